@@ -0,0 +1,100 @@
+package dispatcher
+
+import (
+	"context"
+
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/buf"
+	"github.com/xtls/xray-core/common/dice"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/session"
+	"github.com/xtls/xray-core/features/dns"
+	"github.com/xtls/xray-core/features/routing"
+	"github.com/xtls/xray-core/transport"
+	"github.com/xtls/xray-core/transport/internet/stat"
+)
+
+const http403response = "HTTP/1.1 403 Forbidden\r\nConnection: close\r\nCache-Control: max-age=3600, public\r\nContent-Length: 0\r\n\r\n"
+
+// reject ends the connection on the link in the given way.
+func reject(ctx context.Context, link *transport.Link, rejectType routing.RejectType) {
+	switch rejectType {
+	case routing.RejectReset:
+		if inbound := session.InboundFromContext(ctx); inbound != nil {
+			resetConn(inbound.Conn)
+		}
+	case routing.RejectHTTP403:
+		b := buf.New()
+		b.WriteString(http403response)
+		link.Writer.WriteMultiBuffer(buf.MultiBuffer{b})
+	case routing.RejectDrop:
+		// Never answer, until the client gives up.
+		buf.Copy(link.Reader, buf.Discard)
+	}
+	common.Close(link.Writer)
+	common.Interrupt(link.Reader)
+}
+
+// resetConn makes the TCP connection under conn send a TCP RST when it is closed.
+func resetConn(conn net.Conn) {
+	for conn != nil {
+		switch c := conn.(type) {
+		case *net.TCPConn:
+			c.SetLinger(0)
+			return
+		case *stat.CounterConnection:
+			conn = c.Connection
+		case interface{ NetConn() net.Conn }:
+			conn = c.NetConn()
+		default:
+			return
+		}
+	}
+}
+
+// applyAction rewrites the destination and the session content of the connection by the action.
+func (d *DefaultDispatcher) applyAction(ctx context.Context, action *routing.Action) (context.Context, error) {
+	ob := session.OutboundFromContext(ctx)
+	if action.Address != nil {
+		ob.Target.Address = action.Address
+	}
+	if action.Port != 0 {
+		ob.Target.Port = action.Port
+	}
+
+	if action.DomainStrategy != routing.DomainStrategyAsIs && ob.Target.Address.Family().IsDomain() {
+		domain := ob.Target.Address.Domain()
+		ips, err := d.dns.LookupIP(domain, domainStrategyOption(action.DomainStrategy))
+		switch {
+		case err == nil && len(ips) > 0:
+			ob.Target.Address = net.IPAddress(ips[dice.Roll(len(ips))])
+		case action.DomainStrategy >= routing.DomainStrategyForceIP:
+			return ctx, newError("failed to resolve ", domain).Base(err)
+		default:
+			newError("failed to resolve ", domain, ", keeping the domain").Base(err).AtInfo().WriteToLog(session.ExportIDToError(ctx))
+		}
+	}
+
+	if len(action.Attributes) > 0 {
+		content := session.ContentFromContext(ctx)
+		if content == nil {
+			content = new(session.Content)
+			ctx = session.ContextWithContent(ctx, content)
+		}
+		for key, value := range action.Attributes {
+			content.SetAttribute(key, value)
+		}
+	}
+	return ctx, nil
+}
+
+func domainStrategyOption(strategy routing.DomainStrategy) dns.IPOption {
+	switch strategy {
+	case routing.DomainStrategyUseIPv4, routing.DomainStrategyForceIPv4:
+		return dns.IPOption{IPv4Enable: true}
+	case routing.DomainStrategyUseIPv6, routing.DomainStrategyForceIPv6:
+		return dns.IPOption{IPv6Enable: true}
+	default:
+		return dns.IPOption{IPv4Enable: true, IPv6Enable: true}
+	}
+}
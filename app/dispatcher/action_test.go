@@ -0,0 +1,98 @@
+package dispatcher_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/xtls/xray-core/app/dispatcher"
+	"github.com/xtls/xray-core/app/policy"
+	"github.com/xtls/xray-core/app/proxyman"
+	_ "github.com/xtls/xray-core/app/proxyman/outbound"
+	"github.com/xtls/xray-core/app/router"
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/buf"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/serial"
+	"github.com/xtls/xray-core/core"
+	"github.com/xtls/xray-core/features/routing"
+	"github.com/xtls/xray-core/proxy/freedom"
+	"github.com/xtls/xray-core/testing/servers/tcp"
+	_ "github.com/xtls/xray-core/transport/internet/tcp"
+)
+
+func TestRuleAction(t *testing.T) {
+	tcpServer := tcp.Server{
+		MsgProcessor: bytes.ToUpper,
+	}
+	dest, err := tcpServer.Start()
+	common.Must(err)
+	defer tcpServer.Close()
+
+	v, err := core.New(&core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&dispatcher.Config{}),
+			serial.ToTypedMessage(&proxyman.OutboundConfig{}),
+			serial.ToTypedMessage(&policy.Config{}),
+			serial.ToTypedMessage(&router.Config{
+				Rule: []*router.RoutingRule{
+					{
+						Domain: []*router.Domain{{Type: router.Domain_Full, Value: "blocked.example"}},
+						Action: &router.RuleAction{Reject: router.RuleAction_HTTP403},
+					},
+					{
+						TargetTag: &router.RoutingRule_Tag{
+							Tag: "direct",
+						},
+						Domain: []*router.Domain{{Type: router.Domain_Full, Value: "rewrite.example"}},
+						Action: &router.RuleAction{
+							Address: net.NewIPOrDomain(dest.Address),
+							Port:    uint32(dest.Port),
+						},
+					},
+				},
+			}),
+		},
+		Outbound: []*core.OutboundHandlerConfig{
+			{
+				Tag:           "direct",
+				ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+			},
+		},
+	})
+	common.Must(err)
+	common.Must(v.Start())
+	defer v.Close()
+
+	d := v.GetFeature(routing.DispatcherType()).(routing.Dispatcher)
+
+	link, err := d.Dispatch(context.Background(), net.TCPDestination(net.DomainAddress("blocked.example"), 80))
+	common.Must(err)
+	response, err := buf.ReadAllToBytes(&buf.BufferedReader{Reader: link.Reader})
+	common.Must(err)
+	if !bytes.HasPrefix(response, []byte("HTTP/1.1 403 Forbidden\r\n")) {
+		t.Error("expected 403 response, but got ", string(response))
+	}
+
+	link, err = d.Dispatch(context.Background(), net.TCPDestination(net.DomainAddress("rewrite.example"), 80))
+	common.Must(err)
+	b := buf.New()
+	b.WriteString("hello")
+	common.Must(link.Writer.WriteMultiBuffer(buf.MultiBuffer{b}))
+	mb, err := link.Reader.ReadMultiBuffer()
+	common.Must(err)
+	if s := mb.String(); s != "HELLO" {
+		t.Error("expected rewritten destination to answer, but got ", s)
+	}
+	buf.ReleaseMulti(mb)
+
+	// The connection is listed with the rewritten destination.
+	connections := d.(routing.ConnectionManager).Connections()
+	if len(connections) != 1 {
+		t.Fatal("expected 1 connection, but got ", len(connections))
+	}
+	if c := connections[0]; c.Target != dest || c.OutboundTag != "direct" {
+		t.Error("unexpected connection to ", c.Target, " through ", c.OutboundTag)
+	}
+	common.Close(link.Writer)
+}
@@ -0,0 +1,102 @@
+package command
+
+//go:generate go run github.com/xtls/xray-core/common/errors/errorgen
+
+import (
+	"context"
+	"sort"
+
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/core"
+	"github.com/xtls/xray-core/features/routing"
+	grpc "google.golang.org/grpc"
+)
+
+// connectionServer is an implementation of ConnectionService.
+type connectionServer struct {
+	manager routing.ConnectionManager
+}
+
+func NewConnectionServer(manager routing.ConnectionManager) ConnectionServiceServer {
+	return &connectionServer{
+		manager: manager,
+	}
+}
+
+func (s *connectionServer) ListConnections(ctx context.Context, request *ListConnectionsRequest) (*ListConnectionsResponse, error) {
+	connections := s.manager.Connections()
+	sort.Slice(connections, func(i, j int) bool {
+		if connections[i].StartTime.Equal(connections[j].StartTime) {
+			return connections[i].ID < connections[j].ID
+		}
+		return connections[i].StartTime.Before(connections[j].StartTime)
+	})
+
+	response := &ListConnectionsResponse{}
+	for _, c := range connections {
+		if len(request.InboundTag) > 0 && c.InboundTag != request.InboundTag {
+			continue
+		}
+		if len(request.Email) > 0 && c.Email != request.Email {
+			continue
+		}
+		response.Connections = append(response.Connections, &Connection{
+			Id:          c.ID,
+			InboundTag:  c.InboundTag,
+			Email:       c.Email,
+			Source:      c.Source.NetAddr(),
+			Target:      c.Target.String(),
+			OutboundTag: c.OutboundTag,
+			StartTime:   c.StartTime.Unix(),
+			Uplink:      c.Uplink,
+			Downlink:    c.Downlink,
+		})
+	}
+	return response, nil
+}
+
+func (s *connectionServer) CloseConnections(ctx context.Context, request *CloseConnectionsRequest) (*CloseConnectionsResponse, error) {
+	if len(request.Id) == 0 && len(request.Email) == 0 {
+		return nil, newError("either connection IDs or email must be specified")
+	}
+
+	response := &CloseConnectionsResponse{}
+	for _, id := range request.Id {
+		if s.manager.CloseConnection(id) {
+			response.Closed++
+		}
+	}
+	if len(request.Email) > 0 {
+		response.Closed += uint32(s.manager.CloseUserConnections(request.Email))
+	}
+	return response, nil
+}
+
+func (s *connectionServer) mustEmbedUnimplementedConnectionServiceServer() {}
+
+type service struct {
+	manager routing.ConnectionManager
+}
+
+func (s *service) Register(server *grpc.Server) {
+	if s.manager == nil {
+		return
+	}
+	RegisterConnectionServiceServer(server, NewConnectionServer(s.manager))
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, cfg interface{}) (interface{}, error) {
+		s := new(service)
+
+		core.RequireFeatures(ctx, func(d routing.Dispatcher) {
+			if manager, ok := d.(routing.ConnectionManager); ok {
+				s.manager = manager
+			} else {
+				newError("dispatcher does not track connections").AtWarning().WriteToLog()
+			}
+		})
+
+		return s, nil
+	}))
+}
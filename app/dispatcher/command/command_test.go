@@ -0,0 +1,238 @@
+package command_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/xtls/xray-core/app/dispatcher"
+	. "github.com/xtls/xray-core/app/dispatcher/command"
+	"github.com/xtls/xray-core/app/proxyman"
+	_ "github.com/xtls/xray-core/app/proxyman/outbound"
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/buf"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/protocol"
+	"github.com/xtls/xray-core/common/serial"
+	"github.com/xtls/xray-core/common/session"
+	"github.com/xtls/xray-core/core"
+	"github.com/xtls/xray-core/features/routing"
+	"github.com/xtls/xray-core/proxy/freedom"
+	"github.com/xtls/xray-core/testing/servers/tcp"
+	"github.com/xtls/xray-core/transport"
+	_ "github.com/xtls/xray-core/transport/internet/tcp"
+)
+
+type testManager struct {
+	connections []*routing.Connection
+}
+
+func (m *testManager) Connections() []*routing.Connection {
+	result := make([]*routing.Connection, len(m.connections))
+	copy(result, m.connections)
+	return result
+}
+
+func (m *testManager) CloseConnection(id uint32) bool {
+	for i, c := range m.connections {
+		if c.ID == id {
+			m.connections = append(m.connections[:i], m.connections[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (m *testManager) CloseUserConnections(email string) int {
+	var n int
+	for i := 0; i < len(m.connections); {
+		if m.connections[i].Email == email {
+			m.connections = append(m.connections[:i], m.connections[i+1:]...)
+			n++
+		} else {
+			i++
+		}
+	}
+	return n
+}
+
+func TestListAndCloseConnections(t *testing.T) {
+	now := time.Now()
+	m := &testManager{
+		connections: []*routing.Connection{
+			{
+				ID:          2,
+				InboundTag:  "in",
+				Email:       "a@xray.com",
+				Source:      net.TCPDestination(net.ParseAddress("1.2.3.4"), 1234),
+				Target:      net.TCPDestination(net.ParseAddress("example.com"), 443),
+				OutboundTag: "direct",
+				StartTime:   now,
+				Uplink:      10,
+				Downlink:    20,
+			},
+			{
+				ID:         1,
+				InboundTag: "in",
+				Email:      "b@xray.com",
+				StartTime:  now.Add(-time.Minute),
+			},
+			{
+				ID:         3,
+				InboundTag: "other",
+				Email:      "a@xray.com",
+				StartTime:  now.Add(time.Minute),
+			},
+		},
+	}
+	s := NewConnectionServer(m)
+
+	resp, err := s.ListConnections(context.Background(), &ListConnectionsRequest{})
+	common.Must(err)
+	if len(resp.Connections) != 3 {
+		t.Fatal("expected 3 connections, got ", len(resp.Connections))
+	}
+	for i, id := range []uint32{1, 2, 3} {
+		if resp.Connections[i].Id != id {
+			t.Error("connection ", i, " is ", resp.Connections[i].Id, ", expected ", id)
+		}
+	}
+	c := resp.Connections[1]
+	if c.Source != "1.2.3.4:1234" || c.Target != "tcp:example.com:443" || c.OutboundTag != "direct" ||
+		c.StartTime != now.Unix() || c.Uplink != 10 || c.Downlink != 20 {
+		t.Error("unexpected connection: ", c)
+	}
+
+	resp, err = s.ListConnections(context.Background(), &ListConnectionsRequest{InboundTag: "in", Email: "a@xray.com"})
+	common.Must(err)
+	if len(resp.Connections) != 1 || resp.Connections[0].Id != 2 {
+		t.Error("unexpected connections: ", resp.Connections)
+	}
+
+	if _, err := s.CloseConnections(context.Background(), &CloseConnectionsRequest{}); err == nil {
+		t.Error("expected error on empty request")
+	}
+
+	closed, err := s.CloseConnections(context.Background(), &CloseConnectionsRequest{Id: []uint32{1, 4}})
+	common.Must(err)
+	if closed.Closed != 1 {
+		t.Error("closed ", closed.Closed, " connections by ID, expected 1")
+	}
+
+	closed, err = s.CloseConnections(context.Background(), &CloseConnectionsRequest{Email: "a@xray.com"})
+	common.Must(err)
+	if closed.Closed != 2 {
+		t.Error("closed ", closed.Closed, " connections of user, expected 2")
+	}
+	if len(m.connections) != 0 {
+		t.Error("connections left: ", len(m.connections))
+	}
+}
+
+// dispatch dispatches a connection of the user to the destination, and waits for its first response.
+func dispatch(ctx context.Context, d routing.Dispatcher, dest net.Destination, id session.ID, email string) *transport.Link {
+	ctx = session.ContextWithID(ctx, id)
+	ctx = session.ContextWithInbound(ctx, &session.Inbound{
+		Tag:  "in",
+		User: &protocol.MemoryUser{Email: email},
+	})
+	link, err := d.Dispatch(ctx, dest)
+	common.Must(err)
+	b := buf.New()
+	b.WriteString("hello")
+	common.Must(link.Writer.WriteMultiBuffer(buf.MultiBuffer{b}))
+	mb, err := link.Reader.ReadMultiBuffer()
+	common.Must(err)
+	buf.ReleaseMulti(mb)
+	return link
+}
+
+// listConnections returns the connections listed by the server, by their IDs.
+func listConnections(s ConnectionServiceServer) map[uint32]*Connection {
+	resp, err := s.ListConnections(context.Background(), &ListConnectionsRequest{})
+	common.Must(err)
+	result := make(map[uint32]*Connection)
+	for _, c := range resp.Connections {
+		result[c.Id] = c
+	}
+	return result
+}
+
+// waitForConnections waits until the server lists n connections, and returns the last listed ones.
+func waitForConnections(s ConnectionServiceServer, n int) map[uint32]*Connection {
+	var connections map[uint32]*Connection
+	for i := 0; i < 50; i++ {
+		connections = listConnections(s)
+		if len(connections) == n {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return connections
+}
+
+func TestDispatcherConnections(t *testing.T) {
+	tcpServer := tcp.Server{
+		MsgProcessor: bytes.ToUpper,
+	}
+	dest, err := tcpServer.Start()
+	common.Must(err)
+	defer tcpServer.Close()
+
+	v, err := core.New(&core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&dispatcher.Config{}),
+			serial.ToTypedMessage(&proxyman.OutboundConfig{}),
+		},
+		Outbound: []*core.OutboundHandlerConfig{
+			{
+				Tag:           "direct",
+				ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+			},
+		},
+	})
+	common.Must(err)
+	common.Must(v.Start())
+	defer v.Close()
+
+	d := v.GetFeature(routing.DispatcherType()).(routing.Dispatcher)
+	s := NewConnectionServer(d.(routing.ConnectionManager))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	link1 := dispatch(ctx, d, dest, 1, "a@xray.com")
+	endCtx, end := context.WithCancel(ctx)
+	dispatch(endCtx, d, dest, 2, "b@xray.com")
+
+	connections := waitForConnections(s, 2)
+	if len(connections) != 2 {
+		t.Fatal("expected 2 connections, got ", len(connections))
+	}
+	c := connections[1]
+	if c == nil || c.InboundTag != "in" || c.Email != "a@xray.com" || c.OutboundTag != "direct" ||
+		c.Target != dest.String() || c.Uplink != 5 || c.Downlink != 5 {
+		t.Error("unexpected connection: ", c)
+	}
+	if c := connections[2]; c == nil || c.Email != "b@xray.com" {
+		t.Error("unexpected connection: ", c)
+	}
+
+	closed, err := s.CloseConnections(context.Background(), &CloseConnectionsRequest{Id: []uint32{1}})
+	common.Must(err)
+	if closed.Closed != 1 {
+		t.Error("closed ", closed.Closed, " connections by ID, expected 1")
+	}
+	if _, ok := listConnections(s)[1]; ok {
+		t.Error("connection 1 is listed after it is closed")
+	}
+	if _, err := link1.Reader.ReadMultiBuffer(); err == nil {
+		t.Error("connection 1 is readable after it is closed")
+	}
+
+	end()
+	connections = waitForConnections(s, 0)
+	if len(connections) != 0 {
+		t.Error("connections left after they end: ", len(connections))
+	}
+}
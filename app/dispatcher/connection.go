@@ -0,0 +1,232 @@
+package dispatcher
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/buf"
+	"github.com/xtls/xray-core/common/log"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/session"
+	"github.com/xtls/xray-core/features/routing"
+	"github.com/xtls/xray-core/transport"
+)
+
+// trackedConnection is an active connection tracked by the dispatcher.
+type trackedConnection struct {
+	info     routing.Connection
+	uplink   int64
+	downlink int64
+
+	// link is the original link of the connection, used to close the connection.
+	link *transport.Link
+	// access is the access message recorded when the connection was accepted, if any.
+	access *log.AccessMessage
+	done   func()
+	stop   func() bool
+}
+
+// connectionRegistry keeps track of active connections.
+type connectionRegistry struct {
+	access      sync.Mutex
+	connections map[*trackedConnection]struct{}
+}
+
+// track registers the connection on the given link, and wraps the link to count its traffic. The connection is
+// removed, and release is called if not nil, when either the context is done or the response is finished.
+func (r *connectionRegistry) track(ctx context.Context, link *transport.Link, destination net.Destination, release func()) *trackedConnection {
+	c := &trackedConnection{
+		info: routing.Connection{
+			ID:        uint32(session.IDFromContext(ctx)),
+			Target:    destination,
+			StartTime: time.Now(),
+		},
+		link: &transport.Link{
+			Reader: link.Reader,
+			Writer: link.Writer,
+		},
+	}
+	if inbound := session.InboundFromContext(ctx); inbound != nil {
+		c.info.InboundTag = inbound.Tag
+		c.info.Source = inbound.Source
+		if inbound.User != nil {
+			c.info.Email = inbound.User.Email
+		}
+	}
+
+	var once sync.Once
+	c.done = func() {
+		once.Do(func() {
+			r.access.Lock()
+			delete(r.connections, c)
+			access := c.access
+			r.access.Unlock()
+			if release != nil {
+				release()
+			}
+			if access != nil {
+				c.recordClose(*access)
+			}
+		})
+	}
+
+	r.access.Lock()
+	if r.connections == nil {
+		r.connections = make(map[*trackedConnection]struct{})
+	}
+	r.connections[c] = struct{}{}
+	// The connection ends either when the inbound finishes, or when the outbound finishes the response.
+	c.stop = context.AfterFunc(ctx, c.done)
+	r.access.Unlock()
+
+	link.Reader = &connectionReader{
+		Reader:  link.Reader,
+		counter: &c.uplink,
+	}
+	link.Writer = &connectionWriter{
+		Writer:  link.Writer,
+		counter: &c.downlink,
+		done:    c.finish,
+	}
+	return c
+}
+
+// setOutbound records the outbound of the connection, its target as rewritten by hosts and rule actions, and the
+// access message recorded for it if any.
+func (r *connectionRegistry) setOutbound(c *trackedConnection, tag string, destination net.Destination, access *log.AccessMessage) {
+	r.access.Lock()
+	defer r.access.Unlock()
+
+	c.info.OutboundTag = tag
+	c.info.Target = destination
+	c.access = access
+}
+
+// find returns the connections matching the given filter.
+func (r *connectionRegistry) find(filter func(*trackedConnection) bool) []*trackedConnection {
+	r.access.Lock()
+	defer r.access.Unlock()
+
+	var result []*trackedConnection
+	for c := range r.connections {
+		if filter(c) {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+func (r *connectionRegistry) snapshot() []*routing.Connection {
+	r.access.Lock()
+	defer r.access.Unlock()
+
+	result := make([]*routing.Connection, 0, len(r.connections))
+	for c := range r.connections {
+		info := c.info
+		info.Uplink = atomic.LoadInt64(&c.uplink)
+		info.Downlink = atomic.LoadInt64(&c.downlink)
+		result = append(result, &info)
+	}
+	return result
+}
+
+// finish removes the connection from the registry before its context is done.
+func (c *trackedConnection) finish() {
+	c.stop()
+	c.done()
+}
+
+// recordClose records the access message of the connection again, with its duration and byte counts.
+func (c *trackedConnection) recordClose(msg log.AccessMessage) {
+	msg.Status = log.AccessClosed
+	msg.Reason = nil
+	msg.Duration = time.Since(c.info.StartTime)
+	msg.Uplink = atomic.LoadInt64(&c.uplink)
+	msg.Downlink = atomic.LoadInt64(&c.downlink)
+	log.Record(&msg)
+}
+
+func (c *trackedConnection) close() {
+	common.Interrupt(c.link.Reader)
+	common.Interrupt(c.link.Writer)
+	c.finish()
+}
+
+// Connections implements routing.ConnectionManager.
+func (d *DefaultDispatcher) Connections() []*routing.Connection {
+	return d.connections.snapshot()
+}
+
+// CloseConnection implements routing.ConnectionManager.
+func (d *DefaultDispatcher) CloseConnection(id uint32) bool {
+	found := d.connections.find(func(c *trackedConnection) bool {
+		return c.info.ID == id
+	})
+	for _, c := range found {
+		c.close()
+	}
+	return len(found) > 0
+}
+
+// CloseUserConnections implements routing.ConnectionManager.
+func (d *DefaultDispatcher) CloseUserConnections(email string) int {
+	found := d.connections.find(func(c *trackedConnection) bool {
+		return c.info.Email == email
+	})
+	for _, c := range found {
+		c.close()
+	}
+	return len(found)
+}
+
+// connectionReader counts the bytes read from the uplink of a connection.
+type connectionReader struct {
+	buf.Reader
+	counter *int64
+}
+
+func (r *connectionReader) ReadMultiBuffer() (buf.MultiBuffer, error) {
+	mb, err := r.Reader.ReadMultiBuffer()
+	atomic.AddInt64(r.counter, int64(mb.Len()))
+	return mb, err
+}
+
+func (r *connectionReader) ReadMultiBufferTimeout(timeout time.Duration) (buf.MultiBuffer, error) {
+	tr, ok := r.Reader.(buf.TimeoutReader)
+	if !ok {
+		return r.ReadMultiBuffer()
+	}
+	mb, err := tr.ReadMultiBufferTimeout(timeout)
+	atomic.AddInt64(r.counter, int64(mb.Len()))
+	return mb, err
+}
+
+func (r *connectionReader) Interrupt() {
+	common.Interrupt(r.Reader)
+}
+
+// connectionWriter counts the bytes written to the downlink of a connection, and calls done once the writer is
+// closed or interrupted.
+type connectionWriter struct {
+	buf.Writer
+	counter *int64
+	done    func()
+}
+
+func (w *connectionWriter) WriteMultiBuffer(mb buf.MultiBuffer) error {
+	atomic.AddInt64(w.counter, int64(mb.Len()))
+	return w.Writer.WriteMultiBuffer(mb)
+}
+
+func (w *connectionWriter) Close() error {
+	w.done()
+	return common.Close(w.Writer)
+}
+
+func (w *connectionWriter) Interrupt() {
+	w.done()
+	common.Interrupt(w.Writer)
+}
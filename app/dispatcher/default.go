@@ -18,11 +18,13 @@ import (
 	"github.com/xtls/xray-core/features/dns"
 	"github.com/xtls/xray-core/features/outbound"
 	"github.com/xtls/xray-core/features/policy"
+	"github.com/xtls/xray-core/features/quota"
 	"github.com/xtls/xray-core/features/routing"
 	routing_session "github.com/xtls/xray-core/features/routing/session"
 	"github.com/xtls/xray-core/features/stats"
 	"github.com/xtls/xray-core/transport"
 	"github.com/xtls/xray-core/transport/pipe"
+	"golang.org/x/time/rate"
 )
 
 var errSniffingTimeout = newError("timeout on sniffing")
@@ -94,6 +96,9 @@ type DefaultDispatcher struct {
 	stats  stats.Manager
 	dns    dns.Client
 	fdns   dns.FakeDNSEngine
+	quota  quota.Manager
+
+	connections connectionRegistry
 }
 
 func init() {
@@ -103,6 +108,9 @@ func init() {
 			core.RequireFeatures(ctx, func(fdns dns.FakeDNSEngine) {
 				d.fdns = fdns
 			})
+			core.RequireFeatures(ctx, func(qm quota.Manager) {
+				d.quota = qm
+			})
 			return d.Init(config.(*Config), om, router, pm, sm, dc)
 		}); err != nil {
 			return nil, err
@@ -180,6 +188,83 @@ func (d *DefaultDispatcher) getLink(ctx context.Context) (*transport.Link, *tran
 	return inboundLink, outboundLink
 }
 
+// limitLink applies the rate limits and the traffic quota of the user of the session to the link of a connection,
+// whose reader is the uplink and writer the downlink. It returns a function to be called when the connection ends, or
+// nil if there is none.
+func (d *DefaultDispatcher) limitLink(ctx context.Context, link *transport.Link) func() {
+	sessionInbound := session.InboundFromContext(ctx)
+	if sessionInbound == nil || sessionInbound.User == nil {
+		return nil
+	}
+	user := sessionInbound.User
+
+	var uplink, downlink *rate.Limiter
+	var release func()
+	if rm, ok := d.policy.(policy.RateLimiterManager); ok && len(user.Email) > 0 {
+		uplink, downlink, release = rm.RateLimitersForUser(user.Email, user.Level)
+	} else {
+		p := d.policy.ForLevel(user.Level)
+		uplink = policy.NewRateLimiter(p.RateLimit.Uplink)
+		downlink = policy.NewRateLimiter(p.RateLimit.Downlink)
+	}
+	if uplink != nil {
+		link.Reader = &RateLimitReader{
+			Context: ctx,
+			Limiter: uplink,
+			Reader:  link.Reader,
+		}
+	}
+	if downlink != nil {
+		link.Writer = &RateLimitWriter{
+			Context: ctx,
+			Limiter: downlink,
+			Writer:  link.Writer,
+		}
+	}
+
+	if q := d.quotaForUser(user); q != nil {
+		link.Reader = &QuotaReader{
+			Quota:  q,
+			Reader: link.Reader,
+		}
+		link.Writer = &QuotaWriter{
+			Quota:  q,
+			Writer: link.Writer,
+		}
+	}
+	return release
+}
+
+// admitUser checks whether the user of the session is allowed to open a new connection. On success, it returns
+// a function to be called when the connection ends, or nil if the connection is not tracked.
+func (d *DefaultDispatcher) admitUser(ctx context.Context) (func(), error) {
+	sessionInbound := session.InboundFromContext(ctx)
+	if sessionInbound == nil || sessionInbound.User == nil || len(sessionInbound.User.Email) == 0 {
+		return nil, nil
+	}
+	user := sessionInbound.User
+
+	if q := d.quotaForUser(user); q != nil && q.Exceeded() {
+		return nil, newError("user ", user.Email, " has exceeded the traffic quota").Base(quota.ErrExceeded)
+	}
+
+	if tracker, ok := d.policy.(policy.ConnectionTracker); ok {
+		var source net.Address
+		if sessionInbound.Source.IsValid() {
+			source = sessionInbound.Source.Address
+		}
+		return tracker.TrackConnection(user.Email, user.Level, source)
+	}
+	return nil, nil
+}
+
+func (d *DefaultDispatcher) quotaForUser(user *protocol.MemoryUser) quota.Quota {
+	if d.quota == nil || user == nil || len(user.Email) == 0 {
+		return nil
+	}
+	return d.quota.ForUser(user.Email)
+}
+
 func (d *DefaultDispatcher) shouldOverride(ctx context.Context, result SniffResult, request session.SniffingRequest, destination net.Destination) bool {
 	domain := result.Domain()
 	if domain == "" {
@@ -213,11 +298,28 @@ func (d *DefaultDispatcher) shouldOverride(ctx context.Context, result SniffResu
 	return false
 }
 
+// setClientCertUser sets the email of the user of the inbound to the subject of its client certificate, unless the
+// proxy has authenticated a user with an email.
+func setClientCertUser(ctx context.Context) {
+	inbound := session.InboundFromContext(ctx)
+	if inbound == nil || inbound.ClientCertSubject == "" || (inbound.User != nil && inbound.User.Email != "") {
+		return
+	}
+	// The user may be shared by connections, so it is copied.
+	user := &protocol.MemoryUser{Email: inbound.ClientCertSubject}
+	if inbound.User != nil {
+		user.Account = inbound.User.Account
+		user.Level = inbound.User.Level
+	}
+	inbound.User = user
+}
+
 // Dispatch implements routing.Dispatcher.
 func (d *DefaultDispatcher) Dispatch(ctx context.Context, destination net.Destination) (*transport.Link, error) {
 	if !destination.IsValid() {
 		panic("Dispatcher: Invalid destination.")
 	}
+	setClientCertUser(ctx)
 	ob := session.OutboundFromContext(ctx)
 	if ob == nil {
 		ob = &session.Outbound{}
@@ -274,6 +376,7 @@ func (d *DefaultDispatcher) DispatchLink(ctx context.Context, destination net.De
 	if !destination.IsValid() {
 		return newError("Dispatcher: Invalid destination.")
 	}
+	setClientCertUser(ctx)
 	ob := session.OutboundFromContext(ctx)
 	if ob == nil {
 		ob = &session.Outbound{}
@@ -367,7 +470,35 @@ func sniffer(ctx context.Context, cReader *cachedReader, metadataOnly bool, netw
 	}
 	return contentResult, contentErr
 }
+
+// routedDispatch routes the connection on the link to an outbound.
 func (d *DefaultDispatcher) routedDispatch(ctx context.Context, link *transport.Link, destination net.Destination) {
+	release, err := d.admitUser(ctx)
+	if err != nil {
+		newError("rejecting [", destination, "]").Base(err).AtInfo().WriteToLog(session.ExportIDToError(ctx))
+		if accessMessage := log.AccessMessageFromContext(ctx); accessMessage != nil {
+			accessMessage.Status = log.AccessRejected
+			accessMessage.Reason = err
+			log.Record(accessMessage)
+		}
+		common.Close(link.Writer)
+		common.Interrupt(link.Reader)
+		return
+	}
+	// Links are limited here rather than when created, so that links from DispatchLink are limited and counted as
+	// well, and the sniffer reads from the pipe.
+	if releaseLimits := d.limitLink(ctx, link); releaseLimits != nil {
+		if releaseUser := release; releaseUser != nil {
+			release = func() {
+				releaseLimits()
+				releaseUser()
+			}
+		} else {
+			release = releaseLimits
+		}
+	}
+	conn := d.connections.track(ctx, link, destination, release)
+
 	ob := session.OutboundFromContext(ctx)
 	if hosts, ok := d.dns.(dns.HostsLookup); ok && destination.Address.Family().IsDomain() {
 		proxied := hosts.LookupHosts(ob.Target.String())
@@ -387,6 +518,8 @@ func (d *DefaultDispatcher) routedDispatch(ctx context.Context, link *transport.
 	routingLink := routing_session.AsRoutingContext(ctx)
 	inTag := routingLink.GetInboundTag()
 	isPickRoute := 0
+	var ruleTag string
+	var action *routing.Action
 	if forcedOutboundTag := session.GetForcedOutboundTagFromContext(ctx); forcedOutboundTag != "" {
 		ctx = session.SetForcedOutboundTagToContext(ctx, "")
 		if h := d.ohm.GetHandler(forcedOutboundTag); h != nil {
@@ -402,11 +535,25 @@ func (d *DefaultDispatcher) routedDispatch(ctx context.Context, link *transport.
 	} else if d.router != nil {
 		if route, err := d.router.PickRoute(routingLink); err == nil {
 			outTag := route.GetOutboundTag()
+			ruleTag = route.GetRuleTag()
+			action = route.GetAction()
+			if action != nil && action.Reject != routing.RejectNone {
+				newError("rejecting [", destination, "] by routing rule ", ruleTag).WriteToLog(session.ExportIDToError(ctx))
+				if accessMessage := log.AccessMessageFromContext(ctx); accessMessage != nil {
+					accessMessage.Status = log.AccessRejected
+					accessMessage.Reason = newError("rejected by routing rule")
+					accessMessage.InboundTag = inTag
+					accessMessage.RuleTag = ruleTag
+					log.Record(accessMessage)
+				}
+				reject(ctx, link, action.Reject)
+				return
+			}
 			if h := d.ohm.GetHandler(outTag); h != nil {
 				isPickRoute = 2
 				newError("taking detour [", outTag, "] for [", destination, "]").WriteToLog(session.ExportIDToError(ctx))
 				handler = h
-			} else {
+			} else if len(outTag) > 0 || action == nil {
 				newError("non existing outTag: ", outTag).AtWarning().WriteToLog(session.ExportIDToError(ctx))
 			}
 		} else {
@@ -414,6 +561,24 @@ func (d *DefaultDispatcher) routedDispatch(ctx context.Context, link *transport.
 		}
 	}
 
+	if action != nil {
+		target := ob.Target
+		if ctx, err = d.applyAction(ctx, action); err != nil {
+			newError("rejecting [", destination, "]").Base(err).AtInfo().WriteToLog(session.ExportIDToError(ctx))
+			if accessMessage := log.AccessMessageFromContext(ctx); accessMessage != nil {
+				accessMessage.Status = log.AccessRejected
+				accessMessage.Reason = err
+				log.Record(accessMessage)
+			}
+			common.Close(link.Writer)
+			common.Interrupt(link.Reader)
+			return
+		}
+		if ob.Target != target {
+			destination = ob.Target
+		}
+	}
+
 	if handler == nil {
 		handler = d.ohm.GetDefaultHandler()
 	}
@@ -425,7 +590,19 @@ func (d *DefaultDispatcher) routedDispatch(ctx context.Context, link *transport.
 		return
 	}
 
-	if accessMessage := log.AccessMessageFromContext(ctx); accessMessage != nil {
+	accessMessage := log.AccessMessageFromContext(ctx)
+	if accessMessage != nil {
+		accessMessage.InboundTag = inTag
+		accessMessage.OutboundTag = handler.Tag()
+		accessMessage.RuleTag = ruleTag
+		if content := session.ContentFromContext(ctx); content != nil {
+			accessMessage.Protocol = content.Protocol
+		}
+		if ob.RouteTarget.Address != nil && ob.RouteTarget.Address.Family().IsDomain() {
+			accessMessage.Domain = ob.RouteTarget.Address.Domain()
+		} else if ob.Target.Address != nil && ob.Target.Address.Family().IsDomain() {
+			accessMessage.Domain = ob.Target.Address.Domain()
+		}
 		if tag := handler.Tag(); tag != "" {
 			if inTag == "" {
 				accessMessage.Detour = tag
@@ -440,5 +617,19 @@ func (d *DefaultDispatcher) routedDispatch(ctx context.Context, link *transport.
 		log.Record(accessMessage)
 	}
 
+	if p := d.policy.ForSystem(); len(inTag) > 0 && (p.Stats.InboundUplink || p.Stats.InboundDownlink) {
+		if c, _ := stats.GetOrRegisterGauge(d.stats, "inbound>>>"+inTag+">>>connection>>>active"); c != nil {
+			c.Add(1)
+			defer c.Add(-1)
+		}
+	}
+	if p := d.policy.ForSystem(); len(handler.Tag()) > 0 && (p.Stats.OutboundUplink || p.Stats.OutboundDownlink) {
+		if c, _ := stats.GetOrRegisterGauge(d.stats, "outbound>>>"+handler.Tag()+">>>connection>>>active"); c != nil {
+			c.Add(1)
+			defer c.Add(-1)
+		}
+	}
+
+	d.connections.setOutbound(conn, handler.Tag(), destination, accessMessage)
 	handler.Dispatch(ctx, link)
 }
@@ -0,0 +1,262 @@
+package dispatcher_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/xtls/xray-core/app/dispatcher"
+	"github.com/xtls/xray-core/app/policy"
+	"github.com/xtls/xray-core/app/proxyman"
+	_ "github.com/xtls/xray-core/app/proxyman/outbound"
+	"github.com/xtls/xray-core/app/quota"
+	"github.com/xtls/xray-core/app/stats"
+	"github.com/xtls/xray-core/app/stats/command"
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/buf"
+	"github.com/xtls/xray-core/common/protocol"
+	"github.com/xtls/xray-core/common/serial"
+	"github.com/xtls/xray-core/common/session"
+	"github.com/xtls/xray-core/core"
+	"github.com/xtls/xray-core/features/routing"
+	feature_stats "github.com/xtls/xray-core/features/stats"
+	"github.com/xtls/xray-core/proxy/freedom"
+	"github.com/xtls/xray-core/testing/servers/tcp"
+	"github.com/xtls/xray-core/transport"
+	_ "github.com/xtls/xray-core/transport/internet/tcp"
+	"github.com/xtls/xray-core/transport/pipe"
+)
+
+// waitForCounter waits until the counter has the value, and returns the last one.
+func waitForCounter(m feature_stats.Manager, name string, value int64) int64 {
+	var v int64
+	for i := 0; i < 50; i++ {
+		if c := m.GetCounter(name); c != nil {
+			v = c.Value()
+			if v == value {
+				break
+			}
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return v
+}
+
+func TestActiveConnectionsReset(t *testing.T) {
+	tcpServer := tcp.Server{
+		MsgProcessor: bytes.ToUpper,
+	}
+	dest, err := tcpServer.Start()
+	common.Must(err)
+	defer tcpServer.Close()
+
+	v, err := core.New(&core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&dispatcher.Config{}),
+			serial.ToTypedMessage(&proxyman.OutboundConfig{}),
+			serial.ToTypedMessage(&stats.Config{}),
+			serial.ToTypedMessage(&policy.Config{
+				System: &policy.SystemPolicy{
+					Stats: &policy.SystemPolicy_Stats{
+						OutboundUplink: true,
+					},
+				},
+			}),
+		},
+		Outbound: []*core.OutboundHandlerConfig{
+			{
+				Tag:           "direct",
+				ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+			},
+		},
+	})
+	common.Must(err)
+	common.Must(v.Start())
+	defer v.Close()
+
+	d := v.GetFeature(routing.DispatcherType()).(routing.Dispatcher)
+	m := v.GetFeature(feature_stats.ManagerType()).(feature_stats.Manager)
+	const name = "outbound>>>direct>>>connection>>>active"
+
+	link, err := d.Dispatch(context.Background(), dest)
+	common.Must(err)
+	b := buf.New()
+	b.WriteString("hello")
+	common.Must(link.Writer.WriteMultiBuffer(buf.MultiBuffer{b}))
+	mb, err := link.Reader.ReadMultiBuffer()
+	common.Must(err)
+	buf.ReleaseMulti(mb)
+
+	if n := waitForCounter(m, name, 1); n != 1 {
+		t.Fatal("active connections: ", n)
+	}
+
+	resp, err := command.NewStatsServer(m).GetStats(context.Background(), &command.GetStatsRequest{
+		Name:   name,
+		Reset_: true,
+	})
+	common.Must(err)
+	if resp.Stat.Value != 1 {
+		t.Error("active connections before reset: ", resp.Stat.Value)
+	}
+	if n := m.GetCounter(name).Value(); n != 1 {
+		t.Error("active connections after reset: ", n)
+	}
+
+	common.Close(link.Writer)
+	common.Interrupt(link.Reader)
+	if n := waitForCounter(m, name, 0); n != 0 {
+		t.Error("active connections after close: ", n)
+	}
+}
+
+func TestQuotaExceeded(t *testing.T) {
+	tcpServer := tcp.Server{
+		MsgProcessor: bytes.ToUpper,
+	}
+	dest, err := tcpServer.Start()
+	common.Must(err)
+	defer tcpServer.Close()
+
+	v, err := core.New(&core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&dispatcher.Config{}),
+			serial.ToTypedMessage(&proxyman.OutboundConfig{}),
+			serial.ToTypedMessage(&quota.Config{
+				User: []*quota.UserQuota{
+					{Email: "test@example.com", Bytes: 15},
+				},
+			}),
+		},
+		Outbound: []*core.OutboundHandlerConfig{
+			{
+				ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+			},
+		},
+	})
+	common.Must(err)
+	common.Must(v.Start())
+	defer v.Close()
+
+	d := v.GetFeature(routing.DispatcherType()).(routing.Dispatcher)
+	ctx := session.ContextWithInbound(context.Background(), &session.Inbound{
+		User: &protocol.MemoryUser{Email: "test@example.com"},
+	})
+	write := func(w buf.Writer) {
+		b := buf.New()
+		b.WriteString("hello")
+		common.Must(w.WriteMultiBuffer(buf.MultiBuffer{b}))
+	}
+
+	// The request and the response use 10 bytes of the quota, and the connection is left idle.
+	idle, err := d.Dispatch(ctx, dest)
+	common.Must(err)
+	write(idle.Writer)
+	mb, err := idle.Reader.ReadMultiBuffer()
+	common.Must(err)
+	if mb.String() != "HELLO" {
+		t.Error("response: ", mb.String())
+	}
+	buf.ReleaseMulti(mb)
+
+	// The traffic of links from DispatchLink uses up the quota.
+	uplinkReader, uplinkWriter := pipe.New()
+	downlinkReader, downlinkWriter := pipe.New()
+	go d.DispatchLink(ctx, dest, &transport.Link{
+		Reader: uplinkReader,
+		Writer: downlinkWriter,
+	})
+	write(uplinkWriter)
+	if _, err := downlinkReader.ReadMultiBuffer(); err == nil {
+		t.Error("response over the quota is not refused")
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := idle.Reader.ReadMultiBuffer()
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("idle connection is readable after the quota is used up")
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("idle connection is not closed after the quota is used up")
+	}
+
+	link, err := d.Dispatch(ctx, dest)
+	common.Must(err)
+	if _, err := link.Reader.ReadMultiBuffer(); err == nil {
+		t.Error("new connection of the user over quota is not rejected")
+	}
+}
+
+func TestDispatchLinkRateLimit(t *testing.T) {
+	tcpServer := tcp.Server{
+		MsgProcessor: bytes.ToUpper,
+	}
+	dest, err := tcpServer.Start()
+	common.Must(err)
+	defer tcpServer.Close()
+
+	v, err := core.New(&core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&dispatcher.Config{}),
+			serial.ToTypedMessage(&proxyman.OutboundConfig{}),
+			serial.ToTypedMessage(&policy.Config{
+				Level: map[uint32]*policy.Policy{
+					0: {
+						RateLimit: &policy.Policy_RateLimit{
+							Uplink: 16 * 1024,
+						},
+					},
+				},
+			}),
+		},
+		Outbound: []*core.OutboundHandlerConfig{
+			{
+				ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+			},
+		},
+	})
+	common.Must(err)
+	common.Must(v.Start())
+	defer v.Close()
+
+	d := v.GetFeature(routing.DispatcherType()).(routing.Dispatcher)
+	ctx := session.ContextWithInbound(context.Background(), &session.Inbound{
+		User: &protocol.MemoryUser{Email: "test@example.com"},
+	})
+	uplinkReader, uplinkWriter := pipe.New()
+	downlinkReader, downlinkWriter := pipe.New()
+	go d.DispatchLink(ctx, dest, &transport.Link{
+		Reader: uplinkReader,
+		Writer: downlinkWriter,
+	})
+	defer common.Interrupt(downlinkReader)
+
+	// The first 32 KB are the burst of the limiter, and the rest takes 2 seconds.
+	const size = 64 * 1024
+	start := time.Now()
+	go func() {
+		payload := make([]byte, size)
+		for i := 0; i < size; i += buf.Size {
+			b := buf.New()
+			b.Write(payload[i : i+buf.Size])
+			if err := uplinkWriter.WriteMultiBuffer(buf.MultiBuffer{b}); err != nil {
+				return
+			}
+		}
+	}()
+	for n := 0; n < size; {
+		mb, err := downlinkReader.ReadMultiBuffer()
+		common.Must(err)
+		n += int(mb.Len())
+		buf.ReleaseMulti(mb)
+	}
+	if elapsed := time.Since(start); elapsed < time.Second*3/2 {
+		t.Error("uplink of DispatchLink is not rate limited, took ", elapsed)
+	}
+}
@@ -0,0 +1,63 @@
+package dispatcher
+
+import (
+	"time"
+
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/buf"
+	"github.com/xtls/xray-core/features/quota"
+)
+
+// QuotaWriter is a buf.Writer which consumes the traffic quota of a user, and fails once the quota is exceeded.
+type QuotaWriter struct {
+	Quota  quota.Quota
+	Writer buf.Writer
+}
+
+func (w *QuotaWriter) WriteMultiBuffer(mb buf.MultiBuffer) error {
+	if err := w.Quota.Consume(int64(mb.Len())); err != nil {
+		buf.ReleaseMulti(mb)
+		return err
+	}
+	return w.Writer.WriteMultiBuffer(mb)
+}
+
+func (w *QuotaWriter) Close() error {
+	return common.Close(w.Writer)
+}
+
+func (w *QuotaWriter) Interrupt() {
+	common.Interrupt(w.Writer)
+}
+
+// QuotaReader is a buf.Reader which consumes the traffic quota of a user, and fails once the quota is exceeded.
+type QuotaReader struct {
+	Quota  quota.Quota
+	Reader buf.Reader
+}
+
+func (r *QuotaReader) ReadMultiBuffer() (buf.MultiBuffer, error) {
+	mb, err := r.Reader.ReadMultiBuffer()
+	return r.consume(mb, err)
+}
+
+func (r *QuotaReader) ReadMultiBufferTimeout(timeout time.Duration) (buf.MultiBuffer, error) {
+	tr, ok := r.Reader.(buf.TimeoutReader)
+	if !ok {
+		return r.ReadMultiBuffer()
+	}
+	mb, err := tr.ReadMultiBufferTimeout(timeout)
+	return r.consume(mb, err)
+}
+
+func (r *QuotaReader) consume(mb buf.MultiBuffer, err error) (buf.MultiBuffer, error) {
+	if quotaErr := r.Quota.Consume(int64(mb.Len())); quotaErr != nil {
+		buf.ReleaseMulti(mb)
+		return nil, quotaErr
+	}
+	return mb, err
+}
+
+func (r *QuotaReader) Interrupt() {
+	common.Interrupt(r.Reader)
+}
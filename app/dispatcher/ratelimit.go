@@ -0,0 +1,85 @@
+package dispatcher
+
+import (
+	"context"
+	"time"
+
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/buf"
+	"golang.org/x/time/rate"
+)
+
+// RateLimitWriter is a buf.Writer which waits on the Limiter before writing, to limit the speed of a link.
+type RateLimitWriter struct {
+	Context context.Context
+	Limiter *rate.Limiter
+	Writer  buf.Writer
+}
+
+func (w *RateLimitWriter) WriteMultiBuffer(mb buf.MultiBuffer) error {
+	if err := waitRateLimit(w.Context, w.Limiter, mb); err != nil {
+		buf.ReleaseMulti(mb)
+		return err
+	}
+	return w.Writer.WriteMultiBuffer(mb)
+}
+
+func (w *RateLimitWriter) Close() error {
+	return common.Close(w.Writer)
+}
+
+func (w *RateLimitWriter) Interrupt() {
+	common.Interrupt(w.Writer)
+}
+
+// RateLimitReader is a buf.Reader which waits on the Limiter after reading, to limit the speed of a link.
+type RateLimitReader struct {
+	Context context.Context
+	Limiter *rate.Limiter
+	Reader  buf.Reader
+}
+
+func (r *RateLimitReader) ReadMultiBuffer() (buf.MultiBuffer, error) {
+	mb, err := r.Reader.ReadMultiBuffer()
+	return r.wait(mb, err)
+}
+
+func (r *RateLimitReader) ReadMultiBufferTimeout(timeout time.Duration) (buf.MultiBuffer, error) {
+	tr, ok := r.Reader.(buf.TimeoutReader)
+	if !ok {
+		return r.ReadMultiBuffer()
+	}
+	mb, err := tr.ReadMultiBufferTimeout(timeout)
+	return r.wait(mb, err)
+}
+
+func (r *RateLimitReader) wait(mb buf.MultiBuffer, err error) (buf.MultiBuffer, error) {
+	if waitErr := waitRateLimit(r.Context, r.Limiter, mb); waitErr != nil {
+		buf.ReleaseMulti(mb)
+		return nil, waitErr
+	}
+	return mb, err
+}
+
+func (r *RateLimitReader) Interrupt() {
+	common.Interrupt(r.Reader)
+}
+
+// waitRateLimit waits until the limiter allows the bytes of the buffer.
+func waitRateLimit(ctx context.Context, limiter *rate.Limiter, mb buf.MultiBuffer) error {
+	if limiter.Limit() == rate.Inf {
+		return nil
+	}
+	// WaitN fails on n larger than the burst, so the buffer is waited for in chunks.
+	for n := int(mb.Len()); n > 0; {
+		size := n
+		if burst := limiter.Burst(); size > burst {
+			size = burst
+		}
+		if err := limiter.WaitN(ctx, size); err != nil {
+			return err
+		}
+		n -= size
+	}
+	return nil
+}
@@ -0,0 +1,337 @@
+package dns
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/core"
+	dns_feature "github.com/xtls/xray-core/features/dns"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+const (
+	// prefetchRatio is the fraction of the TTL left, below which a record is prefetched when queried.
+	prefetchRatio = 10
+	// refreshInterval is the minimum interval between refreshes of the same domain.
+	refreshInterval = time.Second * 5
+	// staleTTL is the TTL of stale records in responses (RFC8767 4).
+	staleTTL = 30
+)
+
+// cacheOptions configures the record cache of a name server.
+type cacheOptions struct {
+	// size is the maximum number of cached domains, or unlimited if 0.
+	size int
+	// maxStale is how long expired records are still served while they are refreshed (RFC8767), or never if 0.
+	maxStale time.Duration
+	// prefetch refreshes records in the background when they are queried near their expiry.
+	prefetch bool
+}
+
+func (c *Config) cacheOptions() cacheOptions {
+	return cacheOptions{
+		size:     int(c.CacheSize),
+		maxStale: time.Duration(c.ServeStale) * time.Second,
+		prefetch: c.Prefetch,
+	}
+}
+
+type cacheEntry struct {
+	domain string
+	A      *IPRecord
+	AAAA   *IPRecord
+	// ttlA and ttlAAAA are the TTLs of the records when cached.
+	ttlA    time.Duration
+	ttlAAAA time.Duration
+	// raw are the responses of queries of other types.
+	raw map[dnsmessage.Type]*rawRecord
+	// refreshed is when the records were last refreshed in the background.
+	refreshed time.Time
+}
+
+// rawRecord is a cached response of a query of any type.
+type rawRecord struct {
+	msg    *dnsmessage.Message
+	stored time.Time
+	expire time.Time
+}
+
+func (e *cacheEntry) empty() bool {
+	return e.A == nil && e.AAAA == nil && len(e.raw) == 0
+}
+
+// recordCache caches the records of domains, and evicts the least recently used ones when full.
+type recordCache struct {
+	sync.Mutex
+	options cacheOptions
+	entries map[string]*list.Element
+	lru     *list.List
+}
+
+func newRecordCache() *recordCache {
+	return &recordCache{
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+func (c *recordCache) setOptions(options cacheOptions) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.options = options
+	c.evict()
+}
+
+// set caches the record of the given type, if it is newer than the cached one. It returns whether it is cached.
+func (c *recordCache) set(domain string, reqType dnsmessage.Type, rec *IPRecord) bool {
+	c.Lock()
+	defer c.Unlock()
+
+	entry := c.entry(domain)
+	ttl := time.Until(rec.Expire)
+	switch reqType {
+	case dnsmessage.TypeA:
+		if !isNewer(entry.A, rec) {
+			return false
+		}
+		entry.A, entry.ttlA = rec, ttl
+	case dnsmessage.TypeAAAA:
+		if !isNewer(entry.AAAA, rec) {
+			return false
+		}
+		entry.AAAA, entry.ttlAAAA = rec, ttl
+	default:
+		return false
+	}
+	return true
+}
+
+// entry returns the entry of the domain, which is created if not found. The lock must be held.
+func (c *recordCache) entry(domain string) *cacheEntry {
+	if elem, found := c.entries[domain]; found {
+		c.lru.MoveToFront(elem)
+		return elem.Value.(*cacheEntry)
+	}
+	entry := &cacheEntry{domain: domain}
+	c.entries[domain] = c.lru.PushFront(entry)
+	c.evict()
+	return entry
+}
+
+// setRaw caches the response of the query of the given type. Only successful and NXDOMAIN responses are cached.
+func (c *recordCache) setRaw(domain string, qType dnsmessage.Type, msg *dnsmessage.Message) {
+	if msg.RCode != dnsmessage.RCodeSuccess && msg.RCode != dnsmessage.RCodeNameError {
+		return
+	}
+	now := time.Now()
+	rec := &rawRecord{
+		msg:    msg,
+		stored: now,
+		expire: now.Add(time.Duration(minTTL(msg)) * time.Second),
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	entry := c.entry(domain)
+	if entry.raw == nil {
+		entry.raw = make(map[dnsmessage.Type]*rawRecord)
+	}
+	entry.raw[qType] = rec
+}
+
+// getRaw returns the cached response of the query of the given type, with TTLs reduced by the time cached. Stale
+// responses are only returned if allowStale is true. The returned bool reports whether the response should be
+// refreshed in the background.
+func (c *recordCache) getRaw(domain string, qType dnsmessage.Type, allowStale bool) (*dnsmessage.Message, bool, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	elem, found := c.entries[domain]
+	if !found {
+		return nil, false, errRecordNotFound
+	}
+	entry := elem.Value.(*cacheEntry)
+	rec, found := entry.raw[qType]
+	if !found {
+		return nil, false, errRecordNotFound
+	}
+	c.lru.MoveToFront(elem)
+
+	now := time.Now()
+	maxStale := time.Duration(0)
+	if allowStale {
+		maxStale = c.options.maxStale
+	}
+	if rec.expire.Add(maxStale).Before(now) {
+		return nil, false, errRecordNotFound
+	}
+
+	left := rec.expire.Sub(now)
+	refresh := false
+	if (left < 0 || (c.options.prefetch && left < rec.expire.Sub(rec.stored)/prefetchRatio)) && now.Sub(entry.refreshed) > refreshInterval {
+		entry.refreshed = now
+		refresh = true
+	}
+
+	elapsed := uint32(now.Sub(rec.stored) / time.Second)
+	msg := *rec.msg
+	msg.Answers = agedResources(rec.msg.Answers, elapsed, left < 0)
+	msg.Authorities = agedResources(rec.msg.Authorities, elapsed, left < 0)
+	msg.Additionals = agedResources(rec.msg.Additionals, elapsed, left < 0)
+	return &msg, refresh, nil
+}
+
+// agedResources returns a copy of the resources, with TTLs reduced by elapsed seconds.
+func agedResources(resources []dnsmessage.Resource, elapsed uint32, stale bool) []dnsmessage.Resource {
+	if len(resources) == 0 {
+		return nil
+	}
+	aged := make([]dnsmessage.Resource, len(resources))
+	copy(aged, resources)
+	for i := range aged {
+		if aged[i].Header.Type == dnsmessage.TypeOPT {
+			continue
+		}
+		switch {
+		case stale:
+			aged[i].Header.TTL = staleTTL
+		case aged[i].Header.TTL > elapsed:
+			aged[i].Header.TTL -= elapsed
+		default:
+			aged[i].Header.TTL = 0
+		}
+	}
+	return aged
+}
+
+// minTTL returns the minimum TTL of the records in the response. The TTL of negative responses is from the SOA
+// record (RFC2308 5).
+func minTTL(msg *dnsmessage.Message) uint32 {
+	var ttl uint32 = 600
+	for _, r := range msg.Answers {
+		if r.Header.TTL < ttl {
+			ttl = r.Header.TTL
+		}
+	}
+	for _, r := range msg.Authorities {
+		if soa, ok := r.Body.(*dnsmessage.SOAResource); ok {
+			if r.Header.TTL < ttl {
+				ttl = r.Header.TTL
+			}
+			if soa.MinTTL < ttl {
+				ttl = soa.MinTTL
+			}
+		}
+	}
+	return ttl
+}
+
+func (c *recordCache) evict() {
+	for c.options.size > 0 && c.lru.Len() > c.options.size {
+		entry := c.lru.Remove(c.lru.Back()).(*cacheEntry)
+		delete(c.entries, entry.domain)
+	}
+}
+
+// get returns the cached IPs of the domain. Stale records are only returned if allowStale is true. The returned
+// bool reports whether the records should be refreshed in the background, because they are stale or about to expire.
+func (c *recordCache) get(domain string, option dns_feature.IPOption, allowStale bool) ([]net.IP, bool, error) {
+	c.Lock()
+	defer c.Unlock()
+
+	elem, found := c.entries[domain]
+	if !found {
+		return nil, false, errRecordNotFound
+	}
+	c.lru.MoveToFront(elem)
+	entry := elem.Value.(*cacheEntry)
+
+	now := time.Now()
+	maxStale := time.Duration(0)
+	if allowStale {
+		maxStale = c.options.maxStale
+	}
+
+	var err4, err6 error
+	var ips, ip6 []net.Address
+	var refresh4, refresh6 bool
+	if option.IPv4Enable {
+		ips, refresh4, err4 = c.getIPs(entry.A, entry.ttlA, now, maxStale)
+	}
+	if option.IPv6Enable {
+		ip6, refresh6, err6 = c.getIPs(entry.AAAA, entry.ttlAAAA, now, maxStale)
+		ips = append(ips, ip6...)
+	}
+
+	refresh := false
+	if (refresh4 || refresh6) && now.Sub(entry.refreshed) > refreshInterval {
+		entry.refreshed = now
+		refresh = true
+	}
+
+	if len(ips) > 0 {
+		netIPs, err := toNetIP(ips)
+		return netIPs, refresh, err
+	}
+	if err4 != nil {
+		return nil, refresh, err4
+	}
+	if err6 != nil {
+		return nil, refresh, err6
+	}
+	return nil, refresh, dns_feature.ErrEmptyResponse
+}
+
+func (c *recordCache) getIPs(rec *IPRecord, ttl time.Duration, now time.Time, maxStale time.Duration) ([]net.Address, bool, error) {
+	if rec == nil || rec.Expire.Add(maxStale).Before(now) {
+		return nil, false, errRecordNotFound
+	}
+	left := rec.Expire.Sub(now)
+	refresh := left < 0 || (c.options.prefetch && left < ttl/prefetchRatio)
+	if rec.RCode != dnsmessage.RCodeSuccess {
+		return nil, refresh, dns_feature.RCodeError(rec.RCode)
+	}
+	return rec.IP, refresh, nil
+}
+
+// cleanup removes the records that can no longer be served, and returns the number of cached domains.
+func (c *recordCache) cleanup(name string) int {
+	c.Lock()
+	defer c.Unlock()
+
+	deadline := time.Now().Add(-c.options.maxStale)
+	for domain, elem := range c.entries {
+		entry := elem.Value.(*cacheEntry)
+		if entry.A != nil && entry.A.Expire.Before(deadline) {
+			entry.A = nil
+		}
+		if entry.AAAA != nil && entry.AAAA.Expire.Before(deadline) {
+			entry.AAAA = nil
+		}
+		for qType, rec := range entry.raw {
+			if rec.expire.Before(deadline) {
+				delete(entry.raw, qType)
+			}
+		}
+
+		if entry.empty() {
+			newError(name, " cleanup ", domain).AtDebug().WriteToLog()
+			c.lru.Remove(elem)
+			delete(c.entries, domain)
+		}
+	}
+	return c.lru.Len()
+}
+
+// refreshContext returns the context to refresh records in, which outlives the query that triggers the refresh.
+func refreshContext(ctx context.Context) context.Context {
+	if core.FromContext(ctx) == nil {
+		return context.Background()
+	}
+	return core.ToBackgroundDetachedContext(ctx)
+}
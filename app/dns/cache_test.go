@@ -0,0 +1,130 @@
+package dns
+
+import (
+	"testing"
+	"time"
+
+	"github.com/xtls/xray-core/common/net"
+	dns_feature "github.com/xtls/xray-core/features/dns"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+func newTestRecord(ip string, ttl time.Duration) *IPRecord {
+	return &IPRecord{
+		IP:     []net.Address{net.ParseAddress(ip)},
+		Expire: time.Now().Add(ttl),
+		RCode:  dnsmessage.RCodeSuccess,
+	}
+}
+
+func TestRecordCacheEviction(t *testing.T) {
+	cache := newRecordCache()
+	cache.setOptions(cacheOptions{size: 2})
+	option := dns_feature.IPOption{IPv4Enable: true}
+
+	cache.set("a.com.", dnsmessage.TypeA, newTestRecord("1.1.1.1", time.Minute))
+	cache.set("b.com.", dnsmessage.TypeA, newTestRecord("2.2.2.2", time.Minute))
+	// Use a.com. so that b.com. is the least recently used.
+	if _, _, err := cache.get("a.com.", option, true); err != nil {
+		t.Fatal(err)
+	}
+	cache.set("c.com.", dnsmessage.TypeA, newTestRecord("3.3.3.3", time.Minute))
+
+	if _, _, err := cache.get("b.com.", option, true); err != errRecordNotFound {
+		t.Error("expected b.com. to be evicted, but got ", err)
+	}
+	for _, domain := range []string{"a.com.", "c.com."} {
+		if _, _, err := cache.get(domain, option, true); err != nil {
+			t.Error("expected ", domain, " to be cached, but got ", err)
+		}
+	}
+}
+
+func TestRecordCacheServeStale(t *testing.T) {
+	cache := newRecordCache()
+	option := dns_feature.IPOption{IPv4Enable: true}
+	cache.set("a.com.", dnsmessage.TypeA, newTestRecord("1.1.1.1", -time.Minute))
+
+	if _, _, err := cache.get("a.com.", option, true); err != errRecordNotFound {
+		t.Error("expected expired record not to be served, but got ", err)
+	}
+
+	cache.setOptions(cacheOptions{maxStale: time.Hour})
+	ips, refresh, err := cache.get("a.com.", option, true)
+	if err != nil || len(ips) != 1 || ips[0].String() != "1.1.1.1" {
+		t.Fatal("expected stale record, but got ", ips, err)
+	}
+	if !refresh {
+		t.Error("expected stale record to be refreshed")
+	}
+	if _, refresh, _ := cache.get("a.com.", option, true); refresh {
+		t.Error("expected only one refresh within the refresh interval")
+	}
+	if _, _, err := cache.get("a.com.", option, false); err != errRecordNotFound {
+		t.Error("expected stale record not to be served when not allowed, but got ", err)
+	}
+	if n := cache.cleanup("test"); n != 1 {
+		t.Error("expected stale record to be kept by cleanup, but got ", n)
+	}
+
+	cache.setOptions(cacheOptions{maxStale: time.Second})
+	if n := cache.cleanup("test"); n != 0 {
+		t.Error("expected record to be cleaned up after the stale window, but got ", n)
+	}
+}
+
+func TestRecordCachePrefetch(t *testing.T) {
+	cache := newRecordCache()
+	option := dns_feature.IPOption{IPv4Enable: true}
+	cache.set("a.com.", dnsmessage.TypeA, newTestRecord("1.1.1.1", time.Minute))
+
+	cache.setOptions(cacheOptions{prefetch: true})
+	if _, refresh, _ := cache.get("a.com.", option, true); refresh {
+		t.Error("expected fresh record not to be prefetched")
+	}
+
+	// Pretend the record was cached with a longer TTL, so that it is about to expire.
+	cache.entries["a.com."].Value.(*cacheEntry).ttlA = time.Hour
+	if _, refresh, _ := cache.get("a.com.", option, true); !refresh {
+		t.Error("expected record about to expire to be prefetched")
+	}
+}
+
+func TestRecordCacheRaw(t *testing.T) {
+	cache := newRecordCache()
+	cache.setOptions(cacheOptions{maxStale: time.Hour})
+
+	name := dnsmessage.MustNewName("a.com.")
+	cache.setRaw("a.com.", dnsmessage.TypeMX, &dnsmessage.Message{
+		Answers: []dnsmessage.Resource{{
+			Header: dnsmessage.ResourceHeader{Name: name, Type: dnsmessage.TypeMX, Class: dnsmessage.ClassINET, TTL: 300},
+			Body:   &dnsmessage.MXResource{Pref: 10, MX: name},
+		}},
+	})
+	cache.setRaw("a.com.", dnsmessage.TypeTXT, &dnsmessage.Message{
+		Header: dnsmessage.Header{RCode: dnsmessage.RCodeServerFailure},
+	})
+
+	msg, refresh, err := cache.getRaw("a.com.", dnsmessage.TypeMX, true)
+	if err != nil || len(msg.Answers) != 1 || refresh {
+		t.Fatal("expected cached MX record, but got ", msg, refresh, err)
+	}
+	if _, _, err := cache.getRaw("a.com.", dnsmessage.TypeTXT, true); err != errRecordNotFound {
+		t.Error("expected server failure not to be cached, but got ", err)
+	}
+
+	// Pretend the record was cached 10 minutes ago.
+	rec := cache.entries["a.com."].Value.(*cacheEntry).raw[dnsmessage.TypeMX]
+	rec.stored = rec.stored.Add(-10 * time.Minute)
+	rec.expire = rec.expire.Add(-10 * time.Minute)
+	msg, refresh, err = cache.getRaw("a.com.", dnsmessage.TypeMX, true)
+	if err != nil || !refresh {
+		t.Fatal("expected stale MX record to be refreshed, but got ", refresh, err)
+	}
+	if ttl := msg.Answers[0].Header.TTL; ttl != staleTTL {
+		t.Error("expected TTL of stale record to be ", staleTTL, ", but got ", ttl)
+	}
+	if ttl := rec.msg.Answers[0].Header.TTL; ttl != 300 {
+		t.Error("expected cached record not to be modified, but got TTL ", ttl)
+	}
+}
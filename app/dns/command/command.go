@@ -0,0 +1,138 @@
+package command
+
+//go:generate go run github.com/xtls/xray-core/common/errors/errorgen
+
+import (
+	"context"
+	"strings"
+
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/core"
+	"github.com/xtls/xray-core/features/dns"
+	grpc "google.golang.org/grpc"
+)
+
+// DNSServer is an implementation of DNSService.
+type DNSServer struct {
+	V *core.Instance
+}
+
+func (s *DNSServer) fakeDNS() (dns.FakeDNSMappingManager, error) {
+	engine := s.V.GetFeature((*dns.FakeDNSEngine)(nil))
+	if engine == nil {
+		return nil, newError("fake DNS is not enabled")
+	}
+	manager, ok := engine.(dns.FakeDNSMappingManager)
+	if !ok {
+		return nil, newError("fake DNS does not support inspecting mappings")
+	}
+	return manager, nil
+}
+
+// ListFakeDNS implements DNSService.
+func (s *DNSServer) ListFakeDNS(ctx context.Context, request *ListFakeDNSRequest) (*ListFakeDNSResponse, error) {
+	manager, err := s.fakeDNS()
+	if err != nil {
+		return nil, err
+	}
+
+	response := &ListFakeDNSResponse{}
+	for _, m := range manager.FakeDNSMappings() {
+		if len(request.Domain) > 0 && !strings.Contains(m.Domain, request.Domain) {
+			continue
+		}
+		response.Mappings = append(response.Mappings, &FakeDNSMapping{
+			Domain: m.Domain,
+			Ip:     m.IP.String(),
+		})
+	}
+	return response, nil
+}
+
+// FlushFakeDNS implements DNSService.
+func (s *DNSServer) FlushFakeDNS(ctx context.Context, request *FlushFakeDNSRequest) (*FlushFakeDNSResponse, error) {
+	manager, err := s.fakeDNS()
+	if err != nil {
+		return nil, err
+	}
+	return &FlushFakeDNSResponse{
+		Flushed: uint32(manager.FlushFakeDNS()),
+	}, nil
+}
+
+// ListQueries implements DNSService.
+func (s *DNSServer) ListQueries(ctx context.Context, request *ListQueriesRequest) (*ListQueriesResponse, error) {
+	logger, ok := s.V.GetFeature(dns.ClientType()).(dns.QueryLogger)
+	if !ok {
+		return nil, newError("DNS does not support listing queries")
+	}
+
+	response := &ListQueriesResponse{}
+	for _, r := range logger.RecentQueries() {
+		if request.Limit > 0 && len(response.Queries) >= int(request.Limit) {
+			break
+		}
+		if len(request.Domain) > 0 && !strings.Contains(r.Domain, request.Domain) {
+			continue
+		}
+		if len(request.Server) > 0 && r.Server != request.Server {
+			continue
+		}
+		record := &QueryRecord{
+			Time:     r.Time.UnixMilli(),
+			Domain:   r.Domain,
+			Type:     r.Type,
+			Server:   r.Server,
+			Elapsed:  r.Elapsed.Milliseconds(),
+			CacheHit: r.CacheHit,
+			Answers:  r.Answers,
+		}
+		if r.Client.IsValid() {
+			record.Client = r.Client.NetAddr()
+		}
+		if r.Error != nil {
+			record.Error = r.Error.Error()
+		}
+		response.Queries = append(response.Queries, record)
+	}
+	return response, nil
+}
+
+// ListServers implements DNSService.
+func (s *DNSServer) ListServers(ctx context.Context, request *ListServersRequest) (*ListServersResponse, error) {
+	reporter, ok := s.V.GetFeature(dns.ClientType()).(dns.HealthReporter)
+	if !ok {
+		return nil, newError("DNS does not support listing servers")
+	}
+
+	response := &ListServersResponse{}
+	for _, h := range reporter.ServerHealth() {
+		response.Servers = append(response.Servers, &ServerStatus{
+			Server:      h.Server,
+			Healthy:     h.Healthy,
+			Failures:    uint32(h.Failures),
+			SuccessRate: h.SuccessRate,
+			Latency:     h.Latency.Milliseconds(),
+		})
+	}
+	return response, nil
+}
+
+func (s *DNSServer) mustEmbedUnimplementedDNSServiceServer() {}
+
+type service struct {
+	v *core.Instance
+}
+
+func (s *service) Register(server *grpc.Server) {
+	RegisterDNSServiceServer(server, &DNSServer{
+		V: s.v,
+	})
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, cfg interface{}) (interface{}, error) {
+		s := core.MustFromContext(ctx)
+		return &service{v: s}, nil
+	}))
+}
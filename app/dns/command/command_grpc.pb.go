@@ -0,0 +1,220 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v4.23.1
+// source: app/dns/command/command.proto
+
+package command
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	DNSService_ListFakeDNS_FullMethodName  = "/xray.app.dns.command.DNSService/ListFakeDNS"
+	DNSService_FlushFakeDNS_FullMethodName = "/xray.app.dns.command.DNSService/FlushFakeDNS"
+	DNSService_ListQueries_FullMethodName  = "/xray.app.dns.command.DNSService/ListQueries"
+	DNSService_ListServers_FullMethodName  = "/xray.app.dns.command.DNSService/ListServers"
+)
+
+// DNSServiceClient is the client API for DNSService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type DNSServiceClient interface {
+	ListFakeDNS(ctx context.Context, in *ListFakeDNSRequest, opts ...grpc.CallOption) (*ListFakeDNSResponse, error)
+	FlushFakeDNS(ctx context.Context, in *FlushFakeDNSRequest, opts ...grpc.CallOption) (*FlushFakeDNSResponse, error)
+	ListQueries(ctx context.Context, in *ListQueriesRequest, opts ...grpc.CallOption) (*ListQueriesResponse, error)
+	ListServers(ctx context.Context, in *ListServersRequest, opts ...grpc.CallOption) (*ListServersResponse, error)
+}
+
+type dNSServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewDNSServiceClient(cc grpc.ClientConnInterface) DNSServiceClient {
+	return &dNSServiceClient{cc}
+}
+
+func (c *dNSServiceClient) ListFakeDNS(ctx context.Context, in *ListFakeDNSRequest, opts ...grpc.CallOption) (*ListFakeDNSResponse, error) {
+	out := new(ListFakeDNSResponse)
+	err := c.cc.Invoke(ctx, DNSService_ListFakeDNS_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) FlushFakeDNS(ctx context.Context, in *FlushFakeDNSRequest, opts ...grpc.CallOption) (*FlushFakeDNSResponse, error) {
+	out := new(FlushFakeDNSResponse)
+	err := c.cc.Invoke(ctx, DNSService_FlushFakeDNS_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) ListQueries(ctx context.Context, in *ListQueriesRequest, opts ...grpc.CallOption) (*ListQueriesResponse, error) {
+	out := new(ListQueriesResponse)
+	err := c.cc.Invoke(ctx, DNSService_ListQueries_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dNSServiceClient) ListServers(ctx context.Context, in *ListServersRequest, opts ...grpc.CallOption) (*ListServersResponse, error) {
+	out := new(ListServersResponse)
+	err := c.cc.Invoke(ctx, DNSService_ListServers_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// DNSServiceServer is the server API for DNSService service.
+// All implementations must embed UnimplementedDNSServiceServer
+// for forward compatibility
+type DNSServiceServer interface {
+	ListFakeDNS(context.Context, *ListFakeDNSRequest) (*ListFakeDNSResponse, error)
+	FlushFakeDNS(context.Context, *FlushFakeDNSRequest) (*FlushFakeDNSResponse, error)
+	ListQueries(context.Context, *ListQueriesRequest) (*ListQueriesResponse, error)
+	ListServers(context.Context, *ListServersRequest) (*ListServersResponse, error)
+	mustEmbedUnimplementedDNSServiceServer()
+}
+
+// UnimplementedDNSServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedDNSServiceServer struct {
+}
+
+func (UnimplementedDNSServiceServer) ListFakeDNS(context.Context, *ListFakeDNSRequest) (*ListFakeDNSResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListFakeDNS not implemented")
+}
+func (UnimplementedDNSServiceServer) FlushFakeDNS(context.Context, *FlushFakeDNSRequest) (*FlushFakeDNSResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FlushFakeDNS not implemented")
+}
+func (UnimplementedDNSServiceServer) ListQueries(context.Context, *ListQueriesRequest) (*ListQueriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListQueries not implemented")
+}
+func (UnimplementedDNSServiceServer) ListServers(context.Context, *ListServersRequest) (*ListServersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListServers not implemented")
+}
+func (UnimplementedDNSServiceServer) mustEmbedUnimplementedDNSServiceServer() {}
+
+// UnsafeDNSServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to DNSServiceServer will
+// result in compilation errors.
+type UnsafeDNSServiceServer interface {
+	mustEmbedUnimplementedDNSServiceServer()
+}
+
+func RegisterDNSServiceServer(s grpc.ServiceRegistrar, srv DNSServiceServer) {
+	s.RegisterService(&DNSService_ServiceDesc, srv)
+}
+
+func _DNSService_ListFakeDNS_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFakeDNSRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).ListFakeDNS(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_ListFakeDNS_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).ListFakeDNS(ctx, req.(*ListFakeDNSRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_FlushFakeDNS_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FlushFakeDNSRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).FlushFakeDNS(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_FlushFakeDNS_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).FlushFakeDNS(ctx, req.(*FlushFakeDNSRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_ListQueries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListQueriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).ListQueries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_ListQueries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).ListQueries(ctx, req.(*ListQueriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _DNSService_ListServers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListServersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DNSServiceServer).ListServers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: DNSService_ListServers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DNSServiceServer).ListServers(ctx, req.(*ListServersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// DNSService_ServiceDesc is the grpc.ServiceDesc for DNSService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var DNSService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "xray.app.dns.command.DNSService",
+	HandlerType: (*DNSServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListFakeDNS",
+			Handler:    _DNSService_ListFakeDNS_Handler,
+		},
+		{
+			MethodName: "FlushFakeDNS",
+			Handler:    _DNSService_FlushFakeDNS_Handler,
+		},
+		{
+			MethodName: "ListQueries",
+			Handler:    _DNSService_ListQueries_Handler,
+		},
+		{
+			MethodName: "ListServers",
+			Handler:    _DNSService_ListServers_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "app/dns/command/command.proto",
+}
@@ -0,0 +1,154 @@
+package command_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/xtls/xray-core/app/dispatcher"
+	dnsapp "github.com/xtls/xray-core/app/dns"
+	. "github.com/xtls/xray-core/app/dns/command"
+	"github.com/xtls/xray-core/app/dns/fakedns"
+	"github.com/xtls/xray-core/app/proxyman"
+	_ "github.com/xtls/xray-core/app/proxyman/inbound"
+	_ "github.com/xtls/xray-core/app/proxyman/outbound"
+	"github.com/xtls/xray-core/app/stats"
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/serial"
+	"github.com/xtls/xray-core/common/session"
+	"github.com/xtls/xray-core/core"
+	"github.com/xtls/xray-core/features/dns"
+	feature_stats "github.com/xtls/xray-core/features/stats"
+)
+
+func TestFakeDNSMappings(t *testing.T) {
+	v, err := core.New(&core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&dispatcher.Config{}),
+			serial.ToTypedMessage(&proxyman.InboundConfig{}),
+			serial.ToTypedMessage(&proxyman.OutboundConfig{}),
+			serial.ToTypedMessage(&fakedns.FakeDnsPool{
+				IpPool:  dns.FakeIPv4Pool,
+				LruSize: 256,
+			}),
+		},
+	})
+	common.Must(err)
+	common.Must(v.Start())
+	defer v.Close()
+
+	engine := v.GetFeature((*dns.FakeDNSEngine)(nil)).(dns.FakeDNSEngine)
+	ip := engine.GetFakeIPForDomain("example.com")[0]
+	engine.GetFakeIPForDomain("example.org")
+
+	server := &DNSServer{
+		V: v,
+	}
+	resp, err := server.ListFakeDNS(context.Background(), &ListFakeDNSRequest{Domain: ".com"})
+	common.Must(err)
+	if len(resp.Mappings) != 1 || resp.Mappings[0].Domain != "example.com" || resp.Mappings[0].Ip != ip.String() {
+		t.Error("unexpected mappings: ", resp.Mappings)
+	}
+
+	flushed, err := server.FlushFakeDNS(context.Background(), &FlushFakeDNSRequest{})
+	common.Must(err)
+	if flushed.Flushed != 2 {
+		t.Error("expected 2 flushed mappings, but got ", flushed.Flushed)
+	}
+	if domain := engine.GetDomainFromFakeDNS(ip); domain != "" {
+		t.Error("expected no mapping after flush, but got ", domain)
+	}
+}
+
+func TestFakeDNSNotEnabled(t *testing.T) {
+	v, err := core.New(&core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&dispatcher.Config{}),
+			serial.ToTypedMessage(&proxyman.InboundConfig{}),
+			serial.ToTypedMessage(&proxyman.OutboundConfig{}),
+		},
+	})
+	common.Must(err)
+
+	server := &DNSServer{
+		V: v,
+	}
+	if _, err := server.ListFakeDNS(context.Background(), &ListFakeDNSRequest{}); err == nil {
+		t.Error("expected error when fake DNS is not enabled")
+	}
+}
+
+func TestListQueries(t *testing.T) {
+	v, err := core.New(&core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&dispatcher.Config{}),
+			serial.ToTypedMessage(&proxyman.InboundConfig{}),
+			serial.ToTypedMessage(&proxyman.OutboundConfig{}),
+			serial.ToTypedMessage(&stats.Config{}),
+			serial.ToTypedMessage(&fakedns.FakeDnsPool{
+				IpPool:  dns.FakeIPv4Pool,
+				LruSize: 256,
+			}),
+			serial.ToTypedMessage(&dnsapp.Config{
+				NameServer: []*dnsapp.NameServer{
+					{
+						Address: &net.Endpoint{
+							Network: net.Network_UDP,
+							Address: net.NewIPOrDomain(net.DomainAddress("fakedns")),
+							Port:    53,
+						},
+					},
+				},
+				StaticHosts: []*dnsapp.Config_HostMapping{
+					{
+						Type:   dnsapp.DomainMatchingType_Full,
+						Domain: "example.org",
+						Ip:     [][]byte{{1, 2, 3, 4}},
+					},
+				},
+			}),
+		},
+	})
+	common.Must(err)
+	common.Must(v.Start())
+	defer v.Close()
+
+	client := v.GetFeature(dns.ClientType()).(dns.ContextClient)
+	ctx := session.ContextWithInbound(context.Background(), &session.Inbound{
+		Source: net.UDPDestination(net.ParseAddress("192.168.1.2"), 5353),
+	})
+	option := dns.IPOption{IPv4Enable: true, FakeEnable: true}
+	fakeIPs, err := client.LookupIPWithContext(ctx, "example.com", option)
+	common.Must(err)
+	_, err = client.LookupIPWithContext(ctx, "example.org", option)
+	common.Must(err)
+
+	server := &DNSServer{
+		V: v,
+	}
+	resp, err := server.ListQueries(context.Background(), &ListQueriesRequest{})
+	common.Must(err)
+	if len(resp.Queries) != 2 {
+		t.Fatal("expected 2 queries, but got ", resp.Queries)
+	}
+	if q := resp.Queries[0]; q.Domain != "example.org" || q.Server != "hosts" || q.Answers[0] != "1.2.3.4" {
+		t.Error("unexpected query: ", q)
+	}
+	if q := resp.Queries[1]; q.Domain != "example.com" || q.Server != "FakeDNS" || q.Type != "A" || q.Client != "192.168.1.2:5353" || q.Answers[0] != fakeIPs[0].String() {
+		t.Error("unexpected query: ", q)
+	}
+
+	resp, err = server.ListQueries(context.Background(), &ListQueriesRequest{Server: "FakeDNS", Limit: 1})
+	common.Must(err)
+	if len(resp.Queries) != 1 || resp.Queries[0].Domain != "example.com" {
+		t.Error("unexpected queries: ", resp.Queries)
+	}
+
+	statsManager := v.GetFeature(feature_stats.ManagerType()).(feature_stats.Manager)
+	if c := statsManager.GetCounter("dns>>>FakeDNS>>>query"); c == nil || c.Value() != 1 {
+		t.Error("expected 1 query of FakeDNS in stats, but got ", c)
+	}
+	if c := statsManager.GetCounter("dns>>>hosts>>>query"); c != nil {
+		t.Error("expected hosts not to be counted in stats")
+	}
+}
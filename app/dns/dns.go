@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/xtls/xray-core/app/router"
 	"github.com/xtls/xray-core/common"
@@ -15,23 +16,29 @@ import (
 	"github.com/xtls/xray-core/common/net"
 	"github.com/xtls/xray-core/common/session"
 	"github.com/xtls/xray-core/common/strmatcher"
+	"github.com/xtls/xray-core/core"
 	"github.com/xtls/xray-core/features"
 	"github.com/xtls/xray-core/features/dns"
+	"github.com/xtls/xray-core/features/stats"
+	"golang.org/x/net/dns/dnsmessage"
 )
 
 // DNS is a DNS rely server.
 type DNS struct {
-	sync.Mutex
+	sync.RWMutex
 	tag                    string
 	disableCache           bool
 	disableFallback        bool
 	disableFallbackIfMatch bool
+	serverStrategy         ServerStrategy
 	ipOption               *dns.IPOption
 	hosts                  *StaticHosts
 	clients                []*Client
 	ctx                    context.Context
 	domainMatcher          strmatcher.IndexMatcher
 	matcherInfos           []*DomainMatcherInfo
+	queryLog               *queryLog
+	stats                  *queryStats
 }
 
 // DomainMatcherInfo contains information attached to index returned by Server.domainMatcher
@@ -99,6 +106,7 @@ func New(ctx context.Context, config *Config) (*DNS, error) {
 		features.PrintDeprecatedFeatureWarning("simple DNS server")
 		client, err := NewSimpleClient(ctx, endpoint, clientIP)
 		if err != nil {
+			closeClients(clients)
 			return nil, newError("failed to create client").Base(err)
 		}
 		clients = append(clients, client)
@@ -120,8 +128,9 @@ func New(ctx context.Context, config *Config) (*DNS, error) {
 		case net.IPv4len, net.IPv6len:
 			myClientIP = net.IP(ns.ClientIp)
 		}
-		client, err := NewClient(ctx, ns, myClientIP, geoipContainer, &matcherInfos, updateDomain)
+		client, err := NewClient(ctx, ns, myClientIP, config.cacheOptions(), geoipContainer, &matcherInfos, updateDomain)
 		if err != nil {
+			closeClients(clients)
 			return nil, newError("failed to create client").Base(err)
 		}
 		clients = append(clients, client)
@@ -143,6 +152,8 @@ func New(ctx context.Context, config *Config) (*DNS, error) {
 		disableCache:           config.DisableCache,
 		disableFallback:        config.DisableFallback,
 		disableFallbackIfMatch: config.DisableFallbackIfMatch,
+		serverStrategy:         config.ServerStrategy,
+		queryLog:               newQueryLog(config.QueryLogSize),
 	}, nil
 }
 
@@ -158,23 +169,79 @@ func (s *DNS) Start() error {
 
 // Close implements common.Closable.
 func (s *DNS) Close() error {
+	s.RLock()
+	clients := s.clients
+	s.RUnlock()
+
+	closeClients(clients)
+	return nil
+}
+
+func closeClients(clients []*Client) {
+	for _, client := range clients {
+		if err := client.Close(); err != nil {
+			newError("failed to close DNS client ", client.Name()).Base(err).AtDebug().WriteToLog()
+		}
+	}
+}
+
+// Reload implements features.Reloadable.
+func (s *DNS) Reload(config interface{}) error {
+	c, ok := config.(*Config)
+	if !ok {
+		return newError("Reload: config type error")
+	}
+	n, err := New(s.ctx, c)
+	if err != nil {
+		return err
+	}
+
+	s.Lock()
+	oldClients := s.clients
+	s.tag = n.tag
+	s.hosts = n.hosts
+	s.ipOption = n.ipOption
+	s.clients = n.clients
+	s.domainMatcher = n.domainMatcher
+	s.matcherInfos = n.matcherInfos
+	s.disableCache = n.disableCache
+	s.disableFallback = n.disableFallback
+	s.disableFallbackIfMatch = n.disableFallbackIfMatch
+	s.serverStrategy = n.serverStrategy
+	s.queryLog = n.queryLog
+	s.Unlock()
+
+	// Queries in flight on the old clients may fail, as their connections are closed.
+	closeClients(oldClients)
 	return nil
 }
 
 // IsOwnLink implements proxy.dns.ownLinkVerifier
 func (s *DNS) IsOwnLink(ctx context.Context) bool {
+	s.RLock()
+	defer s.RUnlock()
+
 	inbound := session.InboundFromContext(ctx)
 	return inbound != nil && inbound.Tag == s.tag
 }
 
 // LookupIP implements dns.Client.
 func (s *DNS) LookupIP(domain string, option dns.IPOption) ([]net.IP, error) {
+	return s.LookupIPWithContext(context.Background(), domain, option)
+}
+
+// LookupIPWithContext implements dns.ContextClient.
+func (s *DNS) LookupIPWithContext(reqCtx context.Context, domain string, option dns.IPOption) ([]net.IP, error) {
 	if domain == "" {
 		return nil, newError("empty domain name")
 	}
 
-	option.IPv4Enable = option.IPv4Enable && s.ipOption.IPv4Enable
-	option.IPv6Enable = option.IPv6Enable && s.ipOption.IPv6Enable
+	s.RLock()
+	tag, hosts, ipOption, disableCache, serverStrategy := s.tag, s.hosts, *s.ipOption, s.disableCache, s.serverStrategy
+	s.RUnlock()
+
+	option.IPv4Enable = option.IPv4Enable && ipOption.IPv4Enable
+	option.IPv6Enable = option.IPv6Enable && ipOption.IPv6Enable
 
 	if !option.IPv4Enable && !option.IPv6Enable {
 		return nil, dns.ErrEmptyResponse
@@ -184,7 +251,7 @@ func (s *DNS) LookupIP(domain string, option dns.IPOption) ([]net.IP, error) {
 	domain = strings.TrimSuffix(domain, ".")
 
 	// Static host lookup
-	switch addrs := s.hosts.Lookup(domain, option); {
+	switch addrs := hosts.Lookup(domain, option); {
 	case addrs == nil: // Domain not recorded in static host
 		break
 	case len(addrs) == 0: // Domain recorded, but no valid IP returned (e.g. IPv4 address with only IPv6 enabled)
@@ -194,18 +261,36 @@ func (s *DNS) LookupIP(domain string, option dns.IPOption) ([]net.IP, error) {
 		domain = addrs[0].Domain()
 	default: // Successfully found ip records in static host
 		newError("returning ", len(addrs), " IP(s) for domain ", domain, " -> ", addrs).WriteToLog()
-		return toNetIP(addrs)
+		ips, err := toNetIP(addrs)
+		r := newQueryRecord(reqCtx, domain, ipQueryType(option), "hosts")
+		r.Answers, r.Error = ipAnswers(ips), err
+		s.recordQuery(r, false)
+		return ips, err
 	}
 
 	// Name servers lookup
-	errs := []error{}
-	ctx := session.ContextWithInbound(s.ctx, &session.Inbound{Tag: s.tag})
-	for _, client := range s.sortClients(domain) {
+	ctx := session.ContextWithInbound(s.ctx, &session.Inbound{Tag: tag})
+	sorted := s.sortClients(domain)
+	clients := make([]*Client, 0, len(sorted))
+	for _, client := range sorted {
 		if !option.FakeEnable && strings.EqualFold(client.Name(), "FakeDNS") {
 			newError("skip DNS resolution for domain ", domain, " at server ", client.Name()).AtDebug().WriteToLog()
 			continue
 		}
-		ips, err := client.QueryIP(ctx, domain, option, s.disableCache)
+		clients = append(clients, client)
+	}
+	clients, healthy := orderByHealth(domain, clients)
+	if serverStrategy == ServerStrategy_Fastest && healthy > 1 {
+		return s.lookupFastest(ctx, reqCtx, clients, healthy, domain, option, disableCache)
+	}
+	return s.lookupSequential(ctx, reqCtx, clients, domain, option, disableCache)
+}
+
+// lookupSequential queries the name servers one by one, until one answers.
+func (s *DNS) lookupSequential(ctx, reqCtx context.Context, clients []*Client, domain string, option dns.IPOption, disableCache bool) ([]net.IP, error) {
+	errs := []error{}
+	for _, client := range clients {
+		ips, err := s.queryIP(ctx, reqCtx, client, domain, option, disableCache)
 		if len(ips) > 0 {
 			return ips, nil
 		}
@@ -213,8 +298,7 @@ func (s *DNS) LookupIP(domain string, option dns.IPOption) ([]net.IP, error) {
 			newError("failed to lookup ip for domain ", domain, " at server ", client.Name()).Base(err).WriteToLog()
 			errs = append(errs, err)
 		}
-		// 5 for RcodeRefused in miekg/dns, hardcode to reduce binary size
-		if err != context.Canceled && err != context.DeadlineExceeded && err != errExpectedIPNonMatch && err != dns.ErrEmptyResponse && dns.RCodeFromError(err) != 5 {
+		if !isRetryable(err) {
 			return nil, err
 		}
 	}
@@ -222,6 +306,180 @@ func (s *DNS) LookupIP(domain string, option dns.IPOption) ([]net.IP, error) {
 	return nil, newError("returning nil for domain ", domain).Base(errors.Combine(errs...))
 }
 
+// lookupFastest queries the first healthy name servers in parallel, and
+// returns the first answer with IPs. The unhealthy ones are queried one by
+// one, if none of the healthy ones answers.
+func (s *DNS) lookupFastest(ctx, reqCtx context.Context, clients []*Client, healthy int, domain string, option dns.IPOption, disableCache bool) ([]net.IP, error) {
+	ips, err := s.raceIP(ctx, reqCtx, clients[:healthy], domain, option, disableCache)
+	if len(ips) > 0 || healthy == len(clients) || !isRetryable(err) && !isServerFailure(err) {
+		return ips, err
+	}
+	newError("all healthy DNS servers failed for domain ", domain, ", falling back to unhealthy ones").Base(err).AtDebug().WriteToLog()
+	return s.lookupSequential(ctx, reqCtx, clients[healthy:], domain, option, disableCache)
+}
+
+// raceIP queries the name servers in parallel, and returns the first answer with IPs.
+func (s *DNS) raceIP(ctx, reqCtx context.Context, clients []*Client, domain string, option dns.IPOption, disableCache bool) ([]net.IP, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		client *Client
+		ips    []net.IP
+		err    error
+	}
+	tag := session.InboundFromContext(ctx).Tag
+	results := make(chan result, len(clients))
+	for _, client := range clients {
+		client := client
+		go func() {
+			// The inbound is modified by outbounds, so it is not shared between queries.
+			ctx := session.ContextWithInbound(ctx, &session.Inbound{Tag: tag})
+			ips, err := s.queryIP(ctx, reqCtx, client, domain, option, disableCache)
+			results <- result{client: client, ips: ips, err: err}
+		}()
+	}
+
+	// Like the sequential lookup, an answer without IPs such as NXDOMAIN is
+	// returned, if no server answers with IPs. Answers are preferred to
+	// server failures such as SERVFAIL.
+	var answerErr error
+	errs := []error{}
+	for range clients {
+		r := <-results
+		if len(r.ips) > 0 {
+			return r.ips, nil
+		}
+		if r.err != nil {
+			newError("failed to lookup ip for domain ", domain, " at server ", r.client.Name()).Base(r.err).WriteToLog()
+			errs = append(errs, r.err)
+		}
+		if !isRetryable(r.err) && (answerErr == nil || isServerFailure(answerErr) && !isServerFailure(r.err)) {
+			answerErr = r.err
+		}
+	}
+	if answerErr != nil {
+		return nil, answerErr
+	}
+
+	return nil, newError("returning nil for domain ", domain).Base(errors.Combine(errs...))
+}
+
+// queryIP queries the name server of the client, and records the query.
+func (s *DNS) queryIP(ctx, reqCtx context.Context, client *Client, domain string, option dns.IPOption, disableCache bool) ([]net.IP, error) {
+	r := newQueryRecord(reqCtx, domain, ipQueryType(option), client.Name())
+	trace := new(queryTrace)
+	ips, err := client.QueryIP(contextWithQueryTrace(ctx, trace), domain, option, disableCache)
+	r.Elapsed, r.CacheHit, r.Answers, r.Error = time.Since(r.Time), trace.cacheHit, ipAnswers(ips), err
+	s.recordQuery(r, true)
+	client.health.report(client.Name(), err, r.Elapsed, trace.cacheHit)
+	return ips, err
+}
+
+// isRetryable returns true if the next name server should be tried after the error.
+func isRetryable(err error) bool {
+	// 5 for RcodeRefused in miekg/dns, hardcode to reduce binary size
+	return err == context.Canceled || err == context.DeadlineExceeded || err == errExpectedIPNonMatch || err == dns.ErrEmptyResponse || dns.RCodeFromError(err) == 5
+}
+
+// orderByHealth moves unhealthy clients to the end, so that they are only
+// tried as the last resort. It returns the number of healthy clients.
+func orderByHealth(domain string, clients []*Client) ([]*Client, int) {
+	ordered := make([]*Client, 0, len(clients))
+	var unhealthy []*Client
+	for _, client := range clients {
+		if client.health.healthy() {
+			ordered = append(ordered, client)
+		} else {
+			unhealthy = append(unhealthy, client)
+		}
+	}
+	if len(unhealthy) > 0 {
+		newError("domain ", domain, " skips unhealthy DNS ", len(unhealthy), " server(s) until all others fail").AtDebug().WriteToLog()
+	}
+	return append(ordered, unhealthy...), len(ordered)
+}
+
+// LookupRaw implements dns.RawClient.
+func (s *DNS) LookupRaw(reqCtx context.Context, domain string, qType dnsmessage.Type) (*dnsmessage.Message, error) {
+	if domain == "" {
+		return nil, newError("empty domain name")
+	}
+
+	s.RLock()
+	tag, disableCache := s.tag, s.disableCache
+	s.RUnlock()
+
+	// Normalize the FQDN form query
+	domain = strings.TrimSuffix(domain, ".")
+
+	errs := []error{}
+	ctx := session.ContextWithInbound(s.ctx, &session.Inbound{Tag: tag})
+	clients, _ := orderByHealth(domain, s.sortClients(domain))
+	for _, client := range clients {
+		r := newQueryRecord(reqCtx, domain, rawQueryType(qType), client.Name())
+		trace := new(queryTrace)
+		msg, err := client.QueryRaw(contextWithQueryTrace(ctx, trace), domain, qType, disableCache)
+		if err == errRawQueryUnsupported {
+			newError("skip ", qType, " query for domain ", domain, " at server ", client.Name()).AtDebug().WriteToLog()
+			continue
+		}
+		r.Elapsed, r.CacheHit, r.Error = time.Since(r.Time), trace.cacheHit, err
+		if msg != nil {
+			r.Answers = rawAnswers(msg)
+			if msg.RCode != dnsmessage.RCodeSuccess {
+				r.Error = dns.RCodeError(msg.RCode)
+			}
+		}
+		s.recordQuery(r, true)
+		client.health.report(client.Name(), r.Error, r.Elapsed, r.CacheHit)
+		if err != nil {
+			newError("failed to lookup ", qType, " for domain ", domain, " at server ", client.Name()).Base(err).WriteToLog()
+			errs = append(errs, err)
+			continue
+		}
+		// Try the next server if this one fails or refuses to answer.
+		if msg.RCode == dnsmessage.RCodeServerFailure || msg.RCode == dnsmessage.RCodeRefused {
+			errs = append(errs, dns.RCodeError(msg.RCode))
+			continue
+		}
+		return msg, nil
+	}
+
+	return nil, newError("returning nil for ", qType, " query for domain ", domain).Base(errors.Combine(errs...))
+}
+
+// RecentQueries implements dns.QueryLogger.
+func (s *DNS) RecentQueries() []*dns.QueryRecord {
+	s.RLock()
+	defer s.RUnlock()
+	return s.queryLog.recent()
+}
+
+// ServerHealth implements dns.HealthReporter.
+func (s *DNS) ServerHealth() []dns.ServerHealth {
+	s.RLock()
+	defer s.RUnlock()
+
+	health := make([]dns.ServerHealth, 0, len(s.clients))
+	for _, client := range s.clients {
+		health = append(health, client.health.status(client.Name()))
+	}
+	return health
+}
+
+// recordQuery keeps the query in the query log, and counts it in the stats of its name server if counted.
+func (s *DNS) recordQuery(r *dns.QueryRecord, counted bool) {
+	s.RLock()
+	queryLog, stats := s.queryLog, s.stats
+	s.RUnlock()
+
+	queryLog.add(r)
+	if counted {
+		stats.add(r)
+	}
+}
+
 // LookupHosts implements dns.HostsLookup.
 func (s *DNS) LookupHosts(domain string) *net.Address {
 	domain = strings.TrimSuffix(domain, ".")
@@ -229,7 +487,9 @@ func (s *DNS) LookupHosts(domain string) *net.Address {
 		return nil
 	}
 	// Normalize the FQDN form query
+	s.RLock()
 	addrs := s.hosts.Lookup(domain, *s.ipOption)
+	s.RUnlock()
 	if len(addrs) > 0 {
 		newError("domain replaced: ", domain, " -> ", addrs[0].String()).AtInfo().WriteToLog()
 		return &addrs[0]
@@ -255,6 +515,9 @@ func (s *DNS) SetFakeDNSOption(isFakeEnable bool) {
 }
 
 func (s *DNS) sortClients(domain string) []*Client {
+	s.RLock()
+	defer s.RUnlock()
+
 	clients := make([]*Client, 0, len(s.clients))
 	clientUsed := make([]bool, len(s.clients))
 	clientNames := make([]string, 0, len(s.clients))
@@ -276,6 +539,18 @@ func (s *DNS) sortClients(domain string) []*Client {
 		hasMatch = true
 	}
 
+	// Rule-set matching, after the domain rules
+	for idx, client := range s.clients {
+		if clientUsed[idx] || !client.matchRuleSets(domain) {
+			continue
+		}
+		domainRules = append(domainRules, fmt.Sprintf("rule-set(DNS idx:%d)", idx))
+		clientUsed[idx] = true
+		clients = append(clients, client)
+		clientNames = append(clientNames, client.Name())
+		hasMatch = true
+	}
+
 	if !(s.disableFallback || s.disableFallbackIfMatch && hasMatch) {
 		// Default round-robin query
 		for idx, client := range s.clients {
@@ -306,6 +581,15 @@ func (s *DNS) sortClients(domain string) []*Client {
 
 func init() {
 	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
-		return New(ctx, config.(*Config))
+		d, err := New(ctx, config.(*Config))
+		if err != nil {
+			return nil, err
+		}
+		if err := core.RequireFeatures(ctx, func(sm stats.Manager) {
+			d.stats = &queryStats{manager: sm}
+		}); err != nil {
+			return nil, err
+		}
+		return d, nil
 	}))
 }
@@ -1,6 +1,9 @@
 package dns_test
 
 import (
+	"os"
+	"path/filepath"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -1053,3 +1056,188 @@ func TestMultiMatchPrioritizedDomain(t *testing.T) {
 		t.Error("DNS query doesn't finish in 2 seconds.")
 	}
 }
+
+type servfailHandler struct{}
+
+func (*servfailHandler) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
+	ans := new(dns.Msg)
+	ans.SetRcode(r, dns.RcodeServerFailure)
+	w.WriteMsg(ans)
+}
+
+func newServerStrategyInstance(t *testing.T, strategy ServerStrategy) *core.Instance {
+	return newFailingServerInstance(t, func(config *Config) {
+		config.ServerStrategy = strategy
+	})
+}
+
+// switchHandler answers after the delay, with SERVFAIL while it is failing.
+type switchHandler struct {
+	delay   time.Duration
+	failing atomic.Bool
+}
+
+func (h *switchHandler) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
+	time.Sleep(h.delay)
+	if h.failing.Load() {
+		(&servfailHandler{}).ServeDNS(w, r)
+	} else {
+		(&staticHandler{}).ServeDNS(w, r)
+	}
+}
+
+// newFailingServerInstance creates an instance with a name server answering SERVFAIL, followed by a working one.
+func newFailingServerInstance(t *testing.T, configure func(*Config)) *core.Instance {
+	return newNameServerInstance(t, []dns.Handler{&servfailHandler{}, &staticHandler{}}, configure)
+}
+
+// newNameServerInstance creates an instance with a name server for each of the handlers.
+func newNameServerInstance(t *testing.T, handlers []dns.Handler, configure func(*Config)) *core.Instance {
+	var nameServers []*NameServer
+	for _, handler := range handlers {
+		port := udp.PickPort()
+		dnsServer := dns.Server{
+			Addr:    "127.0.0.1:" + port.String(),
+			Net:     "udp",
+			Handler: handler,
+			UDPSize: 1200,
+		}
+		t.Cleanup(func() { dnsServer.Shutdown() })
+		go dnsServer.ListenAndServe()
+
+		nameServers = append(nameServers, &NameServer{
+			Address: &net.Endpoint{
+				Network: net.Network_UDP,
+				Address: net.NewIPOrDomain(net.LocalHostIP),
+				Port:    uint32(port),
+			},
+		})
+	}
+	time.Sleep(time.Second)
+
+	config := &Config{
+		NameServer:   nameServers,
+		DisableCache: true,
+	}
+	configure(config)
+
+	v, err := core.New(&core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(config),
+			serial.ToTypedMessage(&dispatcher.Config{}),
+			serial.ToTypedMessage(&proxyman.OutboundConfig{}),
+			serial.ToTypedMessage(&policy.Config{}),
+		},
+		Outbound: []*core.OutboundHandlerConfig{
+			{
+				ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+			},
+		},
+	})
+	common.Must(err)
+	return v
+}
+
+func TestUnhealthyServerSkipped(t *testing.T) {
+	v := newServerStrategyInstance(t, ServerStrategy_Sequential)
+	client := v.GetFeature(feature_dns.ClientType()).(feature_dns.Client)
+	option := feature_dns.IPOption{IPv4Enable: true}
+
+	// The first server fails, and the lookup stops at its SERVFAIL answer.
+	for i := 0; i < 3; i++ {
+		if _, err := client.LookupIP("google.com", option); err == nil {
+			t.Fatal("expected SERVFAIL from the first server")
+		}
+	}
+
+	// The first server is unhealthy now, so the second one answers.
+	ips, err := client.LookupIP("google.com", option)
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	if r := cmp.Diff(ips, []net.IP{{8, 8, 8, 8}}); r != "" {
+		t.Error(r)
+	}
+
+	health := client.(feature_dns.HealthReporter).ServerHealth()
+	if len(health) != 2 || health[0].Healthy || health[0].Failures != 3 || !health[1].Healthy || health[1].SuccessRate != 1 {
+		t.Error("unexpected server health: ", health)
+	}
+}
+
+func TestFastestServerStrategy(t *testing.T) {
+	v := newServerStrategyInstance(t, ServerStrategy_Fastest)
+	client := v.GetFeature(feature_dns.ClientType()).(feature_dns.Client)
+
+	ips, err := client.LookupIP("google.com", feature_dns.IPOption{IPv4Enable: true})
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	if r := cmp.Diff(ips, []net.IP{{8, 8, 8, 8}}); r != "" {
+		t.Error(r)
+	}
+
+	// NXDOMAIN is returned when no server answers with IPs.
+	if _, err := client.LookupIP("notexist.google.com", feature_dns.IPOption{IPv6Enable: true}); feature_dns.RCodeFromError(err) != uint16(dns.RcodeNameError) {
+		t.Error("expected NXDOMAIN, but got ", err)
+	}
+}
+
+func TestFastestServerStrategyFallback(t *testing.T) {
+	first := &switchHandler{}
+	first.failing.Store(true)
+	second := &switchHandler{delay: 300 * time.Millisecond}
+	third := &switchHandler{delay: 300 * time.Millisecond}
+	v := newNameServerInstance(t, []dns.Handler{first, second, third}, func(config *Config) {
+		config.ServerStrategy = ServerStrategy_Fastest
+	})
+	client := v.GetFeature(feature_dns.ClientType()).(feature_dns.Client)
+	option := feature_dns.IPOption{IPv4Enable: true}
+
+	// The first server fails before the others answer, until it is unhealthy.
+	// Each lookup is for another domain, so that the answers in flight are not shared.
+	for _, domain := range []string{"api.google.com", "v2.api.google.com", "ipv6.google.com"} {
+		if _, err := client.LookupIP(domain, option); err != nil {
+			t.Fatal("unexpected error: ", err)
+		}
+	}
+	if health := client.(feature_dns.HealthReporter).ServerHealth(); health[0].Healthy {
+		t.Fatal("expected the first server to be unhealthy: ", health)
+	}
+
+	// The healthy servers fail, so the unhealthy one answers.
+	first.failing.Store(false)
+	second.failing.Store(true)
+	third.failing.Store(true)
+	ips, err := client.LookupIP("facebook.com", option)
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	if r := cmp.Diff(ips, []net.IP{{9, 9, 9, 9}}); r != "" {
+		t.Error(r)
+	}
+}
+
+func TestRuleSetDomain(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "list.txt")
+	common.Must(os.WriteFile(file, []byte("google.com\n"), 0o644))
+
+	v := newFailingServerInstance(t, func(config *Config) {
+		config.NameServer[1].RuleSet = []*router.RuleSetFile{{Path: file}}
+	})
+	client := v.GetFeature(feature_dns.ClientType()).(feature_dns.Client)
+	option := feature_dns.IPOption{IPv4Enable: true}
+
+	// The domain in the rule-set is resolved by the second server first.
+	ips, err := client.LookupIP("api.google.com", option)
+	if err != nil {
+		t.Fatal("unexpected error: ", err)
+	}
+	if r := cmp.Diff(ips, []net.IP{{8, 8, 7, 7}}); r != "" {
+		t.Error(r)
+	}
+
+	if _, err := client.LookupIP("facebook.com", option); err == nil {
+		t.Error("expected SERVFAIL from the first server")
+	}
+}
@@ -24,11 +24,6 @@ func Fqdn(domain string) string {
 	return domain + "."
 }
 
-type record struct {
-	A    *IPRecord
-	AAAA *IPRecord
-}
-
 // IPRecord is a cacheable item for a resolved domain
 type IPRecord struct {
 	ReqID  uint16
@@ -37,16 +32,6 @@ type IPRecord struct {
 	RCode  dnsmessage.RCode
 }
 
-func (r *IPRecord) getIPs() ([]net.Address, error) {
-	if r == nil || r.Expire.Before(time.Now()) {
-		return nil, errRecordNotFound
-	}
-	if r.RCode != dnsmessage.RCodeSuccess {
-		return nil, dns_feature.RCodeError(r.RCode)
-	}
-	return r.IP, nil
-}
-
 func isNewer(baseRec *IPRecord, newRec *IPRecord) bool {
 	if newRec == nil {
 		return false
@@ -65,6 +50,8 @@ type dnsRequest struct {
 	start   time.Time
 	expire  time.Time
 	msg     *dnsmessage.Message
+	// response receives the packed response instead of the cache, if not nil.
+	response chan []byte
 }
 
 func genEDNS0Options(clientIP net.IP) *dnsmessage.Resource {
@@ -2,15 +2,20 @@ package fakedns
 
 import (
 	"context"
+	"encoding/json"
 	"math"
 	"math/big"
 	gonet "net"
+	"os"
+	"path/filepath"
 	"sync"
 	"time"
 
 	"github.com/xtls/xray-core/common"
 	"github.com/xtls/xray-core/common/cache"
+	"github.com/xtls/xray-core/common/errors"
 	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/task"
 	"github.com/xtls/xray-core/features/dns"
 )
 
@@ -20,6 +25,7 @@ type Holder struct {
 	mu         *sync.Mutex
 
 	config *FakeDnsPool
+	saver  *task.Periodic
 }
 
 func (fkdns *Holder) IsIPInIPPool(ip net.Address) bool {
@@ -43,18 +49,148 @@ func (*Holder) Type() interface{} {
 
 func (fkdns *Holder) Start() error {
 	if fkdns.config != nil && fkdns.config.IpPool != "" && fkdns.config.LruSize != 0 {
-		return fkdns.initializeFromConfig()
+		if err := fkdns.initializeFromConfig(); err != nil {
+			return err
+		}
+		return fkdns.startSaving()
 	}
 	return newError("invalid fakeDNS setting")
 }
 
 func (fkdns *Holder) Close() error {
+	var err error
+	if fkdns.saver != nil {
+		fkdns.saver.Close()
+		fkdns.saver = nil
+		err = fkdns.save()
+	}
 	fkdns.domainToIP = nil
 	fkdns.ipRange = nil
 	fkdns.mu = nil
+	return err
+}
+
+// fakeDNSState is the content of the state file of a pool.
+type fakeDNSState struct {
+	IPPool   string            `json:"ipPool"`
+	Mappings []fakeDNSStateMap `json:"mappings"`
+}
+
+type fakeDNSStateMap struct {
+	Domain string `json:"domain"`
+	IP     string `json:"ip"`
+}
+
+// startSaving restores the mappings from the state file, and saves them periodically, if the state file is set.
+func (fkdns *Holder) startSaving() error {
+	if len(fkdns.config.StateFile) == 0 {
+		return nil
+	}
+	if err := fkdns.load(); err != nil {
+		return newError("failed to load fake DNS state from ", fkdns.config.StateFile).Base(err)
+	}
+
+	interval := time.Minute
+	if fkdns.config.SaveInterval > 0 {
+		interval = time.Duration(fkdns.config.SaveInterval) * time.Second
+	}
+	fkdns.saver = &task.Periodic{
+		Interval: interval,
+		Execute: func() error {
+			if err := fkdns.save(); err != nil {
+				newError("failed to save fake DNS state to ", fkdns.config.StateFile).Base(err).AtWarning().WriteToLog()
+			}
+			return nil
+		},
+	}
+	return fkdns.saver.Start()
+}
+
+func (fkdns *Holder) load() error {
+	data, err := os.ReadFile(fkdns.config.StateFile)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var state fakeDNSState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+
+	fkdns.mu.Lock()
+	defer fkdns.mu.Unlock()
+	// Mappings are saved from the most recently used, so put them back in reverse order.
+	var restored int
+	for i := len(state.Mappings) - 1; i >= 0; i-- {
+		m := state.Mappings[i]
+		ip := net.ParseAddress(m.IP)
+		if len(m.Domain) == 0 || !fkdns.IsIPInIPPool(ip) {
+			continue
+		}
+		if _, found := fkdns.domainToIP.PeekKeyFromValue(ip); found {
+			continue
+		}
+		fkdns.domainToIP.Put(m.Domain, ip)
+		restored++
+	}
+	newError("restored ", restored, " fake DNS mappings of ", fkdns.config.IpPool).AtInfo().WriteToLog()
 	return nil
 }
 
+func (fkdns *Holder) save() error {
+	state := fakeDNSState{
+		IPPool: fkdns.config.IpPool,
+	}
+	for _, m := range fkdns.FakeDNSMappings() {
+		state.Mappings = append(state.Mappings, fakeDNSStateMap{
+			Domain: m.Domain,
+			IP:     m.IP.String(),
+		})
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+	// Write to a temporary file first, so that the state file is never left half written.
+	path := fkdns.config.StateFile
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// FakeDNSMappings implements dns.FakeDNSMappingManager.
+func (fkdns *Holder) FakeDNSMappings() []dns.FakeDNSMapping {
+	var mappings []dns.FakeDNSMapping
+	fkdns.domainToIP.Range(func(key, value interface{}) bool {
+		mappings = append(mappings, dns.FakeDNSMapping{
+			Domain: key.(string),
+			IP:     value.(net.Address),
+		})
+		return true
+	})
+	return mappings
+}
+
+// FlushFakeDNS implements dns.FakeDNSMappingManager.
+func (fkdns *Holder) FlushFakeDNS() int {
+	fkdns.mu.Lock()
+	defer fkdns.mu.Unlock()
+	return fkdns.domainToIP.Clear()
+}
+
 func NewFakeDNSHolder() (*Holder, error) {
 	var fkdns *Holder
 	var err error
@@ -70,7 +206,7 @@ func NewFakeDNSHolder() (*Holder, error) {
 }
 
 func NewFakeDNSHolderConfigOnly(conf *FakeDnsPool) (*Holder, error) {
-	return &Holder{nil, nil, nil, conf}, nil
+	return &Holder{config: conf}, nil
 }
 
 func (fkdns *Holder) initializeFromConfig() error {
@@ -201,15 +337,36 @@ func (h *HolderMulti) Start() error {
 	return nil
 }
 
+// Close closes all pools, even if some of them fail to close.
 func (h *HolderMulti) Close() error {
+	var errs []error
 	for _, v := range h.holders {
-		if err := v.Close(); err != nil {
-			return newError("Cannot close all fake dns pools").Base(err)
-		}
+		errs = append(errs, v.Close())
+	}
+	if err := errors.Combine(errs...); err != nil {
+		return newError("Cannot close all fake dns pools").Base(err)
 	}
 	return nil
 }
 
+// FakeDNSMappings implements dns.FakeDNSMappingManager.
+func (h *HolderMulti) FakeDNSMappings() []dns.FakeDNSMapping {
+	var ret []dns.FakeDNSMapping
+	for _, v := range h.holders {
+		ret = append(ret, v.FakeDNSMappings()...)
+	}
+	return ret
+}
+
+// FlushFakeDNS implements dns.FakeDNSMappingManager.
+func (h *HolderMulti) FlushFakeDNS() int {
+	var n int
+	for _, v := range h.holders {
+		n += v.FlushFakeDNS()
+	}
+	return n
+}
+
 func (h *HolderMulti) createHolderGroups() error {
 	for _, v := range h.config.Pools {
 		holder, err := NewFakeDNSHolderConfigOnly(v)
@@ -2,6 +2,7 @@ package fakedns
 
 import (
 	gonet "net"
+	"path/filepath"
 	"strconv"
 	"testing"
 
@@ -204,3 +205,69 @@ func TestFakeDNSMulti(t *testing.T) {
 		})
 	})
 }
+
+func TestFakeDNSStateFile(t *testing.T) {
+	stateFile := filepath.Join(t.TempDir(), "fakedns.json")
+	config := &FakeDnsPool{
+		IpPool:    dns.FakeIPv4Pool,
+		LruSize:   256,
+		StateFile: stateFile,
+	}
+
+	fkdns, err := NewFakeDNSHolderConfigOnly(config)
+	common.Must(err)
+	common.Must(fkdns.Start())
+	addr := fkdns.GetFakeIPForDomain("fakednstest.example.com")
+	addr2 := fkdns.GetFakeIPForDomain("fakednstest2.example.com")
+	common.Must(fkdns.Close())
+
+	restored, err := NewFakeDNSHolderConfigOnly(config)
+	common.Must(err)
+	common.Must(restored.Start())
+	defer restored.Close()
+
+	assert.Equal(t, "fakednstest.example.com", restored.GetDomainFromFakeDNS(addr[0]))
+	assert.Equal(t, "fakednstest2.example.com", restored.GetDomainFromFakeDNS(addr2[0]))
+	assert.Equal(t, addr, restored.GetFakeIPForDomain("fakednstest.example.com"))
+
+	mappings := restored.FakeDNSMappings()
+	assert.Len(t, mappings, 2)
+	assert.Equal(t, "fakednstest.example.com", mappings[0].Domain)
+
+	assert.Equal(t, 2, restored.FlushFakeDNS())
+	assert.Equal(t, "", restored.GetDomainFromFakeDNS(addr[0]))
+	assert.Empty(t, restored.FakeDNSMappings())
+}
+
+func TestFakeDNSMultiClose(t *testing.T) {
+	dir := t.TempDir()
+	stateFile := filepath.Join(dir, "fakedns6.json")
+	fakeMulti, err := NewFakeDNSHolderMulti(&FakeDnsPoolMulti{
+		Pools: []*FakeDnsPool{{
+			IpPool:  "240.0.0.0/12",
+			LruSize: 256,
+			// The directory does not exist, so the state cannot be saved.
+			StateFile: filepath.Join(dir, "missing", "fakedns4.json"),
+		}, {
+			IpPool:    "fddd:c5b4:ff5f:f4f0::/64",
+			LruSize:   256,
+			StateFile: stateFile,
+		}},
+	})
+	common.Must(err)
+	common.Must(fakeMulti.Start())
+	addr := fakeMulti.GetFakeIPForDomain3("fakednstest.example.com", false, true)
+
+	assert.Error(t, fakeMulti.Close())
+
+	// The pool after the failed one is closed and saved too.
+	restored, err := NewFakeDNSHolderConfigOnly(&FakeDnsPool{
+		IpPool:    "fddd:c5b4:ff5f:f4f0::/64",
+		LruSize:   256,
+		StateFile: stateFile,
+	})
+	common.Must(err)
+	common.Must(restored.Start())
+	defer restored.Close()
+	assert.Equal(t, "fakednstest.example.com", restored.GetDomainFromFakeDNS(addr[0]))
+}
@@ -0,0 +1,113 @@
+package dns
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/xtls/xray-core/common/errors"
+	dns_feature "github.com/xtls/xray-core/features/dns"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+const (
+	// A name server is unhealthy after this many consecutive failures.
+	healthFailureThreshold = 3
+	healthMinBackoff       = 10 * time.Second
+	healthMaxBackoff       = 5 * time.Minute
+	// Weight of the latest query in the failure rate and the latency.
+	healthWeight = 0.2
+)
+
+// serverHealth tracks the failure rate and the latency of a name server. The
+// zero value is a healthy server without any queries.
+type serverHealth struct {
+	sync.Mutex
+	failures    int
+	failureRate float64
+	latency     time.Duration
+	backoff     time.Duration
+	downUntil   time.Time
+}
+
+// healthy returns false if the server failed recently, and should be skipped until its backoff ends.
+func (h *serverHealth) healthy() bool {
+	h.Lock()
+	defer h.Unlock()
+	return !time.Now().Before(h.downUntil)
+}
+
+// report updates the health by the result of a query to the server.
+func (h *serverHealth) report(name string, err error, elapsed time.Duration, cacheHit bool) {
+	// Cached answers tell nothing about the server, and canceled queries
+	// were abandoned by the caller.
+	if cacheHit || errors.Cause(err) == context.Canceled {
+		return
+	}
+
+	h.Lock()
+	defer h.Unlock()
+
+	if isServerFailure(err) {
+		h.failures++
+		h.failureRate += (1 - h.failureRate) * healthWeight
+		if h.failures >= healthFailureThreshold {
+			h.backoff *= 2
+			if h.backoff < healthMinBackoff {
+				h.backoff = healthMinBackoff
+			}
+			if h.backoff > healthMaxBackoff {
+				h.backoff = healthMaxBackoff
+			}
+			h.downUntil = time.Now().Add(h.backoff)
+			newError("DNS server ", name, " is unhealthy after ", h.failures, " failures, skipping it for ", h.backoff).Base(err).AtWarning().WriteToLog()
+		}
+		return
+	}
+
+	if h.failures >= healthFailureThreshold {
+		newError("DNS server ", name, " is healthy again").AtInfo().WriteToLog()
+	}
+	h.failures = 0
+	h.backoff = 0
+	h.downUntil = time.Time{}
+	h.failureRate -= h.failureRate * healthWeight
+	if h.latency == 0 {
+		h.latency = elapsed
+	} else {
+		h.latency += time.Duration(float64(elapsed-h.latency) * healthWeight)
+	}
+}
+
+func (h *serverHealth) status(name string) dns_feature.ServerHealth {
+	h.Lock()
+	defer h.Unlock()
+	return dns_feature.ServerHealth{
+		Server:      name,
+		Healthy:     !time.Now().Before(h.downUntil),
+		Failures:    h.failures,
+		SuccessRate: 1 - h.failureRate,
+		Latency:     h.latency,
+	}
+}
+
+// isServerFailure returns true if the error means that the server did not
+// answer, as opposed to answers such as NXDOMAIN or an empty response.
+func isServerFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	cause := errors.Cause(err)
+	if cause == dns_feature.ErrEmptyResponse || cause == errExpectedIPNonMatch || cause == errRawQueryUnsupported {
+		return false
+	}
+	switch rcode := dnsmessage.RCode(dns_feature.RCodeFromError(err)); rcode {
+	case dnsmessage.RCodeServerFailure, dnsmessage.RCodeRefused:
+		return true
+	case dnsmessage.RCodeSuccess:
+		// Not an answer from the server at all, e.g. a timeout.
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,45 @@
+package dns
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	dns_feature "github.com/xtls/xray-core/features/dns"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+func TestServerHealth(t *testing.T) {
+	var h serverHealth
+	timeout := newError("failed to query").Base(context.DeadlineExceeded)
+
+	for i := 0; i < healthFailureThreshold-1; i++ {
+		h.report("test", timeout, 0, false)
+	}
+	h.report("test", dns_feature.ErrEmptyResponse, time.Millisecond, false)
+	h.report("test", timeout, 0, true)
+	h.report("test", context.Canceled, 0, false)
+	if !h.healthy() || h.failures != 0 {
+		t.Fatal("expected answers to reset failures, but got ", h.failures)
+	}
+
+	for i := 0; i < healthFailureThreshold; i++ {
+		h.report("test", dns_feature.RCodeError(dnsmessage.RCodeServerFailure), 0, false)
+	}
+	if h.healthy() || h.backoff != healthMinBackoff {
+		t.Fatal("expected server to be unhealthy for ", healthMinBackoff, ", but got ", h.backoff)
+	}
+
+	// The backoff doubles when the server fails again after the backoff.
+	h.downUntil = time.Now()
+	h.report("test", timeout, 0, false)
+	if h.healthy() || h.backoff != 2*healthMinBackoff {
+		t.Error("expected backoff to double, but got ", h.backoff)
+	}
+
+	h.report("test", dns_feature.RCodeError(dnsmessage.RCodeNameError), 10*time.Millisecond, false)
+	status := h.status("test")
+	if !status.Healthy || status.Failures != 0 || status.SuccessRate >= 1 || status.Latency != 2800*time.Microsecond {
+		t.Error("unexpected status: ", status)
+	}
+}
@@ -7,12 +7,14 @@ import (
 	"time"
 
 	"github.com/xtls/xray-core/app/router"
+	"github.com/xtls/xray-core/common"
 	"github.com/xtls/xray-core/common/errors"
 	"github.com/xtls/xray-core/common/net"
 	"github.com/xtls/xray-core/common/strmatcher"
 	"github.com/xtls/xray-core/core"
 	"github.com/xtls/xray-core/features/dns"
 	"github.com/xtls/xray-core/features/routing"
+	"golang.org/x/net/dns/dnsmessage"
 )
 
 // Server is the interface for Name Server.
@@ -23,16 +25,26 @@ type Server interface {
 	QueryIP(ctx context.Context, domain string, clientIP net.IP, option dns.IPOption, disableCache bool) ([]net.IP, error)
 }
 
+// cachingServer is a Server that caches records.
+type cachingServer interface {
+	recordCache() *recordCache
+}
+
 // Client is the interface for DNS client.
 type Client struct {
 	server       Server
 	clientIP     net.IP
 	skipFallback bool
 	domains      []string
+	ruleSets     []*router.RuleSetMatcher
 	expectIPs    []*router.GeoIPMatcher
+	health       serverHealth
 }
 
-var errExpectedIPNonMatch = errors.New("expectIPs not match")
+var (
+	errExpectedIPNonMatch  = errors.New("expectIPs not match")
+	errRawQueryUnsupported = errors.New("query type not supported")
+)
 
 // NewServer creates a name server object according to the network destination url.
 func NewServer(dest net.Destination, dispatcher routing.Dispatcher, queryStrategy QueryStrategy) (Server, error) {
@@ -54,6 +66,10 @@ func NewServer(dest net.Destination, dispatcher routing.Dispatcher, queryStrateg
 			return NewTCPNameServer(u, dispatcher, queryStrategy)
 		case strings.EqualFold(u.Scheme, "tcp+local"): // DNS-over-TCP Local mode
 			return NewTCPLocalNameServer(u, queryStrategy)
+		case strings.EqualFold(u.Scheme, "tls"): // DNS-over-TLS Remote mode
+			return NewTLSNameServer(u, dispatcher, queryStrategy)
+		case strings.EqualFold(u.Scheme, "tls+local"): // DNS-over-TLS Local mode
+			return NewTLSLocalNameServer(u, queryStrategy)
 		case strings.EqualFold(u.String(), "fakedns"):
 			return NewFakeDNSServer(), nil
 		}
@@ -72,6 +88,7 @@ func NewClient(
 	ctx context.Context,
 	ns *NameServer,
 	clientIP net.IP,
+	cache cacheOptions,
 	container router.GeoIPMatcherContainer,
 	matcherInfos *[]*DomainMatcherInfo,
 	updateDomainRule func(strmatcher.Matcher, int, []*DomainMatcherInfo) error,
@@ -84,6 +101,9 @@ func NewClient(
 		if err != nil {
 			return newError("failed to create nameserver").Base(err).AtWarning()
 		}
+		if s, ok := server.(cachingServer); ok {
+			s.recordCache().setOptions(cache)
+		}
 
 		// Priotize local domains with specific TLDs or without any dot to local DNS
 		if _, isLocalDNS := server.(*LocalNameServer); isLocalDNS {
@@ -134,11 +154,23 @@ func NewClient(
 			}
 		}
 
+		// Establish domain rule-sets
+		var ruleSets []*router.RuleSetMatcher
+		for _, file := range ns.RuleSet {
+			ruleSet, err := router.NewRuleSetMatcher(file)
+			if err != nil {
+				closeRuleSets(ruleSets)
+				return newError("failed to create rule-set").Base(err).AtWarning()
+			}
+			ruleSets = append(ruleSets, ruleSet)
+		}
+
 		// Establish expected IPs
 		var matchers []*router.GeoIPMatcher
 		for _, geoip := range ns.Geoip {
 			matcher, err := container.Add(geoip)
 			if err != nil {
+				closeRuleSets(ruleSets)
 				return newError("failed to create ip matcher").Base(err).AtWarning()
 			}
 			matchers = append(matchers, matcher)
@@ -157,6 +189,7 @@ func NewClient(
 		client.clientIP = clientIP
 		client.skipFallback = ns.SkipFallback
 		client.domains = rules
+		client.ruleSets = ruleSets
 		client.expectIPs = matchers
 		return nil
 	})
@@ -193,6 +226,28 @@ func (c *Client) Name() string {
 	return c.server.Name()
 }
 
+// Close releases the rule-sets of the client, and the connections and tasks of the server it manages.
+func (c *Client) Close() error {
+	closeRuleSets(c.ruleSets)
+	return common.Close(c.server)
+}
+
+func closeRuleSets(ruleSets []*router.RuleSetMatcher) {
+	for _, ruleSet := range ruleSets {
+		ruleSet.Close()
+	}
+}
+
+// matchRuleSets returns true if the domain is in any rule-set of the client.
+func (c *Client) matchRuleSets(domain string) bool {
+	for _, ruleSet := range c.ruleSets {
+		if ruleSet.MatchDomain(domain) {
+			return true
+		}
+	}
+	return false
+}
+
 // QueryIP sends DNS query to the name server with the client's IP.
 func (c *Client) QueryIP(ctx context.Context, domain string, option dns.IPOption, disableCache bool) ([]net.IP, error) {
 	ctx, cancel := context.WithTimeout(ctx, 4*time.Second)
@@ -205,6 +260,17 @@ func (c *Client) QueryIP(ctx context.Context, domain string, option dns.IPOption
 	return c.MatchExpectedIPs(domain, ips)
 }
 
+// QueryRaw sends the query of the given type to the name server with the client's IP.
+func (c *Client) QueryRaw(ctx context.Context, domain string, qType dnsmessage.Type, disableCache bool) (*dnsmessage.Message, error) {
+	server, ok := c.server.(rawQuerier)
+	if !ok {
+		return nil, errRawQueryUnsupported
+	}
+	ctx, cancel := context.WithTimeout(ctx, 4*time.Second)
+	defer cancel()
+	return server.QueryRaw(ctx, domain, c.clientIP, qType, disableCache)
+}
+
 // MatchExpectedIPs matches queried domain IPs with expected IPs and returns matched ones.
 func (c *Client) MatchExpectedIPs(domain string, ips []net.IP) ([]net.IP, error) {
 	if len(c.expectIPs) == 0 {
@@ -31,7 +31,7 @@ import (
 type DoHNameServer struct {
 	dispatcher routing.Dispatcher
 	sync.RWMutex
-	ips           map[string]*record
+	cache         *recordCache
 	pub           *pubsub.Service
 	cleanup       *task.Periodic
 	reqID         uint32
@@ -125,7 +125,7 @@ func NewDoHLocalNameServer(url *url.URL, queryStrategy QueryStrategy) *DoHNameSe
 
 func baseDOHNameServer(url *url.URL, prefix string, queryStrategy QueryStrategy) *DoHNameServer {
 	s := &DoHNameServer{
-		ips:           make(map[string]*record),
+		cache:         newRecordCache(),
 		pub:           pubsub.NewService(),
 		name:          prefix + "//" + url.Host,
 		dohURL:        url.String(),
@@ -143,56 +143,28 @@ func (s *DoHNameServer) Name() string {
 	return s.name
 }
 
+func (s *DoHNameServer) recordCache() *recordCache {
+	return s.cache
+}
+
+// Close implements common.Closable.
+func (s *DoHNameServer) Close() error {
+	s.httpClient.CloseIdleConnections()
+	return s.cleanup.Close()
+}
+
 // Cleanup clears expired items from cache
 func (s *DoHNameServer) Cleanup() error {
-	now := time.Now()
-	s.Lock()
-	defer s.Unlock()
-
-	if len(s.ips) == 0 {
+	if s.cache.cleanup(s.name) == 0 {
 		return newError("nothing to do. stopping...")
 	}
-
-	for domain, record := range s.ips {
-		if record.A != nil && record.A.Expire.Before(now) {
-			record.A = nil
-		}
-		if record.AAAA != nil && record.AAAA.Expire.Before(now) {
-			record.AAAA = nil
-		}
-
-		if record.A == nil && record.AAAA == nil {
-			newError(s.name, " cleanup ", domain).AtDebug().WriteToLog()
-			delete(s.ips, domain)
-		} else {
-			s.ips[domain] = record
-		}
-	}
-
-	if len(s.ips) == 0 {
-		s.ips = make(map[string]*record)
-	}
-
 	return nil
 }
 
 func (s *DoHNameServer) updateIP(req *dnsRequest, ipRec *IPRecord) {
 	elapsed := time.Since(req.start)
 
-	s.Lock()
-	rec, found := s.ips[req.domain]
-	if !found {
-		rec = &record{}
-	}
-	updated := false
-
-	switch req.reqType {
-	case dnsmessage.TypeA:
-		if isNewer(rec.A, ipRec) {
-			rec.A = ipRec
-			updated = true
-		}
-	case dnsmessage.TypeAAAA:
+	if req.reqType == dnsmessage.TypeAAAA {
 		addr := make([]net.Address, 0, len(ipRec.IP))
 		for _, ip := range ipRec.IP {
 			if len(ip.IP()) == net.IPv6len {
@@ -200,23 +172,16 @@ func (s *DoHNameServer) updateIP(req *dnsRequest, ipRec *IPRecord) {
 			}
 		}
 		ipRec.IP = addr
-		if isNewer(rec.AAAA, ipRec) {
-			rec.AAAA = ipRec
-			updated = true
-		}
 	}
 	newError(s.name, " got answer: ", req.domain, " ", req.reqType, " -> ", ipRec.IP, " ", elapsed).AtInfo().WriteToLog()
 
-	if updated {
-		s.ips[req.domain] = rec
-	}
+	s.cache.set(req.domain, req.reqType, ipRec)
 	switch req.reqType {
 	case dnsmessage.TypeA:
 		s.pub.Publish(req.domain+"4", nil)
 	case dnsmessage.TypeAAAA:
 		s.pub.Publish(req.domain+"6", nil)
 	}
-	s.Unlock()
 	common.Must(s.cleanup.Start())
 }
 
@@ -310,46 +275,23 @@ func (s *DoHNameServer) dohHTTPSContext(ctx context.Context, b []byte) ([]byte,
 	return io.ReadAll(resp.Body)
 }
 
-func (s *DoHNameServer) findIPsForDomain(domain string, option dns_feature.IPOption) ([]net.IP, error) {
-	s.RLock()
-	record, found := s.ips[domain]
-	s.RUnlock()
-
-	if !found {
-		return nil, errRecordNotFound
-	}
-
-	var err4 error
-	var err6 error
-	var ips []net.Address
-	var ip6 []net.Address
-
-	if option.IPv4Enable {
-		ips, err4 = record.A.getIPs()
-	}
-
-	if option.IPv6Enable {
-		ip6, err6 = record.AAAA.getIPs()
-		ips = append(ips, ip6...)
-	}
-
-	if len(ips) > 0 {
-		return toNetIP(ips)
-	}
-
-	if err4 != nil {
-		return nil, err4
-	}
-
-	if err6 != nil {
-		return nil, err6
-	}
+// QueryRaw implements rawQuerier.
+func (s *DoHNameServer) QueryRaw(ctx context.Context, domain string, clientIP net.IP, qType dnsmessage.Type, disableCache bool) (*dnsmessage.Message, error) {
+	req := buildRawReqMsg(Fqdn(domain), qType, s.newReqID, genEDNS0Options(clientIP))
+	return queryRaw(ctx, s.name, s.cache, req, disableCache, s.exchangeRaw)
+}
 
-	if (option.IPv4Enable && record.A != nil) || (option.IPv6Enable && record.AAAA != nil) {
-		return nil, dns_feature.ErrEmptyResponse
+func (s *DoHNameServer) exchangeRaw(ctx context.Context, req *dnsRequest) ([]byte, error) {
+	b, err := dns.PackMessage(req.msg)
+	if err != nil {
+		return nil, newError("failed to pack dns query").Base(err)
 	}
-
-	return nil, errRecordNotFound
+	defer b.Release()
+	ctx = session.ContextWithContent(ctx, &session.Content{
+		Protocol:       "https",
+		SkipDNSResolve: true,
+	})
+	return s.dohHTTPSContext(ctx, b.Bytes())
 }
 
 // QueryIP implements Server.
@@ -363,9 +305,13 @@ func (s *DoHNameServer) QueryIP(ctx context.Context, domain string, clientIP net
 	if disableCache {
 		newError("DNS cache is disabled. Querying IP for ", domain, " at ", s.name).AtDebug().WriteToLog()
 	} else {
-		ips, err := s.findIPsForDomain(fqdn, option)
+		ips, refresh, err := s.cache.get(fqdn, option, true)
 		if err != errRecordNotFound {
+			if refresh {
+				s.sendQuery(refreshContext(ctx), fqdn, clientIP, option)
+			}
 			newError(s.name, " cache HIT ", domain, " -> ", ips).Base(err).AtDebug().WriteToLog()
+			markCacheHit(ctx)
 			log.Record(&log.DNSLog{Server: s.name, Domain: domain, Result: ips, Status: log.DNSCacheHit, Elapsed: 0, Error: err})
 			return ips, err
 		}
@@ -401,7 +347,7 @@ func (s *DoHNameServer) QueryIP(ctx context.Context, domain string, clientIP net
 	start := time.Now()
 
 	for {
-		ips, err := s.findIPsForDomain(fqdn, option)
+		ips, _, err := s.cache.get(fqdn, option, false)
 		if err != errRecordNotFound {
 			log.Record(&log.DNSLog{Server: s.name, Domain: domain, Result: ips, Status: log.DNSQueried, Elapsed: time.Since(start), Error: err})
 			return ips, err
@@ -33,7 +33,7 @@ const handshakeTimeout = time.Second * 8
 // QUICNameServer implemented DNS over QUIC
 type QUICNameServer struct {
 	sync.RWMutex
-	ips           map[string]*record
+	cache         *recordCache
 	pub           *pubsub.Service
 	cleanup       *task.Periodic
 	reqID         uint32
@@ -58,7 +58,7 @@ func NewQUICNameServer(url *url.URL, queryStrategy QueryStrategy) (*QUICNameServ
 	dest := net.UDPDestination(net.ParseAddress(url.Hostname()), port)
 
 	s := &QUICNameServer{
-		ips:           make(map[string]*record),
+		cache:         newRecordCache(),
 		pub:           pubsub.NewService(),
 		name:          url.String(),
 		destination:   &dest,
@@ -77,80 +77,50 @@ func (s *QUICNameServer) Name() string {
 	return s.name
 }
 
-// Cleanup clears expired items from cache
-func (s *QUICNameServer) Cleanup() error {
-	now := time.Now()
-	s.Lock()
-	defer s.Unlock()
-
-	if len(s.ips) == 0 {
-		return newError("nothing to do. stopping...")
-	}
-
-	for domain, record := range s.ips {
-		if record.A != nil && record.A.Expire.Before(now) {
-			record.A = nil
-		}
-		if record.AAAA != nil && record.AAAA.Expire.Before(now) {
-			record.AAAA = nil
-		}
+func (s *QUICNameServer) recordCache() *recordCache {
+	return s.cache
+}
 
-		if record.A == nil && record.AAAA == nil {
-			newError(s.name, " cleanup ", domain).AtDebug().WriteToLog()
-			delete(s.ips, domain)
-		} else {
-			s.ips[domain] = record
-		}
+// Close implements common.Closable.
+func (s *QUICNameServer) Close() error {
+	s.Lock()
+	if s.connection != nil {
+		_ = s.connection.CloseWithError(0, "")
+		s.connection = nil
 	}
+	s.Unlock()
+	return s.cleanup.Close()
+}
 
-	if len(s.ips) == 0 {
-		s.ips = make(map[string]*record)
+// Cleanup clears expired items from cache
+func (s *QUICNameServer) Cleanup() error {
+	if s.cache.cleanup(s.name) == 0 {
+		return newError("nothing to do. stopping...")
 	}
-
 	return nil
 }
 
 func (s *QUICNameServer) updateIP(req *dnsRequest, ipRec *IPRecord) {
 	elapsed := time.Since(req.start)
 
-	s.Lock()
-	rec, found := s.ips[req.domain]
-	if !found {
-		rec = &record{}
-	}
-	updated := false
-
-	switch req.reqType {
-	case dnsmessage.TypeA:
-		if isNewer(rec.A, ipRec) {
-			rec.A = ipRec
-			updated = true
-		}
-	case dnsmessage.TypeAAAA:
-		addr := make([]net.Address, 0)
+	if req.reqType == dnsmessage.TypeAAAA {
+		addr := make([]net.Address, 0, len(ipRec.IP))
 		for _, ip := range ipRec.IP {
 			if len(ip.IP()) == net.IPv6len {
 				addr = append(addr, ip)
 			}
 		}
 		ipRec.IP = addr
-		if isNewer(rec.AAAA, ipRec) {
-			rec.AAAA = ipRec
-			updated = true
-		}
 	}
 	newError(s.name, " got answer: ", req.domain, " ", req.reqType, " -> ", ipRec.IP, " ", elapsed).AtInfo().WriteToLog()
 
-	if updated {
-		s.ips[req.domain] = rec
-	}
+	s.cache.set(req.domain, req.reqType, ipRec)
 	switch req.reqType {
 	case dnsmessage.TypeA:
 		s.pub.Publish(req.domain+"4", nil)
 	case dnsmessage.TypeAAAA:
 		s.pub.Publish(req.domain+"6", nil)
 	}
-	s.Unlock()
 	common.Must(s.cleanup.Start())
 }
 
@@ -196,46 +166,13 @@ func (s *QUICNameServer) sendQuery(ctx context.Context, domain string, clientIP
 				return
 			}
 
-			dnsReqBuf := buf.New()
-			binary.Write(dnsReqBuf, binary.BigEndian, uint16(b.Len()))
-			dnsReqBuf.Write(b.Bytes())
-			b.Release()
-
-			conn, err := s.openStream(dnsCtx)
-			if err != nil {
-				newError("failed to open quic connection").Base(err).AtError().WriteToLog()
-				return
-			}
-
-			_, err = conn.Write(dnsReqBuf.Bytes())
-			if err != nil {
-				newError("failed to send query").Base(err).AtError().WriteToLog()
-				return
-			}
-
-			_ = conn.Close()
-
-			respBuf := buf.New()
-			defer respBuf.Release()
-			n, err := respBuf.ReadFullFrom(conn, 2)
-			if err != nil && n == 0 {
-				newError("failed to read response length").Base(err).AtError().WriteToLog()
-				return
-			}
-			var length int16
-			err = binary.Read(bytes.NewReader(respBuf.Bytes()), binary.BigEndian, &length)
+			resp, err := s.exchange(dnsCtx, b)
 			if err != nil {
-				newError("failed to parse response length").Base(err).AtError().WriteToLog()
-				return
-			}
-			respBuf.Clear()
-			n, err = respBuf.ReadFullFrom(conn, int32(length))
-			if err != nil && n == 0 {
-				newError("failed to read response length").Base(err).AtError().WriteToLog()
+				newError(s.name, " failed to query ", r.domain).Base(err).AtError().WriteToLog()
 				return
 			}
 
-			rec, err := parseResponse(respBuf.Bytes())
+			rec, err := parseResponse(resp)
 			if err != nil {
 				newError("failed to handle response").Base(err).AtError().WriteToLog()
 				return
@@ -245,46 +182,61 @@ func (s *QUICNameServer) sendQuery(ctx context.Context, domain string, clientIP
 	}
 }
 
-func (s *QUICNameServer) findIPsForDomain(domain string, option dns_feature.IPOption) ([]net.IP, error) {
-	s.RLock()
-	record, found := s.ips[domain]
-	s.RUnlock()
+// exchange sends the query over a new stream and returns the response.
+func (s *QUICNameServer) exchange(ctx context.Context, b *buf.Buffer) ([]byte, error) {
+	dnsReqBuf := buf.New()
+	defer dnsReqBuf.Release()
+	binary.Write(dnsReqBuf, binary.BigEndian, uint16(b.Len()))
+	dnsReqBuf.Write(b.Bytes())
+	b.Release()
 
-	if !found {
-		return nil, errRecordNotFound
+	conn, err := s.openStream(ctx)
+	if err != nil {
+		return nil, newError("failed to open quic connection").Base(err)
 	}
 
-	var err4 error
-	var err6 error
-	var ips []net.Address
-	var ip6 []net.Address
-
-	if option.IPv4Enable {
-		ips, err4 = record.A.getIPs()
+	_, err = conn.Write(dnsReqBuf.Bytes())
+	if err != nil {
+		return nil, newError("failed to send query").Base(err)
 	}
 
-	if option.IPv6Enable {
-		ip6, err6 = record.AAAA.getIPs()
-		ips = append(ips, ip6...)
-	}
+	_ = conn.Close()
 
-	if len(ips) > 0 {
-		return toNetIP(ips)
+	respBuf := buf.New()
+	defer respBuf.Release()
+	n, err := respBuf.ReadFullFrom(conn, 2)
+	if err != nil && n == 0 {
+		return nil, newError("failed to read response length").Base(err)
 	}
-
-	if err4 != nil {
-		return nil, err4
+	var length int16
+	err = binary.Read(bytes.NewReader(respBuf.Bytes()), binary.BigEndian, &length)
+	if err != nil {
+		return nil, newError("failed to parse response length").Base(err)
 	}
-
-	if err6 != nil {
-		return nil, err6
+	respBuf.Clear()
+	n, err = respBuf.ReadFullFrom(conn, int32(length))
+	if err != nil && n == 0 {
+		return nil, newError("failed to read response length").Base(err)
 	}
+	return append([]byte(nil), respBuf.Bytes()...), nil
+}
 
-	if (option.IPv4Enable && record.A != nil) || (option.IPv6Enable && record.AAAA != nil) {
-		return nil, dns_feature.ErrEmptyResponse
-	}
+// QueryRaw implements rawQuerier.
+func (s *QUICNameServer) QueryRaw(ctx context.Context, domain string, clientIP net.IP, qType dnsmessage.Type, disableCache bool) (*dnsmessage.Message, error) {
+	req := buildRawReqMsg(Fqdn(domain), qType, s.newReqID, genEDNS0Options(clientIP))
+	return queryRaw(ctx, s.name, s.cache, req, disableCache, s.exchangeRaw)
+}
 
-	return nil, errRecordNotFound
+func (s *QUICNameServer) exchangeRaw(ctx context.Context, req *dnsRequest) ([]byte, error) {
+	b, err := dns.PackMessage(req.msg)
+	if err != nil {
+		return nil, newError("failed to pack dns query").Base(err)
+	}
+	ctx = session.ContextWithContent(ctx, &session.Content{
+		Protocol:       "quic",
+		SkipDNSResolve: true,
+	})
+	return s.exchange(ctx, b)
 }
 
 // QueryIP is called from dns.Server->queryIPTimeout
@@ -298,9 +250,13 @@ func (s *QUICNameServer) QueryIP(ctx context.Context, domain string, clientIP ne
 	if disableCache {
 		newError("DNS cache is disabled. Querying IP for ", domain, " at ", s.name).AtDebug().WriteToLog()
 	} else {
-		ips, err := s.findIPsForDomain(fqdn, option)
+		ips, refresh, err := s.cache.get(fqdn, option, true)
 		if err != errRecordNotFound {
+			if refresh {
+				s.sendQuery(refreshContext(ctx), fqdn, clientIP, option)
+			}
 			newError(s.name, " cache HIT ", domain, " -> ", ips).Base(err).AtDebug().WriteToLog()
+			markCacheHit(ctx)
 			log.Record(&log.DNSLog{Server: s.name, Domain: domain, Result: ips, Status: log.DNSCacheHit, Elapsed: 0, Error: err})
 			return ips, err
 		}
@@ -336,7 +292,7 @@ func (s *QUICNameServer) QueryIP(ctx context.Context, domain string, clientIP ne
 	start := time.Now()
 
 	for {
-		ips, err := s.findIPsForDomain(fqdn, option)
+		ips, _, err := s.cache.get(fqdn, option, false)
 		if err != errRecordNotFound {
 			log.Record(&log.DNSLog{Server: s.name, Domain: domain, Result: ips, Status: log.DNSQueried, Elapsed: time.Since(start), Error: err})
 			return ips, err
@@ -29,12 +29,14 @@ type TCPNameServer struct {
 	sync.RWMutex
 	name          string
 	destination   *net.Destination
-	ips           map[string]*record
+	cache         *recordCache
 	pub           *pubsub.Service
 	cleanup       *task.Periodic
 	reqID         uint32
 	dial          func(context.Context) (net.Conn, error)
 	queryStrategy QueryStrategy
+	// pipeline is used instead of a new connection per query if not nil.
+	pipeline *dnsPipeline
 }
 
 // NewTCPNameServer creates DNS over TCP server object for remote resolving.
@@ -78,7 +80,10 @@ func NewTCPLocalNameServer(url *url.URL, queryStrategy QueryStrategy) (*TCPNameS
 }
 
 func baseTCPNameServer(url *url.URL, prefix string, queryStrategy QueryStrategy) (*TCPNameServer, error) {
-	port := net.Port(53)
+	return baseStreamNameServer(url, prefix, net.Port(53), queryStrategy)
+}
+
+func baseStreamNameServer(url *url.URL, prefix string, port net.Port, queryStrategy QueryStrategy) (*TCPNameServer, error) {
 	if url.Port() != "" {
 		var err error
 		if port, err = net.PortFromString(url.Port()); err != nil {
@@ -89,7 +94,7 @@ func baseTCPNameServer(url *url.URL, prefix string, queryStrategy QueryStrategy)
 
 	s := &TCPNameServer{
 		destination:   &dest,
-		ips:           make(map[string]*record),
+		cache:         newRecordCache(),
 		pub:           pubsub.NewService(),
 		name:          prefix + "//" + dest.NetAddr(),
 		queryStrategy: queryStrategy,
@@ -107,80 +112,47 @@ func (s *TCPNameServer) Name() string {
 	return s.name
 }
 
-// Cleanup clears expired items from cache
-func (s *TCPNameServer) Cleanup() error {
-	now := time.Now()
-	s.Lock()
-	defer s.Unlock()
-
-	if len(s.ips) == 0 {
-		return newError("nothing to do. stopping...")
-	}
-
-	for domain, record := range s.ips {
-		if record.A != nil && record.A.Expire.Before(now) {
-			record.A = nil
-		}
-		if record.AAAA != nil && record.AAAA.Expire.Before(now) {
-			record.AAAA = nil
-		}
+func (s *TCPNameServer) recordCache() *recordCache {
+	return s.cache
+}
 
-		if record.A == nil && record.AAAA == nil {
-			newError(s.name, " cleanup ", domain).AtDebug().WriteToLog()
-			delete(s.ips, domain)
-		} else {
-			s.ips[domain] = record
-		}
+// Close implements common.Closable.
+func (s *TCPNameServer) Close() error {
+	if s.pipeline != nil {
+		s.pipeline.close()
 	}
+	return s.cleanup.Close()
+}
 
-	if len(s.ips) == 0 {
-		s.ips = make(map[string]*record)
+// Cleanup clears expired items from cache
+func (s *TCPNameServer) Cleanup() error {
+	if s.cache.cleanup(s.name) == 0 {
+		return newError("nothing to do. stopping...")
 	}
-
 	return nil
 }
 
 func (s *TCPNameServer) updateIP(req *dnsRequest, ipRec *IPRecord) {
 	elapsed := time.Since(req.start)
 
-	s.Lock()
-	rec, found := s.ips[req.domain]
-	if !found {
-		rec = &record{}
-	}
-	updated := false
-
-	switch req.reqType {
-	case dnsmessage.TypeA:
-		if isNewer(rec.A, ipRec) {
-			rec.A = ipRec
-			updated = true
-		}
-	case dnsmessage.TypeAAAA:
-		addr := make([]net.Address, 0)
+	if req.reqType == dnsmessage.TypeAAAA {
+		addr := make([]net.Address, 0, len(ipRec.IP))
 		for _, ip := range ipRec.IP {
 			if len(ip.IP()) == net.IPv6len {
 				addr = append(addr, ip)
 			}
 		}
 		ipRec.IP = addr
-		if isNewer(rec.AAAA, ipRec) {
-			rec.AAAA = ipRec
-			updated = true
-		}
 	}
 	newError(s.name, " got answer: ", req.domain, " ", req.reqType, " -> ", ipRec.IP, " ", elapsed).AtInfo().WriteToLog()
 
-	if updated {
-		s.ips[req.domain] = rec
-	}
+	s.cache.set(req.domain, req.reqType, ipRec)
 	switch req.reqType {
 	case dnsmessage.TypeA:
 		s.pub.Publish(req.domain+"4", nil)
 	case dnsmessage.TypeAAAA:
 		s.pub.Publish(req.domain+"6", nil)
 	}
-	s.Unlock()
 	common.Must(s.cleanup.Start())
 }
 
@@ -223,45 +195,18 @@ func (s *TCPNameServer) sendQuery(ctx context.Context, domain string, clientIP n
 				return
 			}
 
-			conn, err := s.dial(dnsCtx)
-			if err != nil {
-				newError("failed to dial namesever").Base(err).AtError().WriteToLog()
-				return
-			}
-			defer conn.Close()
-			dnsReqBuf := buf.New()
-			binary.Write(dnsReqBuf, binary.BigEndian, uint16(b.Len()))
-			dnsReqBuf.Write(b.Bytes())
-			b.Release()
-
-			_, err = conn.Write(dnsReqBuf.Bytes())
-			if err != nil {
-				newError("failed to send query").Base(err).AtError().WriteToLog()
-				return
+			var resp []byte
+			if s.pipeline != nil {
+				resp, err = s.pipeline.exchange(dnsCtx, r.msg.ID, b)
+			} else {
+				resp, err = s.exchange(dnsCtx, b)
 			}
-			dnsReqBuf.Release()
-
-			respBuf := buf.New()
-			defer respBuf.Release()
-			n, err := respBuf.ReadFullFrom(conn, 2)
-			if err != nil && n == 0 {
-				newError("failed to read response length").Base(err).AtError().WriteToLog()
-				return
-			}
-			var length int16
-			err = binary.Read(bytes.NewReader(respBuf.Bytes()), binary.BigEndian, &length)
 			if err != nil {
-				newError("failed to parse response length").Base(err).AtError().WriteToLog()
-				return
-			}
-			respBuf.Clear()
-			n, err = respBuf.ReadFullFrom(conn, int32(length))
-			if err != nil && n == 0 {
-				newError("failed to read response length").Base(err).AtError().WriteToLog()
+				newError(s.name, " failed to query ", r.domain).Base(err).AtError().WriteToLog()
 				return
 			}
 
-			rec, err := parseResponse(respBuf.Bytes())
+			rec, err := parseResponse(resp)
 			if err != nil {
 				newError("failed to parse DNS over TCP response").Base(err).AtError().WriteToLog()
 				return
@@ -272,42 +217,62 @@ func (s *TCPNameServer) sendQuery(ctx context.Context, domain string, clientIP n
 	}
 }
 
-func (s *TCPNameServer) findIPsForDomain(domain string, option dns_feature.IPOption) ([]net.IP, error) {
-	s.RLock()
-	record, found := s.ips[domain]
-	s.RUnlock()
-
-	if !found {
-		return nil, errRecordNotFound
+// exchange sends the query over a new connection and returns the response.
+func (s *TCPNameServer) exchange(ctx context.Context, b *buf.Buffer) ([]byte, error) {
+	conn, err := s.dial(ctx)
+	if err != nil {
+		return nil, newError("failed to dial namesever").Base(err)
 	}
+	defer conn.Close()
+	dnsReqBuf := buf.New()
+	binary.Write(dnsReqBuf, binary.BigEndian, uint16(b.Len()))
+	dnsReqBuf.Write(b.Bytes())
+	b.Release()
 
-	var err4 error
-	var err6 error
-	var ips []net.Address
-	var ip6 []net.Address
-
-	if option.IPv4Enable {
-		ips, err4 = record.A.getIPs()
+	_, err = conn.Write(dnsReqBuf.Bytes())
+	if err != nil {
+		return nil, newError("failed to send query").Base(err)
 	}
+	dnsReqBuf.Release()
 
-	if option.IPv6Enable {
-		ip6, err6 = record.AAAA.getIPs()
-		ips = append(ips, ip6...)
+	respBuf := buf.New()
+	defer respBuf.Release()
+	n, err := respBuf.ReadFullFrom(conn, 2)
+	if err != nil && n == 0 {
+		return nil, newError("failed to read response length").Base(err)
 	}
-
-	if len(ips) > 0 {
-		return toNetIP(ips)
+	var length int16
+	err = binary.Read(bytes.NewReader(respBuf.Bytes()), binary.BigEndian, &length)
+	if err != nil {
+		return nil, newError("failed to parse response length").Base(err)
 	}
-
-	if err4 != nil {
-		return nil, err4
+	respBuf.Clear()
+	n, err = respBuf.ReadFullFrom(conn, int32(length))
+	if err != nil && n == 0 {
+		return nil, newError("failed to read response length").Base(err)
 	}
+	return append([]byte(nil), respBuf.Bytes()...), nil
+}
 
-	if err6 != nil {
-		return nil, err6
-	}
+// QueryRaw implements rawQuerier.
+func (s *TCPNameServer) QueryRaw(ctx context.Context, domain string, clientIP net.IP, qType dnsmessage.Type, disableCache bool) (*dnsmessage.Message, error) {
+	req := buildRawReqMsg(Fqdn(domain), qType, s.newReqID, genEDNS0Options(clientIP))
+	return queryRaw(ctx, s.name, s.cache, req, disableCache, s.exchangeRaw)
+}
 
-	return nil, dns_feature.ErrEmptyResponse
+func (s *TCPNameServer) exchangeRaw(ctx context.Context, req *dnsRequest) ([]byte, error) {
+	b, err := dns.PackMessage(req.msg)
+	if err != nil {
+		return nil, newError("failed to pack dns query").Base(err)
+	}
+	ctx = session.ContextWithContent(ctx, &session.Content{
+		Protocol:       "dns",
+		SkipDNSResolve: true,
+	})
+	if s.pipeline != nil {
+		return s.pipeline.exchange(ctx, req.msg.ID, b)
+	}
+	return s.exchange(ctx, b)
 }
 
 // QueryIP implements Server.
@@ -321,9 +286,13 @@ func (s *TCPNameServer) QueryIP(ctx context.Context, domain string, clientIP net
 	if disableCache {
 		newError("DNS cache is disabled. Querying IP for ", domain, " at ", s.name).AtDebug().WriteToLog()
 	} else {
-		ips, err := s.findIPsForDomain(fqdn, option)
+		ips, refresh, err := s.cache.get(fqdn, option, true)
 		if err != errRecordNotFound {
+			if refresh {
+				s.sendQuery(refreshContext(ctx), fqdn, clientIP, option)
+			}
 			newError(s.name, " cache HIT ", domain, " -> ", ips).Base(err).AtDebug().WriteToLog()
+			markCacheHit(ctx)
 			log.Record(&log.DNSLog{Server: s.name, Domain: domain, Result: ips, Status: log.DNSCacheHit, Elapsed: 0, Error: err})
 			return ips, err
 		}
@@ -359,7 +328,7 @@ func (s *TCPNameServer) QueryIP(ctx context.Context, domain string, clientIP net
 	start := time.Now()
 
 	for {
-		ips, err := s.findIPsForDomain(fqdn, option)
+		ips, _, err := s.cache.get(fqdn, option, false)
 		if err != errRecordNotFound {
 			log.Record(&log.DNSLog{Server: s.name, Domain: domain, Result: ips, Status: log.DNSQueried, Elapsed: time.Since(start), Error: err})
 			return ips, err
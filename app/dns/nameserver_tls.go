@@ -0,0 +1,255 @@
+package dns
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/xtls/xray-core/common/buf"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/net/cnc"
+	"github.com/xtls/xray-core/features/routing"
+	"github.com/xtls/xray-core/transport/internet"
+	"github.com/xtls/xray-core/transport/internet/tls"
+)
+
+// NextProtoDoT is the ALPN token of DNS over TLS (RFC7858).
+const NextProtoDoT = "dot"
+
+// pipelineIdleTimeout is how long an idle pipelined connection is reused. Servers usually close idle connections
+// after a while, and a silently dropped connection would only be noticed by queries timing out.
+const pipelineIdleTimeout = time.Second * 30
+
+// NewTLSNameServer creates DNS over TLS server object for remote resolving.
+func NewTLSNameServer(url *url.URL, dispatcher routing.Dispatcher, queryStrategy QueryStrategy) (*TCPNameServer, error) {
+	s, err := baseStreamNameServer(url, "TLS", net.Port(853), queryStrategy)
+	if err != nil {
+		return nil, err
+	}
+
+	s.pipeline = newDNSPipeline(func(ctx context.Context) (net.Conn, error) {
+		link, err := dispatcher.Dispatch(toDnsContext(ctx, s.destination.String()), *s.destination)
+		if err != nil {
+			return nil, err
+		}
+
+		return tlsClient(ctx, cnc.NewConnection(
+			cnc.ConnectionInputMulti(link.Writer),
+			cnc.ConnectionOutputMulti(link.Reader),
+		), s.destination.Address)
+	})
+
+	return s, nil
+}
+
+// NewTLSLocalNameServer creates DNS over TLS client object for local resolving.
+func NewTLSLocalNameServer(url *url.URL, queryStrategy QueryStrategy) (*TCPNameServer, error) {
+	s, err := baseStreamNameServer(url, "TLSL", net.Port(853), queryStrategy)
+	if err != nil {
+		return nil, err
+	}
+
+	s.pipeline = newDNSPipeline(func(ctx context.Context) (net.Conn, error) {
+		conn, err := internet.DialSystem(ctx, *s.destination, nil)
+		if err != nil {
+			return nil, err
+		}
+		return tlsClient(ctx, conn, s.destination.Address)
+	})
+
+	return s, nil
+}
+
+func tlsClient(ctx context.Context, conn net.Conn, address net.Address) (net.Conn, error) {
+	config := tls.Config{
+		ServerName: address.String(),
+	}
+	tlsConn := tls.Client(conn, config.GetTLSConfig(tls.WithNextProto(NextProtoDoT)))
+	if err := tlsConn.(*tls.Conn).HandshakeContext(ctx); err != nil {
+		conn.Close()
+		return nil, newError("failed to handshake with ", address).Base(err)
+	}
+	return tlsConn, nil
+}
+
+// dnsPipeline sends queries over a reused stream connection, without waiting for the responses of previous
+// queries (RFC7766 6.2.1.1). Responses are matched to queries by their IDs.
+type dnsPipeline struct {
+	dial func(context.Context) (net.Conn, error)
+
+	access sync.Mutex
+	conn   *pipelineConn
+}
+
+func newDNSPipeline(dial func(context.Context) (net.Conn, error)) *dnsPipeline {
+	return &dnsPipeline{
+		dial: dial,
+	}
+}
+
+// pipelineConn is a connection with queries in flight.
+type pipelineConn struct {
+	net.Conn
+	writeAccess sync.Mutex
+
+	access   sync.Mutex
+	pending  map[uint16]chan []byte
+	lastUsed time.Time
+	closed   bool
+}
+
+// exchange sends the query with the given ID and returns the response. The query buffer is released.
+func (p *dnsPipeline) exchange(ctx context.Context, id uint16, b *buf.Buffer) ([]byte, error) {
+	req := buf.New()
+	binary.Write(req, binary.BigEndian, uint16(b.Len()))
+	req.Write(b.Bytes())
+	b.Release()
+	defer req.Release()
+
+	// A reused connection may have been closed by the server, so retry once on a new connection.
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		var conn *pipelineConn
+		conn, err = p.getConn(ctx)
+		if err != nil {
+			return nil, err
+		}
+		resp := make(chan []byte, 1)
+		if err = conn.send(id, req.Bytes(), resp); err != nil {
+			continue
+		}
+
+		select {
+		case msg, ok := <-resp:
+			if ok {
+				return msg, nil
+			}
+			err = newError("connection closed before response")
+		case <-ctx.Done():
+			conn.cancel(id)
+			return nil, ctx.Err()
+		}
+	}
+	return nil, newError("failed to query namesever").Base(err)
+}
+
+// getConn returns the current connection, or a new one if there is none or it has been idle for too long.
+func (p *dnsPipeline) getConn(ctx context.Context) (*pipelineConn, error) {
+	p.access.Lock()
+	defer p.access.Unlock()
+
+	if p.conn != nil && p.conn.usable() {
+		return p.conn, nil
+	}
+	if p.conn != nil {
+		p.conn.close()
+	}
+
+	conn, err := p.dial(ctx)
+	if err != nil {
+		return nil, newError("failed to dial namesever").Base(err)
+	}
+	p.conn = &pipelineConn{
+		Conn:     conn,
+		pending:  make(map[uint16]chan []byte),
+		lastUsed: time.Now(),
+	}
+	go p.conn.readResponses()
+	return p.conn, nil
+}
+
+// close closes the current connection.
+func (p *dnsPipeline) close() {
+	p.access.Lock()
+	defer p.access.Unlock()
+
+	if p.conn != nil {
+		p.conn.close()
+		p.conn = nil
+	}
+}
+
+func (c *pipelineConn) usable() bool {
+	c.access.Lock()
+	defer c.access.Unlock()
+
+	if c.closed {
+		return false
+	}
+	return len(c.pending) > 0 || time.Since(c.lastUsed) < pipelineIdleTimeout
+}
+
+func (c *pipelineConn) send(id uint16, req []byte, resp chan []byte) error {
+	c.access.Lock()
+	if c.closed {
+		c.access.Unlock()
+		return io.ErrClosedPipe
+	}
+	c.pending[id] = resp
+	c.lastUsed = time.Now()
+	c.access.Unlock()
+
+	// Writes are serialized, so queries are never interleaved.
+	c.writeAccess.Lock()
+	_, err := c.Write(req)
+	c.writeAccess.Unlock()
+	if err != nil {
+		c.cancel(id)
+		c.close()
+		return err
+	}
+	return nil
+}
+
+func (c *pipelineConn) cancel(id uint16) {
+	c.access.Lock()
+	defer c.access.Unlock()
+
+	delete(c.pending, id)
+}
+
+func (c *pipelineConn) readResponses() {
+	defer c.close()
+
+	var header [2]byte
+	for {
+		if _, err := io.ReadFull(c.Conn, header[:]); err != nil {
+			return
+		}
+		msg := make([]byte, binary.BigEndian.Uint16(header[:]))
+		if _, err := io.ReadFull(c.Conn, msg); err != nil {
+			return
+		}
+		if len(msg) < 2 {
+			continue
+		}
+		id := binary.BigEndian.Uint16(msg)
+
+		c.access.Lock()
+		if resp, found := c.pending[id]; found {
+			delete(c.pending, id)
+			resp <- msg
+		}
+		c.lastUsed = time.Now()
+		c.access.Unlock()
+	}
+}
+
+// close closes the connection, and fails all queries in flight.
+func (c *pipelineConn) close() {
+	c.access.Lock()
+	defer c.access.Unlock()
+
+	if c.closed {
+		return
+	}
+	c.closed = true
+	c.Conn.Close()
+	for id, resp := range c.pending {
+		delete(c.pending, id)
+		close(resp)
+	}
+}
@@ -0,0 +1,72 @@
+package dns
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	gonet "net"
+	"testing"
+	"time"
+
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/buf"
+	"github.com/xtls/xray-core/common/net"
+)
+
+func TestDNSPipeline(t *testing.T) {
+	var dials int
+	p := newDNSPipeline(func(ctx context.Context) (net.Conn, error) {
+		dials++
+		client, server := gonet.Pipe()
+		// The server answers a pair of queries in the reverse order.
+		go func() {
+			defer server.Close()
+			var queries [][]byte
+			for {
+				var header [2]byte
+				if _, err := io.ReadFull(server, header[:]); err != nil {
+					return
+				}
+				query := make([]byte, binary.BigEndian.Uint16(header[:]))
+				if _, err := io.ReadFull(server, query); err != nil {
+					return
+				}
+				queries = append(queries, query)
+				if len(queries) < 2 {
+					continue
+				}
+				for i := len(queries) - 1; i >= 0; i-- {
+					resp := append([]byte{0, byte(len(queries[i]))}, queries[i]...)
+					if _, err := server.Write(resp); err != nil {
+						return
+					}
+				}
+				queries = nil
+			}
+		}()
+		return client, nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	results := make(chan error, 4)
+	for id := uint16(1); id <= 4; id++ {
+		go func(id uint16) {
+			b := buf.New()
+			binary.Write(b, binary.BigEndian, id)
+			b.WriteString("query")
+			resp, err := p.exchange(ctx, id, b)
+			if err == nil && binary.BigEndian.Uint16(resp) != id {
+				err = newError("got response of ", binary.BigEndian.Uint16(resp), " for query ", id)
+			}
+			results <- err
+		}(id)
+	}
+	for i := 0; i < 4; i++ {
+		common.Must(<-results)
+	}
+	if dials != 1 {
+		t.Error("expected queries on a single connection, but dialed ", dials, " times")
+	}
+}
@@ -26,7 +26,7 @@ type ClassicNameServer struct {
 	sync.RWMutex
 	name      string
 	address   *net.Destination
-	ips       map[string]*record
+	cache     *recordCache
 	requests  map[uint16]*dnsRequest
 	pub       *pubsub.Service
 	udpServer *udp.Dispatcher
@@ -43,7 +43,7 @@ func NewClassicNameServer(address net.Destination, dispatcher routing.Dispatcher
 
 	s := &ClassicNameServer{
 		address:  &address,
-		ips:      make(map[string]*record),
+		cache:    newRecordCache(),
 		requests: make(map[uint16]*dnsRequest),
 		pub:      pubsub.NewService(),
 		name:     strings.ToUpper(address.String()),
@@ -62,36 +62,27 @@ func (s *ClassicNameServer) Name() string {
 	return s.name
 }
 
+// Close implements common.Closable.
+func (s *ClassicNameServer) Close() error {
+	s.udpServer.RemoveRay()
+	return s.cleanup.Close()
+}
+
+func (s *ClassicNameServer) recordCache() *recordCache {
+	return s.cache
+}
+
 // Cleanup clears expired items from cache
 func (s *ClassicNameServer) Cleanup() error {
 	now := time.Now()
+	cached := s.cache.cleanup(s.name)
 	s.Lock()
 	defer s.Unlock()
 
-	if len(s.ips) == 0 && len(s.requests) == 0 {
+	if cached == 0 && len(s.requests) == 0 {
 		return newError(s.name, " nothing to do. stopping...")
 	}
 
-	for domain, record := range s.ips {
-		if record.A != nil && record.A.Expire.Before(now) {
-			record.A = nil
-		}
-		if record.AAAA != nil && record.AAAA.Expire.Before(now) {
-			record.AAAA = nil
-		}
-
-		if record.A == nil && record.AAAA == nil {
-			newError(s.name, " cleanup ", domain).AtDebug().WriteToLog()
-			delete(s.ips, domain)
-		} else {
-			s.ips[domain] = record
-		}
-	}
-
-	if len(s.ips) == 0 {
-		s.ips = make(map[string]*record)
-	}
-
 	for id, req := range s.requests {
 		if req.expire.Before(now) {
 			delete(s.requests, id)
@@ -125,51 +116,28 @@ func (s *ClassicNameServer) HandleResponse(ctx context.Context, packet *udp_prot
 		newError(s.name, " cannot find the pending request").AtError().WriteToLog()
 		return
 	}
-
-	var rec record
-	switch req.reqType {
-	case dnsmessage.TypeA:
-		rec.A = ipRec
-	case dnsmessage.TypeAAAA:
-		rec.AAAA = ipRec
+	if req.response != nil {
+		req.response <- append([]byte(nil), packet.Payload.Bytes()...)
+		return
 	}
 
 	elapsed := time.Since(req.start)
 	newError(s.name, " got answer: ", req.domain, " ", req.reqType, " -> ", ipRec.IP, " ", elapsed).AtInfo().WriteToLog()
-	if len(req.domain) > 0 && (rec.A != nil || rec.AAAA != nil) {
-		s.updateIP(req.domain, &rec)
+	if len(req.domain) > 0 {
+		s.updateIP(req, ipRec)
 	}
 }
 
-func (s *ClassicNameServer) updateIP(domain string, newRec *record) {
-	s.Lock()
-
-	rec, found := s.ips[domain]
-	if !found {
-		rec = &record{}
-	}
-
-	updated := false
-	if isNewer(rec.A, newRec.A) {
-		rec.A = newRec.A
-		updated = true
+func (s *ClassicNameServer) updateIP(req *dnsRequest, ipRec *IPRecord) {
+	if s.cache.set(req.domain, req.reqType, ipRec) {
+		newError(s.name, " updating IP records for domain:", req.domain).AtDebug().WriteToLog()
 	}
-	if isNewer(rec.AAAA, newRec.AAAA) {
-		rec.AAAA = newRec.AAAA
-		updated = true
-	}
-
-	if updated {
-		newError(s.name, " updating IP records for domain:", domain).AtDebug().WriteToLog()
-		s.ips[domain] = rec
-	}
-	if newRec.A != nil {
-		s.pub.Publish(domain+"4", nil)
-	}
-	if newRec.AAAA != nil {
-		s.pub.Publish(domain+"6", nil)
+	switch req.reqType {
+	case dnsmessage.TypeA:
+		s.pub.Publish(req.domain+"4", nil)
+	case dnsmessage.TypeAAAA:
+		s.pub.Publish(req.domain+"6", nil)
 	}
-	s.Unlock()
 	common.Must(s.cleanup.Start())
 }
 
@@ -198,42 +166,32 @@ func (s *ClassicNameServer) sendQuery(ctx context.Context, domain string, client
 	}
 }
 
-func (s *ClassicNameServer) findIPsForDomain(domain string, option dns_feature.IPOption) ([]net.IP, error) {
-	s.RLock()
-	record, found := s.ips[domain]
-	s.RUnlock()
-
-	if !found {
-		return nil, errRecordNotFound
-	}
-
-	var err4 error
-	var err6 error
-	var ips []net.Address
-	var ip6 []net.Address
-
-	if option.IPv4Enable {
-		ips, err4 = record.A.getIPs()
-	}
-
-	if option.IPv6Enable {
-		ip6, err6 = record.AAAA.getIPs()
-		ips = append(ips, ip6...)
-	}
-
-	if len(ips) > 0 {
-		return toNetIP(ips)
-	}
+// QueryRaw implements rawQuerier.
+func (s *ClassicNameServer) QueryRaw(ctx context.Context, domain string, clientIP net.IP, qType dnsmessage.Type, disableCache bool) (*dnsmessage.Message, error) {
+	req := buildRawReqMsg(Fqdn(domain), qType, s.newReqID, genEDNS0Options(clientIP))
+	return queryRaw(ctx, s.name, s.cache, req, disableCache, s.exchangeRaw)
+}
 
-	if err4 != nil {
-		return nil, err4
+func (s *ClassicNameServer) exchangeRaw(ctx context.Context, req *dnsRequest) ([]byte, error) {
+	b, err := dns.PackMessage(req.msg)
+	if err != nil {
+		return nil, newError("failed to pack dns query").Base(err)
 	}
+	response := make(chan []byte, 1)
+	req.response = response
+	s.addPendingRequest(req)
+	s.udpServer.Dispatch(toDnsContext(ctx, s.address.String()), *s.address, b)
+	common.Must(s.cleanup.Start())
 
-	if err6 != nil {
-		return nil, err6
+	select {
+	case resp := <-response:
+		return resp, nil
+	case <-ctx.Done():
+		s.Lock()
+		delete(s.requests, req.msg.ID)
+		s.Unlock()
+		return nil, ctx.Err()
 	}
-
-	return nil, dns_feature.ErrEmptyResponse
 }
 
 // QueryIP implements Server.
@@ -243,9 +201,13 @@ func (s *ClassicNameServer) QueryIP(ctx context.Context, domain string, clientIP
 	if disableCache {
 		newError("DNS cache is disabled. Querying IP for ", domain, " at ", s.name).AtDebug().WriteToLog()
 	} else {
-		ips, err := s.findIPsForDomain(fqdn, option)
+		ips, refresh, err := s.cache.get(fqdn, option, true)
 		if err != errRecordNotFound {
+			if refresh {
+				s.sendQuery(refreshContext(ctx), fqdn, clientIP, option)
+			}
 			newError(s.name, " cache HIT ", domain, " -> ", ips).Base(err).AtDebug().WriteToLog()
+			markCacheHit(ctx)
 			log.Record(&log.DNSLog{Server: s.name, Domain: domain, Result: ips, Status: log.DNSCacheHit, Elapsed: 0, Error: err})
 			return ips, err
 		}
@@ -281,7 +243,7 @@ func (s *ClassicNameServer) QueryIP(ctx context.Context, domain string, clientIP
 	start := time.Now()
 
 	for {
-		ips, err := s.findIPsForDomain(fqdn, option)
+		ips, _, err := s.cache.get(fqdn, option, false)
 		if err != errRecordNotFound {
 			log.Record(&log.DNSLog{Server: s.name, Domain: domain, Result: ips, Status: log.DNSQueried, Elapsed: time.Since(start), Error: err})
 			return ips, err
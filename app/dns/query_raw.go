@@ -0,0 +1,80 @@
+package dns
+
+import (
+	"context"
+	"time"
+
+	"github.com/xtls/xray-core/common/net"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// rawQuerier is a Server that resolves queries of any type.
+type rawQuerier interface {
+	// QueryRaw sends the query of the given type to its configured server, and returns the response.
+	QueryRaw(ctx context.Context, domain string, clientIP net.IP, qType dnsmessage.Type, disableCache bool) (*dnsmessage.Message, error)
+}
+
+// rawExchange sends the request to a name server, and returns the packed response.
+type rawExchange func(ctx context.Context, req *dnsRequest) ([]byte, error)
+
+func buildRawReqMsg(domain string, qType dnsmessage.Type, reqIDGen func() uint16, reqOpts *dnsmessage.Resource) *dnsRequest {
+	msg := new(dnsmessage.Message)
+	msg.Header.ID = reqIDGen()
+	msg.Header.RecursionDesired = true
+	msg.Questions = []dnsmessage.Question{{
+		Name:  dnsmessage.MustNewName(domain),
+		Type:  qType,
+		Class: dnsmessage.ClassINET,
+	}}
+	if reqOpts != nil {
+		msg.Additionals = append(msg.Additionals, *reqOpts)
+	}
+	return &dnsRequest{
+		reqType: qType,
+		domain:  domain,
+		start:   time.Now(),
+		msg:     msg,
+	}
+}
+
+// queryRaw answers the request from the cache, or from the name server by the exchange.
+func queryRaw(ctx context.Context, name string, cache *recordCache, req *dnsRequest, disableCache bool, exchange rawExchange) (*dnsmessage.Message, error) {
+	if disableCache {
+		newError("DNS cache is disabled. Querying ", req.reqType, " for ", req.domain, " at ", name).AtDebug().WriteToLog()
+	} else if msg, refresh, err := cache.getRaw(req.domain, req.reqType, true); err == nil {
+		if refresh {
+			go func() {
+				ctx, cancel := context.WithTimeout(refreshContext(ctx), time.Second*5)
+				defer cancel()
+				if _, err := exchangeRaw(ctx, name, cache, req, exchange); err != nil {
+					newError("failed to refresh ", req.domain, " ", req.reqType).Base(err).AtInfo().WriteToLog()
+				}
+			}()
+		}
+		newError(name, " cache HIT ", req.domain, " ", req.reqType).AtDebug().WriteToLog()
+		markCacheHit(ctx)
+		return msg, nil
+	}
+	return exchangeRaw(ctx, name, cache, req, exchange)
+}
+
+func exchangeRaw(ctx context.Context, name string, cache *recordCache, req *dnsRequest, exchange rawExchange) (*dnsmessage.Message, error) {
+	newError(name, " querying ", req.reqType, " for: ", req.domain).AtDebug().WriteToLog()
+	start := time.Now()
+	resp, err := exchange(ctx, req)
+	if err != nil {
+		return nil, newError(name, " failed to query ", req.domain).Base(err)
+	}
+
+	msg := new(dnsmessage.Message)
+	if err := msg.Unpack(resp); err != nil {
+		return nil, newError("failed to parse DNS response").Base(err)
+	}
+	if msg.ID != req.msg.ID {
+		return nil, newError("unexpected DNS response ID ", msg.ID)
+	}
+	newError(name, " got answer: ", req.domain, " ", req.reqType, " -> ", msg.RCode, " ", len(msg.Answers), " records ", time.Since(start)).AtInfo().WriteToLog()
+
+	cache.setRaw(req.domain, req.reqType, msg)
+	return msg, nil
+}
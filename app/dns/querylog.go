@@ -0,0 +1,189 @@
+package dns
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/xtls/xray-core/common/errors"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/session"
+	dns_feature "github.com/xtls/xray-core/features/dns"
+	"github.com/xtls/xray-core/features/stats"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+const defaultQueryLogSize = 128
+
+// queryLog keeps recent queries in a ring buffer.
+type queryLog struct {
+	sync.Mutex
+	records []*dns_feature.QueryRecord
+	next    int
+	full    bool
+}
+
+func newQueryLog(size int32) *queryLog {
+	switch {
+	case size < 0:
+		return nil
+	case size == 0:
+		size = defaultQueryLogSize
+	}
+	return &queryLog{
+		records: make([]*dns_feature.QueryRecord, size),
+	}
+}
+
+func (l *queryLog) add(r *dns_feature.QueryRecord) {
+	if l == nil {
+		return
+	}
+	l.Lock()
+	defer l.Unlock()
+
+	l.records[l.next] = r
+	l.next++
+	if l.next == len(l.records) {
+		l.next = 0
+		l.full = true
+	}
+}
+
+func (l *queryLog) recent() []*dns_feature.QueryRecord {
+	if l == nil {
+		return nil
+	}
+	l.Lock()
+	defer l.Unlock()
+
+	n := l.next
+	if l.full {
+		n = len(l.records)
+	}
+	records := make([]*dns_feature.QueryRecord, 0, n)
+	for i := 1; i <= n; i++ {
+		records = append(records, l.records[(l.next-i+len(l.records))%len(l.records)])
+	}
+	return records
+}
+
+// queryStats counts queries of each name server in the stats manager.
+type queryStats struct {
+	manager stats.Manager
+}
+
+func (s *queryStats) counter(server string, name string) stats.Counter {
+	if s == nil {
+		return nil
+	}
+	c, _ := stats.GetOrRegisterCounter(s.manager, "dns>>>"+server+">>>"+name)
+	return c
+}
+
+func (s *queryStats) add(r *dns_feature.QueryRecord) {
+	if c := s.counter(r.Server, "query"); c != nil {
+		c.Add(1)
+	}
+	if r.CacheHit {
+		if c := s.counter(r.Server, "cache_hit"); c != nil {
+			c.Add(1)
+		}
+	}
+	// Queries canceled by the caller, e.g. when another server answers first, are not failures.
+	if r.Error != nil && errors.Cause(r.Error) != context.Canceled {
+		if c := s.counter(r.Server, "failure"); c != nil {
+			c.Add(1)
+		}
+	}
+	if c := s.counter(r.Server, "elapsed_ms"); c != nil {
+		c.Add(r.Elapsed.Milliseconds())
+	}
+}
+
+type queryTraceKey int
+
+const queryTraceSessionKey queryTraceKey = 0
+
+// queryTrace collects what a name server did for a query.
+type queryTrace struct {
+	cacheHit bool
+}
+
+func contextWithQueryTrace(ctx context.Context, trace *queryTrace) context.Context {
+	return context.WithValue(ctx, queryTraceSessionKey, trace)
+}
+
+// markCacheHit marks that the query in the context is answered from the cache.
+func markCacheHit(ctx context.Context) {
+	if trace, ok := ctx.Value(queryTraceSessionKey).(*queryTrace); ok {
+		trace.cacheHit = true
+	}
+}
+
+func newQueryRecord(ctx context.Context, domain string, qType string, server string) *dns_feature.QueryRecord {
+	r := &dns_feature.QueryRecord{
+		Time:   time.Now(),
+		Domain: domain,
+		Type:   qType,
+		Server: server,
+	}
+	if inbound := session.InboundFromContext(ctx); inbound != nil {
+		r.Client = inbound.Source
+	}
+	return r
+}
+
+func ipQueryType(option dns_feature.IPOption) string {
+	switch {
+	case option.IPv4Enable && option.IPv6Enable:
+		return "A+AAAA"
+	case option.IPv6Enable:
+		return "AAAA"
+	default:
+		return "A"
+	}
+}
+
+func rawQueryType(qType dnsmessage.Type) string {
+	return strings.TrimPrefix(qType.String(), "Type")
+}
+
+func ipAnswers(ips []net.IP) []string {
+	answers := make([]string, 0, len(ips))
+	for _, ip := range ips {
+		answers = append(answers, ip.String())
+	}
+	return answers
+}
+
+func rawAnswers(msg *dnsmessage.Message) []string {
+	answers := make([]string, 0, len(msg.Answers))
+	for _, r := range msg.Answers {
+		var answer string
+		switch body := r.Body.(type) {
+		case *dnsmessage.AResource:
+			answer = net.IP(body.A[:]).String()
+		case *dnsmessage.AAAAResource:
+			answer = net.IP(body.AAAA[:]).String()
+		case *dnsmessage.CNAMEResource:
+			answer = body.CNAME.String()
+		case *dnsmessage.NSResource:
+			answer = body.NS.String()
+		case *dnsmessage.PTRResource:
+			answer = body.PTR.String()
+		case *dnsmessage.MXResource:
+			answer = strconv.Itoa(int(body.Pref)) + " " + body.MX.String()
+		case *dnsmessage.SRVResource:
+			answer = strconv.Itoa(int(body.Priority)) + " " + strconv.Itoa(int(body.Weight)) + " " + strconv.Itoa(int(body.Port)) + " " + body.Target.String()
+		case *dnsmessage.TXTResource:
+			answer = strings.Join(body.TXT, " ")
+		default:
+			answer = rawQueryType(r.Header.Type)
+		}
+		answers = append(answers, answer)
+	}
+	return answers
+}
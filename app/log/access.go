@@ -0,0 +1,163 @@
+package log
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/xtls/xray-core/common/log"
+	"github.com/xtls/xray-core/common/serial"
+)
+
+// accessField returns the value of a field in an access record, and whether the value is a string that needs
+// quoting. Empty values are omitted.
+type accessField func(r *accessRecord) (value string, quoted bool)
+
+var accessFields = map[string]accessField{
+	"time": func(r *accessRecord) (string, bool) {
+		return r.time.Format("2006-01-02T15:04:05.000Z07:00"), true
+	},
+	"status": func(r *accessRecord) (string, bool) {
+		return string(r.msg.Status), true
+	},
+	"from": func(r *accessRecord) (string, bool) {
+		return serial.ToString(r.msg.From), true
+	},
+	"to": func(r *accessRecord) (string, bool) {
+		return serial.ToString(r.msg.To), true
+	},
+	"inbound": func(r *accessRecord) (string, bool) {
+		return r.msg.InboundTag, true
+	},
+	"outbound": func(r *accessRecord) (string, bool) {
+		return r.msg.OutboundTag, true
+	},
+	"rule": func(r *accessRecord) (string, bool) {
+		return r.msg.RuleTag, true
+	},
+	"protocol": func(r *accessRecord) (string, bool) {
+		return r.msg.Protocol, true
+	},
+	"domain": func(r *accessRecord) (string, bool) {
+		return r.msg.Domain, true
+	},
+	"email": func(r *accessRecord) (string, bool) {
+		return r.msg.Email, true
+	},
+	"reason": func(r *accessRecord) (string, bool) {
+		return serial.ToString(r.msg.Reason), true
+	},
+	"duration": func(r *accessRecord) (string, bool) {
+		if r.msg.Status != log.AccessClosed {
+			return "", false
+		}
+		return strconv.FormatFloat(r.msg.Duration.Seconds(), 'f', 3, 64), false
+	},
+	"uplink": func(r *accessRecord) (string, bool) {
+		if r.msg.Status != log.AccessClosed {
+			return "", false
+		}
+		return strconv.FormatInt(r.msg.Uplink, 10), false
+	},
+	"downlink": func(r *accessRecord) (string, bool) {
+		if r.msg.Status != log.AccessClosed {
+			return "", false
+		}
+		return strconv.FormatInt(r.msg.Downlink, 10), false
+	},
+}
+
+// defaultAccessFields are the fields written when none is configured.
+var defaultAccessFields = []string{
+	"time", "status", "from", "to", "inbound", "outbound", "rule", "protocol", "domain", "email", "reason",
+	"duration", "uplink", "downlink",
+}
+
+// ValidateAccessFields returns an error if any of the given access log fields is unknown.
+func ValidateAccessFields(fields []string) error {
+	for _, field := range fields {
+		if _, found := accessFields[field]; !found {
+			return newError("unknown access log field: ", field)
+		}
+	}
+	return nil
+}
+
+// accessRecord is an access message in JSON or logfmt format.
+type accessRecord struct {
+	time   time.Time
+	msg    log.AccessMessage
+	format AccessLogFormat
+	fields []string
+}
+
+func newAccessRecord(msg *log.AccessMessage, format AccessLogFormat, fields []string) *accessRecord {
+	if len(fields) == 0 {
+		fields = defaultAccessFields
+	}
+	return &accessRecord{
+		time:   time.Now(),
+		msg:    *msg,
+		format: format,
+		fields: fields,
+	}
+}
+
+// String implements log.Message.
+func (r *accessRecord) String() string {
+	builder := strings.Builder{}
+	if r.format == AccessLogFormat_JSON {
+		builder.WriteByte('{')
+	}
+	first := true
+	for _, name := range r.fields {
+		field, found := accessFields[name]
+		if !found {
+			continue
+		}
+		value, quoted := field(r)
+		if len(value) == 0 {
+			continue
+		}
+		if r.format == AccessLogFormat_JSON {
+			if !first {
+				builder.WriteByte(',')
+			}
+			builder.WriteString(jsonString(name))
+			builder.WriteByte(':')
+			if quoted {
+				value = jsonString(value)
+			}
+		} else {
+			if !first {
+				builder.WriteByte(' ')
+			}
+			builder.WriteString(name)
+			builder.WriteByte('=')
+			if quoted && needsLogfmtQuoting(value) {
+				value = strconv.Quote(value)
+			}
+		}
+		builder.WriteString(value)
+		first = false
+	}
+	if r.format == AccessLogFormat_JSON {
+		builder.WriteByte('}')
+	}
+	return builder.String()
+}
+
+func jsonString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+func needsLogfmtQuoting(s string) bool {
+	for _, c := range s {
+		if c <= ' ' || c == '=' || c == '"' || c == '\\' || c == 0x7f {
+			return true
+		}
+	}
+	return false
+}
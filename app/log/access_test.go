@@ -0,0 +1,48 @@
+package log
+
+import (
+	"testing"
+	"time"
+
+	"github.com/xtls/xray-core/common/log"
+	"github.com/xtls/xray-core/common/net"
+)
+
+func TestAccessRecord(t *testing.T) {
+	msg := &log.AccessMessage{
+		From:        net.TCPDestination(net.ParseAddress("1.2.3.4"), 1234),
+		To:          "tcp:example.com:443",
+		Status:      log.AccessClosed,
+		Email:       "love@xray.com",
+		InboundTag:  "in",
+		OutboundTag: "out",
+		RuleTag:     "rule 1",
+		Protocol:    "tls",
+		Domain:      "example.com",
+		Duration:    1500 * time.Millisecond,
+		Uplink:      100,
+		Downlink:    2000,
+	}
+
+	r := newAccessRecord(msg, AccessLogFormat_JSON, nil)
+	r.time = time.Date(2024, 1, 2, 3, 4, 5, 6000000, time.UTC)
+	expected := `{"time":"2024-01-02T03:04:05.006Z","status":"closed","from":"tcp:1.2.3.4:1234","to":"tcp:example.com:443",` +
+		`"inbound":"in","outbound":"out","rule":"rule 1","protocol":"tls","domain":"example.com","email":"love@xray.com",` +
+		`"duration":1.500,"uplink":100,"downlink":2000}`
+	if s := r.String(); s != expected {
+		t.Error("unexpected JSON record: ", s)
+	}
+
+	r = newAccessRecord(msg, AccessLogFormat_Logfmt, []string{"status", "rule", "email", "duration", "downlink"})
+	expected = `status=closed rule="rule 1" email=love@xray.com duration=1.500 downlink=2000`
+	if s := r.String(); s != expected {
+		t.Error("unexpected logfmt record: ", s)
+	}
+
+	// Fields of closed connections are omitted from accepted records.
+	msg.Status = log.AccessAccepted
+	r = newAccessRecord(msg, AccessLogFormat_Logfmt, []string{"status", "duration", "uplink"})
+	if s := r.String(); s != "status=accepted" {
+		t.Error("unexpected logfmt record: ", s)
+	}
+}
@@ -22,6 +22,9 @@ type Instance struct {
 
 // New creates a new log.Instance based on the given config.
 func New(ctx context.Context, config *Config) (*Instance, error) {
+	if err := ValidateAccessFields(config.AccessLogFields); err != nil {
+		return nil, err
+	}
 	g := &Instance{
 		config: config,
 		active: false,
@@ -42,6 +45,9 @@ func New(ctx context.Context, config *Config) (*Instance, error) {
 func (g *Instance) initAccessLogger() error {
 	handler, err := createHandler(g.config.AccessLogType, HandlerCreatorOptions{
 		Path: g.config.AccessLogPath,
+		// JSON and logfmt records carry their own timestamps.
+		Raw:      g.config.AccessLogFormat != AccessLogFormat_Text,
+		Rotation: g.config.AccessLogRotation,
 	})
 	if err != nil {
 		return err
@@ -52,7 +58,8 @@ func (g *Instance) initAccessLogger() error {
 
 func (g *Instance) initErrorLogger() error {
 	handler, err := createHandler(g.config.ErrorLogType, HandlerCreatorOptions{
-		Path: g.config.ErrorLogPath,
+		Path:     g.config.ErrorLogPath,
+		Rotation: g.config.ErrorLogRotation,
 	})
 	if err != nil {
 		return err
@@ -102,7 +109,12 @@ func (g *Instance) Handle(msg log.Message) {
 
 	switch msg := msg.(type) {
 	case *log.AccessMessage:
-		if g.accessLogger != nil {
+		if g.accessLogger == nil {
+			break
+		}
+		if g.config.AccessLogFormat != AccessLogFormat_Text {
+			g.accessLogger.Handle(newAccessRecord(msg, g.config.AccessLogFormat, g.config.AccessLogFields))
+		} else if msg.Status != log.AccessClosed {
 			g.accessLogger.Handle(msg)
 		}
 	case *log.DNSLog:
@@ -2,6 +2,7 @@ package log
 
 import (
 	"sync"
+	"time"
 
 	"github.com/xtls/xray-core/common"
 	"github.com/xtls/xray-core/common/log"
@@ -9,6 +10,10 @@ import (
 
 type HandlerCreatorOptions struct {
 	Path string
+	// Raw disables the timestamp prefix of each message.
+	Raw bool
+	// Rotation of the log file. The default rotation is used if nil.
+	Rotation *Rotation
 }
 
 type HandlerCreator func(LogType, HandlerCreatorOptions) (log.Handler, error)
@@ -40,13 +45,38 @@ func createHandler(logType LogType, options HandlerCreatorOptions) (log.Handler,
 	return creator(logType, options)
 }
 
+func (r *Rotation) toFileRotation() log.FileRotation {
+	if r == nil {
+		return log.DefaultFileRotation
+	}
+	rotation := log.FileRotation{
+		MaxSize:    int(r.MaxSize),
+		MaxBackups: int(r.MaxBackups),
+		MaxAge:     int(r.MaxAge),
+		Compress:   r.Compress,
+	}
+	switch r.Interval {
+	case RotationInterval_Hourly:
+		rotation.Interval = time.Hour
+	case RotationInterval_Daily:
+		rotation.Interval = 24 * time.Hour
+	}
+	return rotation
+}
+
 func init() {
 	common.Must(RegisterHandlerCreator(LogType_Console, func(lt LogType, options HandlerCreatorOptions) (log.Handler, error) {
+		if options.Raw {
+			return log.NewLogger(log.CreateStdoutRawLogWriter()), nil
+		}
 		return log.NewLogger(log.CreateStdoutLogWriter()), nil
 	}))
 
 	common.Must(RegisterHandlerCreator(LogType_File, func(lt LogType, options HandlerCreatorOptions) (log.Handler, error) {
-		creator, err := log.CreateFileLogWriter(options.Path)
+		creator, err := log.CreateFileLogWriterWithOptions(options.Path, log.FileLogOptions{
+			Raw:      options.Raw,
+			Rotation: options.Rotation.toFileRotation(),
+		})
 		if err != nil {
 			return nil, err
 		}
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	_ "net/http/pprof"
 	"strings"
+	"sync"
 
 	"github.com/xtls/xray-core/app/observatory"
 	"github.com/xtls/xray-core/app/stats"
@@ -19,10 +20,11 @@ import (
 )
 
 type MetricsHandler struct {
-	ohm          outbound.Manager
-	statsManager feature_stats.Manager
-	observatory  extension.Observatory
-	tag          string
+	ohm             outbound.Manager
+	statsManager    feature_stats.Manager
+	observatory     extension.Observatory
+	observatoryOnce sync.Once
+	tag             string
 }
 
 // NewMetricsHandler creates a new MetricsHandler based on the given config.
@@ -46,6 +48,12 @@ func NewMetricsHandler(ctx context.Context, config *Config) (*MetricsHandler, er
 		}
 		manager.VisitCounters(func(name string, counter feature_stats.Counter) bool {
 			nameSplit := strings.Split(name, ">>>")
+			if len(nameSplit) != 4 {
+				return true
+			}
+			if _, found := resp[nameSplit[0]]; !found {
+				return true
+			}
 			typeName, tagOrUser, direction := nameSplit[0], nameSplit[1], nameSplit[3]
 			if item, found := resp[typeName][tagOrUser]; found {
 				item[direction] = counter.Value()
@@ -59,17 +67,12 @@ func NewMetricsHandler(ctx context.Context, config *Config) (*MetricsHandler, er
 		return resp
 	}))
 	expvar.Publish("observatory", expvar.Func(func() interface{} {
-		if c.observatory == nil {
-			common.Must(core.RequireFeatures(ctx, func(observatory extension.Observatory) error {
-				c.observatory = observatory
-				return nil
-			}))
-			if c.observatory == nil {
-				return nil
-			}
+		o := c.getObservatory(ctx)
+		if o == nil {
+			return nil
 		}
 		resp := map[string]*observatory.OutboundStatus{}
-		if o, err := c.observatory.GetObservation(context.Background()); err != nil {
+		if o, err := o.GetObservation(context.Background()); err != nil {
 			return err
 		} else {
 			for _, x := range o.(*observatory.ObservationResult).GetStatus() {
@@ -78,6 +81,7 @@ func NewMetricsHandler(ctx context.Context, config *Config) (*MetricsHandler, er
 		}
 		return resp
 	}))
+	http.HandleFunc("/metrics", c.ServePrometheus(ctx))
 	return c, nil
 }
 
@@ -0,0 +1,209 @@
+package metrics
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/xtls/xray-core/app/observatory"
+	"github.com/xtls/xray-core/app/stats"
+	"github.com/xtls/xray-core/core"
+	"github.com/xtls/xray-core/features/extension"
+	feature_stats "github.com/xtls/xray-core/features/stats"
+)
+
+// metricFamily is a group of samples sharing the same metric name, in Prometheus text exposition format.
+type metricFamily struct {
+	name    string
+	help    string
+	typ     string
+	samples []metricSample
+}
+
+type metricSample struct {
+	labels [][2]string
+	value  float64
+}
+
+func (f *metricFamily) add(value float64, labels ...string) {
+	s := metricSample{value: value}
+	for i := 0; i+1 < len(labels); i += 2 {
+		s.labels = append(s.labels, [2]string{labels[i], labels[i+1]})
+	}
+	f.samples = append(f.samples, s)
+}
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func (f *metricFamily) writeTo(w io.Writer) {
+	if len(f.samples) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "# HELP %s %s\n", f.name, f.help)
+	fmt.Fprintf(w, "# TYPE %s %s\n", f.name, f.typ)
+	for _, s := range f.samples {
+		w.Write([]byte(f.name))
+		if len(s.labels) > 0 {
+			pairs := make([]string, 0, len(s.labels))
+			for _, l := range s.labels {
+				pairs = append(pairs, l[0]+`="`+labelValueReplacer.Replace(l[1])+`"`)
+			}
+			fmt.Fprintf(w, "{%s}", strings.Join(pairs, ","))
+		}
+		fmt.Fprintf(w, " %v\n", s.value)
+	}
+}
+
+// writeCounters converts stats counters into metric families. Counters are named as
+// "type>>>tag>>>traffic>>>direction" for traffic and "type>>>tag>>>connection>>>active" for active connections,
+// other counters are exported as is with their names as label.
+func writeCounters(w io.Writer, manager *stats.Manager) {
+	traffic := map[string]*metricFamily{}
+	connections := map[string]*metricFamily{}
+	others := &metricFamily{name: "xray_counter", help: "Other counters in stats manager.", typ: "untyped"}
+
+	manager.VisitCounters(func(name string, counter feature_stats.Counter) bool {
+		nameSplit := strings.Split(name, ">>>")
+		if len(nameSplit) != 4 {
+			others.add(float64(counter.Value()), "name", name)
+			return true
+		}
+		typeName, tagOrUser, kind, direction := nameSplit[0], nameSplit[1], nameSplit[2], nameSplit[3]
+		label := "tag"
+		if typeName == "user" {
+			label = "user"
+		}
+		switch {
+		case kind == "traffic":
+			f, found := traffic[typeName]
+			if !found {
+				f = &metricFamily{name: "xray_" + typeName + "_traffic_bytes_total", help: "Traffic in bytes of " + typeName + ".", typ: "counter"}
+				traffic[typeName] = f
+			}
+			f.add(float64(counter.Value()), label, tagOrUser, "direction", direction)
+		case kind == "connection" && direction == "active":
+			f, found := connections[typeName]
+			if !found {
+				f = &metricFamily{name: "xray_" + typeName + "_active_connections", help: "Active connections of " + typeName + ".", typ: "gauge"}
+				connections[typeName] = f
+			}
+			f.add(float64(counter.Value()), label, tagOrUser)
+		default:
+			others.add(float64(counter.Value()), "name", name)
+		}
+		return true
+	})
+
+	for _, families := range []map[string]*metricFamily{traffic, connections} {
+		for _, f := range families {
+			sortSamples(f)
+			f.writeTo(w)
+		}
+	}
+	sortSamples(others)
+	others.writeTo(w)
+}
+
+func sortSamples(f *metricFamily) {
+	sort.Slice(f.samples, func(i, j int) bool {
+		a, b := f.samples[i].labels, f.samples[j].labels
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k][1] != b[k][1] {
+				return a[k][1] < b[k][1]
+			}
+		}
+		return len(a) < len(b)
+	})
+}
+
+func writeObservation(w io.Writer, result *observatory.ObservationResult) {
+	alive := &metricFamily{name: "xray_observatory_outbound_alive", help: "Whether the outbound is alive according to observatory.", typ: "gauge"}
+	delay := &metricFamily{name: "xray_observatory_outbound_delay_milliseconds", help: "Probe delay of the outbound in milliseconds.", typ: "gauge"}
+	lastSeen := &metricFamily{name: "xray_observatory_outbound_last_seen_timestamp_seconds", help: "Last time the outbound is known to be alive.", typ: "gauge"}
+	for _, s := range result.GetStatus() {
+		v := 0.0
+		if s.Alive {
+			v = 1
+		}
+		alive.add(v, "outbound", s.OutboundTag)
+		delay.add(float64(s.Delay), "outbound", s.OutboundTag)
+		lastSeen.add(float64(s.LastSeenTime), "outbound", s.OutboundTag)
+	}
+	for _, f := range []*metricFamily{alive, delay, lastSeen} {
+		sortSamples(f)
+		f.writeTo(w)
+	}
+}
+
+func writeRuntime(w io.Writer, startTime time.Time) {
+	var rtm runtime.MemStats
+	runtime.ReadMemStats(&rtm)
+
+	families := []*metricFamily{
+		{name: "xray_uptime_seconds", help: "Seconds since Xray started.", typ: "gauge"},
+		{name: "go_goroutines", help: "Number of goroutines that currently exist.", typ: "gauge"},
+		{name: "go_memstats_alloc_bytes", help: "Number of bytes allocated and still in use.", typ: "gauge"},
+		{name: "go_memstats_alloc_bytes_total", help: "Total number of bytes allocated, even if freed.", typ: "counter"},
+		{name: "go_memstats_sys_bytes", help: "Number of bytes obtained from system.", typ: "gauge"},
+		{name: "go_memstats_mallocs_total", help: "Total number of mallocs.", typ: "counter"},
+		{name: "go_memstats_frees_total", help: "Total number of frees.", typ: "counter"},
+		{name: "go_memstats_heap_objects", help: "Number of allocated objects.", typ: "gauge"},
+		{name: "go_gc_cycles_total", help: "Number of completed GC cycles.", typ: "counter"},
+		{name: "go_gc_pause_seconds_total", help: "Total GC pause time in seconds.", typ: "counter"},
+	}
+	values := []float64{
+		time.Since(startTime).Seconds(),
+		float64(runtime.NumGoroutine()),
+		float64(rtm.Alloc),
+		float64(rtm.TotalAlloc),
+		float64(rtm.Sys),
+		float64(rtm.Mallocs),
+		float64(rtm.Frees),
+		float64(rtm.Mallocs - rtm.Frees),
+		float64(rtm.NumGC),
+		float64(rtm.PauseTotalNs) / 1e9,
+	}
+	for i, f := range families {
+		f.add(values[i])
+		f.writeTo(w)
+	}
+}
+
+// getObservatory returns the observatory, or nil if there is none. It is looked up once, after all features of the
+// instance are added.
+func (p *MetricsHandler) getObservatory(ctx context.Context) extension.Observatory {
+	p.observatoryOnce.Do(func() {
+		if v := core.FromContext(ctx); v != nil {
+			p.observatory, _ = v.GetFeature(extension.ObservatoryType()).(extension.Observatory)
+		}
+	})
+	return p.observatory
+}
+
+// ServePrometheus writes metrics in Prometheus text exposition format.
+func (p *MetricsHandler) ServePrometheus(ctx context.Context) http.HandlerFunc {
+	startTime := time.Now()
+	return func(rw http.ResponseWriter, r *http.Request) {
+		rw.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		w := bufio.NewWriter(rw)
+		defer w.Flush()
+
+		if manager, ok := p.statsManager.(*stats.Manager); ok {
+			writeCounters(w, manager)
+		}
+		if o := p.getObservatory(ctx); o != nil {
+			if result, err := o.GetObservation(r.Context()); err == nil {
+				if result, ok := result.(*observatory.ObservationResult); ok {
+					writeObservation(w, result)
+				}
+			}
+		}
+		writeRuntime(w, startTime)
+	}
+}
@@ -0,0 +1,68 @@
+package metrics
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/xtls/xray-core/app/observatory"
+	"github.com/xtls/xray-core/app/stats"
+	"github.com/xtls/xray-core/common"
+)
+
+func TestWriteCounters(t *testing.T) {
+	m, err := stats.NewManager(context.Background(), &stats.Config{})
+	common.Must(err)
+
+	c, _ := m.RegisterCounter("inbound>>>in>>>traffic>>>uplink")
+	c.Set(10)
+	c, _ = m.RegisterCounter("user>>>a@b\"c>>>traffic>>>downlink")
+	c.Set(20)
+	c, _ = m.RegisterCounter("outbound>>>out>>>connection>>>active")
+	c.Set(3)
+	c, _ = m.RegisterCounter("misc")
+	c.Set(4)
+
+	sb := new(strings.Builder)
+	writeCounters(sb, m)
+	lines := strings.Split(sb.String(), "\n")
+
+	for _, expected := range []string{
+		`xray_inbound_traffic_bytes_total{tag="in",direction="uplink"} 10`,
+		`xray_user_traffic_bytes_total{user="a@b\"c",direction="downlink"} 20`,
+		`# TYPE xray_outbound_active_connections gauge`,
+		`xray_outbound_active_connections{tag="out"} 3`,
+		`xray_counter{name="misc"} 4`,
+	} {
+		found := false
+		for _, l := range lines {
+			if l == expected {
+				found = true
+			}
+		}
+		if !found {
+			t.Error("line not found: ", expected, "\n", sb.String())
+		}
+	}
+}
+
+func TestWriteObservation(t *testing.T) {
+	sb := new(strings.Builder)
+	writeObservation(sb, &observatory.ObservationResult{
+		Status: []*observatory.OutboundStatus{
+			{OutboundTag: "b", Alive: false, Delay: 99999999},
+			{OutboundTag: "a", Alive: true, Delay: 120, LastSeenTime: 1700000000},
+		},
+	})
+	lines := strings.Split(strings.TrimSpace(sb.String()), "\n")
+	expected := []string{
+		`# HELP xray_observatory_outbound_alive Whether the outbound is alive according to observatory.`,
+		`# TYPE xray_observatory_outbound_alive gauge`,
+		`xray_observatory_outbound_alive{outbound="a"} 1`,
+		`xray_observatory_outbound_alive{outbound="b"} 0`,
+	}
+	if r := cmp.Diff(lines[:4], expected); r != "" {
+		t.Error(r)
+	}
+}
@@ -58,6 +58,18 @@ func (p *Policy) overrideWith(another *Policy) {
 			Connection: another.Buffer.Connection,
 		}
 	}
+	if another.RateLimit != nil {
+		p.RateLimit = &Policy_RateLimit{
+			Uplink:   another.RateLimit.Uplink,
+			Downlink: another.RateLimit.Downlink,
+		}
+	}
+	if another.UserLimit != nil {
+		p.UserLimit = &Policy_UserLimit{
+			MaxConnections: another.UserLimit.MaxConnections,
+			MaxSourceIps:   another.UserLimit.MaxSourceIps,
+		}
+	}
 }
 
 // ToCorePolicy converts this Policy to policy.Session.
@@ -77,9 +89,24 @@ func (p *Policy) ToCorePolicy() policy.Session {
 	if p.Buffer != nil {
 		cp.Buffer.PerConnection = p.Buffer.Connection
 	}
+	if p.RateLimit != nil {
+		cp.RateLimit = p.RateLimit.ToCorePolicy()
+	}
+	if p.UserLimit != nil {
+		cp.UserLimit.MaxConnections = p.UserLimit.MaxConnections
+		cp.UserLimit.MaxSourceIPs = p.UserLimit.MaxSourceIps
+	}
 	return cp
 }
 
+// ToCorePolicy converts this RateLimit to policy.RateLimit.
+func (r *Policy_RateLimit) ToCorePolicy() policy.RateLimit {
+	return policy.RateLimit{
+		Uplink:   r.GetUplink(),
+		Downlink: r.GetDownlink(),
+	}
+}
+
 // ToCorePolicy converts this SystemPolicy to policy.System.
 func (p *SystemPolicy) ToCorePolicy() policy.System {
 	return policy.System{
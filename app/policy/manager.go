@@ -2,22 +2,55 @@ package policy
 
 import (
 	"context"
+	"sort"
+	"sync"
 
 	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/net"
 	"github.com/xtls/xray-core/features/policy"
+	"golang.org/x/time/rate"
 )
 
+// limiterKey identifies the rate limiters of a user at a level, as the level may decide the rate limit.
+type limiterKey struct {
+	email string
+	level uint32
+}
+
+// userLimiters are the rate limiters shared by all connections of a user.
+type userLimiters struct {
+	uplink   *rate.Limiter
+	downlink *rate.Limiter
+	// refs is the number of connections using the limiters. They are removed once it drops to 0.
+	refs int
+}
+
+// userConnections are the online connections of a user, counted by source IP.
+type userConnections struct {
+	total uint32
+	ips   map[string]uint32
+}
+
 // Instance is an instance of Policy manager.
 type Instance struct {
-	levels map[uint32]*Policy
-	system *SystemPolicy
+	access         sync.RWMutex
+	levels         map[uint32]*Policy
+	system         *SystemPolicy
+	userRateLimits map[string]*Policy_RateLimit
+	limiters       map[limiterKey]*userLimiters
+
+	onlineAccess sync.Mutex
+	online       map[string]*userConnections
 }
 
 // New creates new Policy manager instance.
 func New(ctx context.Context, config *Config) (*Instance, error) {
 	m := &Instance{
-		levels: make(map[uint32]*Policy),
-		system: config.System,
+		levels:         make(map[uint32]*Policy),
+		system:         config.System,
+		userRateLimits: make(map[string]*Policy_RateLimit),
+		limiters:       make(map[limiterKey]*userLimiters),
+		online:         make(map[string]*userConnections),
 	}
 	if len(config.Level) > 0 {
 		for lv, p := range config.Level {
@@ -26,6 +59,9 @@ func New(ctx context.Context, config *Config) (*Instance, error) {
 			m.levels[lv] = pp
 		}
 	}
+	for email, r := range config.UserRateLimit {
+		m.userRateLimits[email] = r
+	}
 
 	return m, nil
 }
@@ -37,6 +73,9 @@ func (*Instance) Type() interface{} {
 
 // ForLevel implements policy.Manager.
 func (m *Instance) ForLevel(level uint32) policy.Session {
+	m.access.RLock()
+	defer m.access.RUnlock()
+
 	if p, ok := m.levels[level]; ok {
 		return p.ToCorePolicy()
 	}
@@ -51,6 +90,164 @@ func (m *Instance) ForSystem() policy.System {
 	return m.system.ToCorePolicy()
 }
 
+// rateLimitFor returns the rate limit of the given user. It must be called with access locked.
+func (m *Instance) rateLimitFor(email string, level uint32) policy.RateLimit {
+	if r, found := m.userRateLimits[email]; found {
+		return r.ToCorePolicy()
+	}
+	if p, found := m.levels[level]; found && p.RateLimit != nil {
+		return p.RateLimit.ToCorePolicy()
+	}
+	return policy.RateLimit{}
+}
+
+// RateLimitersForUser implements policy.RateLimiterManager.
+func (m *Instance) RateLimitersForUser(email string, level uint32) (*rate.Limiter, *rate.Limiter, func()) {
+	m.access.Lock()
+	defer m.access.Unlock()
+
+	key := limiterKey{email: email, level: level}
+	l, found := m.limiters[key]
+	if !found {
+		// Limiters are always created, so that a limit set at runtime applies to existing connections as well.
+		limit := m.rateLimitFor(email, level)
+		l = &userLimiters{
+			uplink:   rate.NewLimiter(rate.Inf, 0),
+			downlink: rate.NewLimiter(rate.Inf, 0),
+		}
+		policy.UpdateRateLimiter(l.uplink, limit.Uplink)
+		policy.UpdateRateLimiter(l.downlink, limit.Downlink)
+		m.limiters[key] = l
+	}
+	l.refs++
+
+	var once sync.Once
+	return l.uplink, l.downlink, func() {
+		once.Do(func() {
+			m.access.Lock()
+			defer m.access.Unlock()
+
+			if l.refs--; l.refs == 0 {
+				delete(m.limiters, key)
+			}
+		})
+	}
+}
+
+// SetUserRateLimit changes the rate limit of the given user at runtime. Speed 0 means unlimited.
+func (m *Instance) SetUserRateLimit(email string, uplink, downlink uint64) {
+	m.access.Lock()
+	defer m.access.Unlock()
+
+	m.userRateLimits[email] = &Policy_RateLimit{
+		Uplink:   uplink,
+		Downlink: downlink,
+	}
+	for key, l := range m.limiters {
+		if key.email != email {
+			continue
+		}
+		policy.UpdateRateLimiter(l.uplink, uplink)
+		policy.UpdateRateLimiter(l.downlink, downlink)
+	}
+}
+
+// SetLevelRateLimit changes the rate limit of the given level at runtime. Speed 0 means unlimited.
+// Users with their own rate limit are not affected.
+func (m *Instance) SetLevelRateLimit(level uint32, uplink, downlink uint64) {
+	m.access.Lock()
+	defer m.access.Unlock()
+
+	p := defaultPolicy()
+	if old, found := m.levels[level]; found {
+		p.overrideWith(old)
+	}
+	p.RateLimit = &Policy_RateLimit{
+		Uplink:   uplink,
+		Downlink: downlink,
+	}
+	m.levels[level] = p
+
+	for key, l := range m.limiters {
+		if _, found := m.userRateLimits[key.email]; found || key.level != level {
+			continue
+		}
+		policy.UpdateRateLimiter(l.uplink, uplink)
+		policy.UpdateRateLimiter(l.downlink, downlink)
+	}
+}
+
+// TrackConnection implements policy.ConnectionTracker.
+func (m *Instance) TrackConnection(email string, level uint32, source net.Address) (func(), error) {
+	limit := m.ForLevel(level).UserLimit
+	ip := ""
+	if source != nil {
+		ip = source.String()
+	}
+
+	m.onlineAccess.Lock()
+	defer m.onlineAccess.Unlock()
+
+	c, found := m.online[email]
+	if !found {
+		c = &userConnections{ips: make(map[string]uint32)}
+	}
+	if limit.MaxConnections > 0 && c.total >= limit.MaxConnections {
+		return nil, newError("user ", email, " has reached the limit of ", limit.MaxConnections, " connections")
+	}
+	if limit.MaxSourceIPs > 0 && c.ips[ip] == 0 && uint32(len(c.ips)) >= limit.MaxSourceIPs {
+		return nil, newError("user ", email, " has reached the limit of ", limit.MaxSourceIPs, " source IPs")
+	}
+	c.total++
+	c.ips[ip]++
+	m.online[email] = c
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			m.onlineAccess.Lock()
+			defer m.onlineAccess.Unlock()
+
+			c.total--
+			if c.ips[ip]--; c.ips[ip] == 0 {
+				delete(c.ips, ip)
+			}
+			if c.total == 0 {
+				delete(m.online, email)
+			}
+		})
+	}, nil
+}
+
+// OnlineIPs implements policy.ConnectionTracker.
+func (m *Instance) OnlineIPs(email string) map[string]uint32 {
+	m.onlineAccess.Lock()
+	defer m.onlineAccess.Unlock()
+
+	c, found := m.online[email]
+	if !found {
+		return nil
+	}
+	ips := make(map[string]uint32, len(c.ips))
+	for ip, count := range c.ips {
+		ips[ip] = count
+	}
+	return ips
+}
+
+// OnlineUsers implements policy.ConnectionTracker.
+func (m *Instance) OnlineUsers() []string {
+	m.onlineAccess.Lock()
+	defer m.onlineAccess.Unlock()
+
+	users := make([]string, 0, len(m.online))
+	for email := range m.online {
+		users = append(users, email)
+	}
+	sort.Strings(users)
+	return users
+}
+
 // Start implements common.Runnable.Start().
 func (m *Instance) Start() error {
 	return nil
@@ -7,7 +7,9 @@ import (
 
 	. "github.com/xtls/xray-core/app/policy"
 	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/net"
 	"github.com/xtls/xray-core/features/policy"
+	"golang.org/x/time/rate"
 )
 
 func TestPolicy(t *testing.T) {
@@ -43,3 +45,123 @@ func TestPolicy(t *testing.T) {
 		}
 	}
 }
+
+func TestRateLimit(t *testing.T) {
+	manager, err := New(context.Background(), &Config{
+		Level: map[uint32]*Policy{
+			1: {
+				RateLimit: &Policy_RateLimit{
+					Uplink:   1024 * 1024,
+					Downlink: 0,
+				},
+			},
+		},
+		UserRateLimit: map[string]*Policy_RateLimit{
+			"vip@example.com": {Uplink: 0, Downlink: 0},
+		},
+	})
+	common.Must(err)
+
+	if r := manager.ForLevel(1).RateLimit; r.Uplink != 1024*1024 || r.Downlink != 0 {
+		t.Error("unexpected rate limit of level 1: ", r)
+	}
+
+	up, down, release := manager.RateLimitersForUser("a@example.com", 1)
+	if up.Limit() != rate.Limit(1024*1024) {
+		t.Error("expect uplink limit 1MB/s, but got ", up.Limit())
+	}
+	if down.Limit() != rate.Inf {
+		t.Error("expect unlimited downlink, but got ", down.Limit())
+	}
+	up2, _, release2 := manager.RateLimitersForUser("a@example.com", 1)
+	if up2 != up {
+		t.Error("expect limiters to be shared among connections of a user")
+	}
+
+	vipUp, _, _ := manager.RateLimitersForUser("vip@example.com", 1)
+	if vipUp.Limit() != rate.Inf {
+		t.Error("expect user rate limit to override level's, but got ", vipUp.Limit())
+	}
+
+	manager.SetLevelRateLimit(1, 2048, 4096)
+	if up.Limit() != 2048 || down.Limit() != 4096 {
+		t.Error("expect existing limiters to be updated, but got ", up.Limit(), " ", down.Limit())
+	}
+	if vipUp.Limit() != rate.Inf {
+		t.Error("expect user with own rate limit to be unaffected, but got ", vipUp.Limit())
+	}
+
+	manager.SetUserRateLimit("a@example.com", 0, 100)
+	if up.Limit() != rate.Inf || down.Limit() != 100 {
+		t.Error("expect user limiters to be updated, but got ", up.Limit(), " ", down.Limit())
+	}
+	manager.SetUserRateLimit("a@example.com", 0, 0)
+
+	if up2, _, _ := manager.RateLimitersForUser("a@example.com", 0); up2 == up || up2.Limit() != rate.Inf {
+		t.Error("expect limiters of another level to be separate, but got ", up2.Limit())
+	}
+
+	release()
+	release()
+	up2, _, release3 := manager.RateLimitersForUser("a@example.com", 1)
+	if up2 != up {
+		t.Error("expect limiters to be kept while a connection uses them")
+	}
+	release2()
+	release3()
+	if up2, _, _ := manager.RateLimitersForUser("a@example.com", 1); up2 == up {
+		t.Error("expect limiters to be removed once no connection uses them")
+	}
+}
+
+func TestUserLimit(t *testing.T) {
+	manager, err := New(context.Background(), &Config{
+		Level: map[uint32]*Policy{
+			0: {
+				UserLimit: &Policy_UserLimit{
+					MaxConnections: 3,
+					MaxSourceIps:   2,
+				},
+			},
+		},
+	})
+	common.Must(err)
+
+	const email = "test@example.com"
+	ip1 := net.ParseAddress("10.0.0.1")
+	ip2 := net.ParseAddress("10.0.0.2")
+	ip3 := net.ParseAddress("10.0.0.3")
+
+	release1, err := manager.TrackConnection(email, 0, ip1)
+	common.Must(err)
+	release2, err := manager.TrackConnection(email, 0, ip2)
+	common.Must(err)
+	if _, err := manager.TrackConnection(email, 0, ip3); err == nil {
+		t.Error("expect error on the third source IP")
+	}
+	release3, err := manager.TrackConnection(email, 0, ip1)
+	common.Must(err)
+	if _, err := manager.TrackConnection(email, 0, ip1); err == nil {
+		t.Error("expect error on the fourth connection")
+	}
+
+	if ips := manager.OnlineIPs(email); len(ips) != 2 || ips["10.0.0.1"] != 2 || ips["10.0.0.2"] != 1 {
+		t.Error("unexpected online IPs: ", ips)
+	}
+
+	release2()
+	release2()
+	if _, err := manager.TrackConnection(email, 0, ip3); err != nil {
+		t.Error("expect the third source IP to be accepted after another one is offline: ", err)
+	}
+
+	release1()
+	release3()
+	if users := manager.OnlineUsers(); len(users) != 1 || users[0] != email {
+		t.Error("unexpected online users: ", users)
+	}
+
+	if _, err := manager.TrackConnection("other@example.com", 1, ip1); err != nil {
+		t.Error("expect no limit on level 1: ", err)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/xtls/xray-core/core"
 	"github.com/xtls/xray-core/features/inbound"
 	"github.com/xtls/xray-core/features/outbound"
+	"github.com/xtls/xray-core/features/policy"
 	"github.com/xtls/xray-core/proxy"
 	grpc "google.golang.org/grpc"
 )
@@ -65,6 +66,7 @@ type handlerServer struct {
 	s   *core.Instance
 	ihm inbound.Manager
 	ohm outbound.Manager
+	pm  policy.Manager
 }
 
 func (s *handlerServer) AddInbound(ctx context.Context, request *AddInboundRequest) (*AddInboundResponse, error) {
@@ -122,6 +124,47 @@ func (s *handlerServer) AlterOutbound(ctx context.Context, request *AlterOutboun
 	return &AlterOutboundResponse{}, operation.ApplyOutbound(ctx, handler)
 }
 
+func (s *handlerServer) SetRateLimit(ctx context.Context, request *SetRateLimitRequest) (*SetRateLimitResponse, error) {
+	rm, ok := s.pm.(policy.RateLimiterManager)
+	if !ok {
+		return nil, newError("policy manager does not support rate limit")
+	}
+	if len(request.Email) > 0 {
+		rm.SetUserRateLimit(request.Email, request.Uplink, request.Downlink)
+	} else {
+		rm.SetLevelRateLimit(request.Level, request.Uplink, request.Downlink)
+	}
+	return &SetRateLimitResponse{}, nil
+}
+
+func (s *handlerServer) GetOnlineIPs(ctx context.Context, request *GetOnlineIPsRequest) (*GetOnlineIPsResponse, error) {
+	tracker, ok := s.pm.(policy.ConnectionTracker)
+	if !ok {
+		return nil, newError("policy manager does not track online users")
+	}
+	emails := []string{request.Email}
+	if len(request.Email) == 0 {
+		emails = tracker.OnlineUsers()
+	}
+	response := &GetOnlineIPsResponse{}
+	for _, email := range emails {
+		if ips := tracker.OnlineIPs(email); len(ips) > 0 {
+			response.Users = append(response.Users, &UserOnlineIPs{
+				Email: email,
+				Ips:   ips,
+			})
+		}
+	}
+	return response, nil
+}
+
+func (s *handlerServer) ReloadConfig(ctx context.Context, request *ReloadConfigRequest) (*ReloadConfigResponse, error) {
+	if err := s.s.ReloadConfig(); err != nil {
+		return nil, newError("failed to reload config").Base(err)
+	}
+	return &ReloadConfigResponse{}, nil
+}
+
 func (s *handlerServer) mustEmbedUnimplementedHandlerServiceServer() {}
 
 type service struct {
@@ -132,9 +175,10 @@ func (s *service) Register(server *grpc.Server) {
 	hs := &handlerServer{
 		s: s.v,
 	}
-	common.Must(s.v.RequireFeatures(func(im inbound.Manager, om outbound.Manager) {
+	common.Must(s.v.RequireFeatures(func(im inbound.Manager, om outbound.Manager, pm policy.Manager) {
 		hs.ihm = im
 		hs.ohm = om
+		hs.pm = pm
 	}))
 	RegisterHandlerServiceServer(server, hs)
 
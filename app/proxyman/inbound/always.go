@@ -87,6 +87,20 @@ func NewAlwaysOnInboundHandler(ctx context.Context, tag string, receiverConfig *
 		}
 		mss.SocketSettings.ReceiveOriginalDestAddress = true
 	}
+	if sp, ok := p.(proxy.StandaloneInbound); ok {
+		newError("creating standalone worker for ", tag).AtDebug().WriteToLog()
+
+		h.workers = append(h.workers, &standaloneWorker{
+			proxy:           sp,
+			tag:             tag,
+			dispatcher:      h.mux,
+			sniffingConfig:  receiverConfig.GetEffectiveSniffingSettings(),
+			uplinkCounter:   uplinkCounter,
+			downlinkCounter: downlinkCounter,
+			ctx:             ctx,
+		})
+		return h, nil
+	}
 	if pl == nil {
 		if net.HasNetwork(nl, net.Network_UNIX) {
 			newError("creating unix domain socket worker on ", address).AtDebug().WriteToLog()
@@ -14,12 +14,15 @@ import (
 	"github.com/xtls/xray-core/common/session"
 	"github.com/xtls/xray-core/common/signal/done"
 	"github.com/xtls/xray-core/common/task"
+	"github.com/xtls/xray-core/core"
+	"github.com/xtls/xray-core/features/policy"
 	"github.com/xtls/xray-core/features/routing"
 	"github.com/xtls/xray-core/features/stats"
 	"github.com/xtls/xray-core/proxy"
 	"github.com/xtls/xray-core/transport/internet"
 	"github.com/xtls/xray-core/transport/internet/stat"
 	"github.com/xtls/xray-core/transport/internet/tcp"
+	"github.com/xtls/xray-core/transport/internet/tls"
 	"github.com/xtls/xray-core/transport/internet/udp"
 	"github.com/xtls/xray-core/transport/pipe"
 )
@@ -80,6 +83,14 @@ func (w *tcpWorker) callback(conn stat.Connection) {
 	}
 	ctx = session.ContextWithOutbound(ctx, outbound)
 
+	subject, err := clientCertSubject(ctx, w.stream, conn)
+	if err != nil {
+		newError("failed to verify client certificate").Base(err).WriteToLog(session.ExportIDToError(ctx))
+		cancel()
+		conn.Close()
+		return
+	}
+
 	if w.uplinkCounter != nil || w.downlinkCounter != nil {
 		conn = &stat.CounterConnection{
 			Connection:   conn,
@@ -88,10 +99,11 @@ func (w *tcpWorker) callback(conn stat.Connection) {
 		}
 	}
 	ctx = session.ContextWithInbound(ctx, &session.Inbound{
-		Source:  net.DestinationFromAddr(conn.RemoteAddr()),
-		Gateway: net.TCPDestination(w.address, w.port),
-		Tag:     w.tag,
-		Conn:    conn,
+		Source:            net.DestinationFromAddr(conn.RemoteAddr()),
+		Gateway:           net.TCPDestination(w.address, w.port),
+		Tag:               w.tag,
+		Conn:              conn,
+		ClientCertSubject: subject,
 	})
 
 	content := new(session.Content)
@@ -111,6 +123,33 @@ func (w *tcpWorker) callback(conn stat.Connection) {
 	conn.Close()
 }
 
+// clientCertSubject completes the TLS handshake of the connection if the inbound authenticates clients by their
+// certificates, and returns the subject of the verified certificate. Transports over HTTP, such as WebSocket and
+// gRPC, do not expose their TLS connections, so they have no subject.
+func clientCertSubject(ctx context.Context, stream *internet.MemoryStreamConfig, conn stat.Connection) (string, error) {
+	config := tls.ConfigFromStreamSettings(stream)
+	if config == nil || config.ClientAuth == tls.ClientAuth_NONE {
+		return "", nil
+	}
+	tlsConn, ok := conn.(*tls.Conn)
+	if !ok {
+		return "", nil
+	}
+
+	timeout := policy.SessionDefault().Timeouts.Handshake
+	if v := core.FromContext(ctx); v != nil {
+		if pm, ok := v.GetFeature(policy.ManagerType()).(policy.Manager); ok {
+			timeout = pm.ForLevel(0).Timeouts.Handshake
+		}
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		return "", err
+	}
+	return tlsConn.VerifiedPeerSubject(), nil
+}
+
 func (w *tcpWorker) Proxy() proxy.Inbound {
 	return w.proxy
 }
@@ -454,6 +493,14 @@ func (w *dsWorker) callback(conn stat.Connection) {
 	sid := session.NewID()
 	ctx = session.ContextWithID(ctx, sid)
 
+	subject, err := clientCertSubject(ctx, w.stream, conn)
+	if err != nil {
+		newError("failed to verify client certificate").Base(err).WriteToLog(session.ExportIDToError(ctx))
+		cancel()
+		conn.Close()
+		return
+	}
+
 	if w.uplinkCounter != nil || w.downlinkCounter != nil {
 		conn = &stat.CounterConnection{
 			Connection:   conn,
@@ -462,10 +509,11 @@ func (w *dsWorker) callback(conn stat.Connection) {
 		}
 	}
 	ctx = session.ContextWithInbound(ctx, &session.Inbound{
-		Source:  net.DestinationFromAddr(conn.RemoteAddr()),
-		Gateway: net.UnixDestination(w.address),
-		Tag:     w.tag,
-		Conn:    conn,
+		Source:            net.DestinationFromAddr(conn.RemoteAddr()),
+		Gateway:           net.UnixDestination(w.address),
+		Tag:               w.tag,
+		Conn:              conn,
+		ClientCertSubject: subject,
 	})
 
 	content := new(session.Content)
@@ -523,3 +571,72 @@ func (w *dsWorker) Close() error {
 
 	return nil
 }
+
+type standaloneWorker struct {
+	proxy           proxy.StandaloneInbound
+	tag             string
+	dispatcher      routing.Dispatcher
+	sniffingConfig  *proxyman.SniffingConfig
+	uplinkCounter   stats.Counter
+	downlinkCounter stats.Counter
+
+	ctx context.Context
+}
+
+func (w *standaloneWorker) callback(conn stat.Connection, dest net.Destination) {
+	ctx, cancel := context.WithCancel(w.ctx)
+	sid := session.NewID()
+	ctx = session.ContextWithID(ctx, sid)
+
+	ctx = session.ContextWithOutbound(ctx, &session.Outbound{
+		Target: dest,
+	})
+
+	if w.uplinkCounter != nil || w.downlinkCounter != nil {
+		conn = &stat.CounterConnection{
+			Connection:   conn,
+			ReadCounter:  w.uplinkCounter,
+			WriteCounter: w.downlinkCounter,
+		}
+	}
+	ctx = session.ContextWithInbound(ctx, &session.Inbound{
+		Source: net.DestinationFromAddr(conn.RemoteAddr()),
+		Tag:    w.tag,
+		Conn:   conn,
+	})
+
+	content := new(session.Content)
+	if w.sniffingConfig != nil {
+		content.SniffingRequest.Enabled = w.sniffingConfig.Enabled
+		content.SniffingRequest.OverrideDestinationForProtocol = w.sniffingConfig.DestinationOverride
+		content.SniffingRequest.ExcludeForDomain = w.sniffingConfig.DomainsExcluded
+		content.SniffingRequest.MetadataOnly = w.sniffingConfig.MetadataOnly
+		content.SniffingRequest.RouteOnly = w.sniffingConfig.RouteOnly
+	}
+	ctx = session.ContextWithContent(ctx, content)
+
+	if err := w.proxy.Process(ctx, dest.Network, conn, w.dispatcher); err != nil {
+		newError("connection ends").Base(err).WriteToLog(session.ExportIDToError(ctx))
+	}
+	cancel()
+	conn.Close()
+}
+
+func (w *standaloneWorker) Proxy() proxy.Inbound {
+	return w.proxy
+}
+
+func (w *standaloneWorker) Port() net.Port {
+	return net.Port(0)
+}
+
+func (w *standaloneWorker) Start() error {
+	if err := w.proxy.Listen(w.callback); err != nil {
+		return newError("failed to start inbound ", w.tag).AtWarning().Base(err)
+	}
+	return nil
+}
+
+func (w *standaloneWorker) Close() error {
+	return w.proxy.Close()
+}
@@ -0,0 +1,169 @@
+package inbound
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/xtls/xray-core/app/proxyman"
+	app_stats "github.com/xtls/xray-core/app/stats"
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/buf"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/session"
+	"github.com/xtls/xray-core/common/task"
+	"github.com/xtls/xray-core/features/routing"
+	"github.com/xtls/xray-core/transport"
+	"github.com/xtls/xray-core/transport/internet/stat"
+	"github.com/xtls/xray-core/transport/pipe"
+)
+
+// standaloneInbound passes connections to the handler given to Listen, like a TUN device does with the connections
+// of its network stack, and relays them through the dispatcher.
+type standaloneInbound struct {
+	handler func(conn stat.Connection, dest net.Destination)
+	closed  bool
+}
+
+func (*standaloneInbound) Network() []net.Network {
+	return []net.Network{net.Network_TCP}
+}
+
+func (s *standaloneInbound) Listen(handler func(conn stat.Connection, dest net.Destination)) error {
+	s.handler = handler
+	return nil
+}
+
+func (s *standaloneInbound) Close() error {
+	s.closed = true
+	return nil
+}
+
+func (*standaloneInbound) Process(ctx context.Context, network net.Network, conn stat.Connection, dispatcher routing.Dispatcher) error {
+	link, err := dispatcher.Dispatch(ctx, session.OutboundFromContext(ctx).Target)
+	if err != nil {
+		return err
+	}
+	requestDone := func() error {
+		defer common.Close(link.Writer)
+		return buf.Copy(buf.NewReader(conn), link.Writer)
+	}
+	responseDone := func() error {
+		return buf.Copy(link.Reader, buf.NewWriter(conn))
+	}
+	return task.Run(ctx, requestDone, responseDone)
+}
+
+// echoDispatcher echoes what is sent to it, and keeps the context of the last dispatch.
+type echoDispatcher struct {
+	dispatched chan context.Context
+}
+
+func (d *echoDispatcher) Dispatch(ctx context.Context, dest net.Destination) (*transport.Link, error) {
+	uplinkReader, uplinkWriter := pipe.New()
+	downlinkReader, downlinkWriter := pipe.New()
+	go func() {
+		defer downlinkWriter.Close()
+		buf.Copy(uplinkReader, downlinkWriter)
+	}()
+	d.dispatched <- ctx
+	return &transport.Link{Reader: downlinkReader, Writer: uplinkWriter}, nil
+}
+
+func (*echoDispatcher) DispatchLink(ctx context.Context, dest net.Destination, link *transport.Link) error {
+	return nil
+}
+
+func (*echoDispatcher) Start() error {
+	return nil
+}
+
+func (*echoDispatcher) Close() error {
+	return nil
+}
+
+func (*echoDispatcher) Type() interface{} {
+	return routing.DispatcherType()
+}
+
+func TestStandaloneWorker(t *testing.T) {
+	inbound := &standaloneInbound{}
+	dispatcher := &echoDispatcher{dispatched: make(chan context.Context, 1)}
+	uplinkCounter, downlinkCounter := new(app_stats.Counter), new(app_stats.Counter)
+	worker := &standaloneWorker{
+		proxy:      inbound,
+		tag:        "tun",
+		dispatcher: dispatcher,
+		sniffingConfig: &proxyman.SniffingConfig{
+			Enabled:             true,
+			DestinationOverride: []string{"http", "tls"},
+			RouteOnly:           true,
+		},
+		uplinkCounter:   uplinkCounter,
+		downlinkCounter: downlinkCounter,
+		ctx:             context.Background(),
+	}
+	common.Must(worker.Start())
+	if worker.Port() != 0 {
+		t.Error("expected no port, but got ", worker.Port())
+	}
+
+	// The connection of the network stack, from the client to its original destination.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	common.Must(err)
+	defer listener.Close()
+	client, err := net.Dial("tcp", listener.Addr().String())
+	common.Must(err)
+	defer client.Close()
+	conn, err := listener.Accept()
+	common.Must(err)
+
+	dest := net.TCPDestination(net.ParseAddress("192.0.2.1"), 443)
+	go inbound.handler(conn, dest)
+
+	payload := []byte("hello")
+	common.Must2(client.Write(payload))
+	echoed := make([]byte, len(payload))
+	common.Must2(io.ReadFull(client, echoed))
+	if r := cmp.Diff(echoed, payload); r != "" {
+		t.Error(r)
+	}
+
+	var ctx context.Context
+	select {
+	case ctx = <-dispatcher.dispatched:
+	case <-time.After(5 * time.Second):
+		t.Fatal("connection not dispatched")
+	}
+	if in := session.InboundFromContext(ctx); in == nil || in.Tag != "tun" || in.Conn == nil ||
+		in.Source != net.DestinationFromAddr(client.LocalAddr()) {
+		t.Error("unexpected inbound: ", in)
+	}
+	if outbound := session.OutboundFromContext(ctx); outbound == nil || outbound.Target != dest {
+		t.Error("unexpected outbound: ", outbound)
+	}
+	content := session.ContentFromContext(ctx)
+	if content == nil {
+		t.Fatal("no content")
+	}
+	if r := cmp.Diff(content.SniffingRequest, session.SniffingRequest{
+		Enabled:                        true,
+		OverrideDestinationForProtocol: []string{"http", "tls"},
+		RouteOnly:                      true,
+	}); r != "" {
+		t.Error(r)
+	}
+
+	client.Close()
+	<-ctx.Done()
+	if uplinkCounter.Value() != int64(len(payload)) || downlinkCounter.Value() != int64(len(payload)) {
+		t.Error("unexpected traffic: ", uplinkCounter.Value(), " up, ", downlinkCounter.Value(), " down")
+	}
+
+	common.Must(worker.Close())
+	if !inbound.closed {
+		t.Error("inbound not closed")
+	}
+}
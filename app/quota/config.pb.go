@@ -0,0 +1,331 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.33.0
+// 	protoc        v4.23.1
+// source: app/quota/config.proto
+
+package quota
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// ResetPeriod is how often the usage of a quota is reset, in local time.
+type ResetPeriod int32
+
+const (
+	ResetPeriod_Never   ResetPeriod = 0
+	ResetPeriod_Daily   ResetPeriod = 1
+	ResetPeriod_Weekly  ResetPeriod = 2
+	ResetPeriod_Monthly ResetPeriod = 3
+)
+
+// Enum value maps for ResetPeriod.
+var (
+	ResetPeriod_name = map[int32]string{
+		0: "Never",
+		1: "Daily",
+		2: "Weekly",
+		3: "Monthly",
+	}
+	ResetPeriod_value = map[string]int32{
+		"Never":   0,
+		"Daily":   1,
+		"Weekly":  2,
+		"Monthly": 3,
+	}
+)
+
+func (x ResetPeriod) Enum() *ResetPeriod {
+	p := new(ResetPeriod)
+	*p = x
+	return p
+}
+
+func (x ResetPeriod) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ResetPeriod) Descriptor() protoreflect.EnumDescriptor {
+	return file_app_quota_config_proto_enumTypes[0].Descriptor()
+}
+
+func (ResetPeriod) Type() protoreflect.EnumType {
+	return &file_app_quota_config_proto_enumTypes[0]
+}
+
+func (x ResetPeriod) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ResetPeriod.Descriptor instead.
+func (ResetPeriod) EnumDescriptor() ([]byte, []int) {
+	return file_app_quota_config_proto_rawDescGZIP(), []int{0}
+}
+
+type UserQuota struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Email string `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	// Traffic budget in bytes, counting both uplink and downlink. 0 means
+	// unlimited, while the usage is still counted. Connections of the user
+	// are closed once it is used up.
+	Bytes       uint64      `protobuf:"varint,2,opt,name=bytes,proto3" json:"bytes,omitempty"`
+	ResetPeriod ResetPeriod `protobuf:"varint,3,opt,name=reset_period,json=resetPeriod,proto3,enum=xray.app.quota.ResetPeriod" json:"reset_period,omitempty"`
+	// Day of the reset. 0 (Sunday) to 6 for weekly reset, and 1 to 28 for
+	// monthly reset.
+	ResetDay uint32 `protobuf:"varint,4,opt,name=reset_day,json=resetDay,proto3" json:"reset_day,omitempty"`
+}
+
+func (x *UserQuota) Reset() {
+	*x = UserQuota{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_app_quota_config_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UserQuota) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserQuota) ProtoMessage() {}
+
+func (x *UserQuota) ProtoReflect() protoreflect.Message {
+	mi := &file_app_quota_config_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserQuota.ProtoReflect.Descriptor instead.
+func (*UserQuota) Descriptor() ([]byte, []int) {
+	return file_app_quota_config_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *UserQuota) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *UserQuota) GetBytes() uint64 {
+	if x != nil {
+		return x.Bytes
+	}
+	return 0
+}
+
+func (x *UserQuota) GetResetPeriod() ResetPeriod {
+	if x != nil {
+		return x.ResetPeriod
+	}
+	return ResetPeriod_Never
+}
+
+func (x *UserQuota) GetResetDay() uint32 {
+	if x != nil {
+		return x.ResetDay
+	}
+	return 0
+}
+
+type Config struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	User []*UserQuota `protobuf:"bytes,1,rep,name=user,proto3" json:"user,omitempty"`
+	// Path of the file where the usage is persisted. Usage is kept in memory
+	// only if empty.
+	StateFile string `protobuf:"bytes,2,opt,name=state_file,json=stateFile,proto3" json:"state_file,omitempty"`
+	// Interval in seconds to save the usage to state_file. Default 60.
+	SaveInterval uint32 `protobuf:"varint,3,opt,name=save_interval,json=saveInterval,proto3" json:"save_interval,omitempty"`
+}
+
+func (x *Config) Reset() {
+	*x = Config{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_app_quota_config_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Config) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Config) ProtoMessage() {}
+
+func (x *Config) ProtoReflect() protoreflect.Message {
+	mi := &file_app_quota_config_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Config.ProtoReflect.Descriptor instead.
+func (*Config) Descriptor() ([]byte, []int) {
+	return file_app_quota_config_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Config) GetUser() []*UserQuota {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+func (x *Config) GetStateFile() string {
+	if x != nil {
+		return x.StateFile
+	}
+	return ""
+}
+
+func (x *Config) GetSaveInterval() uint32 {
+	if x != nil {
+		return x.SaveInterval
+	}
+	return 0
+}
+
+var File_app_quota_config_proto protoreflect.FileDescriptor
+
+var file_app_quota_config_proto_rawDesc = []byte{
+	0x0a, 0x16, 0x61, 0x70, 0x70, 0x2f, 0x71, 0x75, 0x6f, 0x74, 0x61, 0x2f, 0x63, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0e, 0x78, 0x72, 0x61, 0x79, 0x2e, 0x61,
+	0x70, 0x70, 0x2e, 0x71, 0x75, 0x6f, 0x74, 0x61, 0x22, 0x94, 0x01, 0x0a, 0x09, 0x55, 0x73, 0x65,
+	0x72, 0x51, 0x75, 0x6f, 0x74, 0x61, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x12, 0x14, 0x0a, 0x05,
+	0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x05, 0x62, 0x79, 0x74,
+	0x65, 0x73, 0x12, 0x3e, 0x0a, 0x0c, 0x72, 0x65, 0x73, 0x65, 0x74, 0x5f, 0x70, 0x65, 0x72, 0x69,
+	0x6f, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x78, 0x72, 0x61, 0x79, 0x2e,
+	0x61, 0x70, 0x70, 0x2e, 0x71, 0x75, 0x6f, 0x74, 0x61, 0x2e, 0x52, 0x65, 0x73, 0x65, 0x74, 0x50,
+	0x65, 0x72, 0x69, 0x6f, 0x64, 0x52, 0x0b, 0x72, 0x65, 0x73, 0x65, 0x74, 0x50, 0x65, 0x72, 0x69,
+	0x6f, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x72, 0x65, 0x73, 0x65, 0x74, 0x5f, 0x64, 0x61, 0x79, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x72, 0x65, 0x73, 0x65, 0x74, 0x44, 0x61, 0x79, 0x22,
+	0x7b, 0x0a, 0x06, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x2d, 0x0a, 0x04, 0x75, 0x73, 0x65,
+	0x72, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x78, 0x72, 0x61, 0x79, 0x2e, 0x61,
+	0x70, 0x70, 0x2e, 0x71, 0x75, 0x6f, 0x74, 0x61, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x51, 0x75, 0x6f,
+	0x74, 0x61, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x74, 0x61, 0x74,
+	0x65, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x74,
+	0x61, 0x74, 0x65, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x73, 0x61, 0x76, 0x65, 0x5f,
+	0x69, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c,
+	0x73, 0x61, 0x76, 0x65, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x2a, 0x3c, 0x0a, 0x0b,
+	0x52, 0x65, 0x73, 0x65, 0x74, 0x50, 0x65, 0x72, 0x69, 0x6f, 0x64, 0x12, 0x09, 0x0a, 0x05, 0x4e,
+	0x65, 0x76, 0x65, 0x72, 0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x44, 0x61, 0x69, 0x6c, 0x79, 0x10,
+	0x01, 0x12, 0x0a, 0x0a, 0x06, 0x57, 0x65, 0x65, 0x6b, 0x6c, 0x79, 0x10, 0x02, 0x12, 0x0b, 0x0a,
+	0x07, 0x4d, 0x6f, 0x6e, 0x74, 0x68, 0x6c, 0x79, 0x10, 0x03, 0x42, 0x4c, 0x0a, 0x12, 0x63, 0x6f,
+	0x6d, 0x2e, 0x78, 0x72, 0x61, 0x79, 0x2e, 0x61, 0x70, 0x70, 0x2e, 0x71, 0x75, 0x6f, 0x74, 0x61,
+	0x50, 0x01, 0x5a, 0x23, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x78,
+	0x74, 0x6c, 0x73, 0x2f, 0x78, 0x72, 0x61, 0x79, 0x2d, 0x63, 0x6f, 0x72, 0x65, 0x2f, 0x61, 0x70,
+	0x70, 0x2f, 0x71, 0x75, 0x6f, 0x74, 0x61, 0xaa, 0x02, 0x0e, 0x58, 0x72, 0x61, 0x79, 0x2e, 0x41,
+	0x70, 0x70, 0x2e, 0x51, 0x75, 0x6f, 0x74, 0x61, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_app_quota_config_proto_rawDescOnce sync.Once
+	file_app_quota_config_proto_rawDescData = file_app_quota_config_proto_rawDesc
+)
+
+func file_app_quota_config_proto_rawDescGZIP() []byte {
+	file_app_quota_config_proto_rawDescOnce.Do(func() {
+		file_app_quota_config_proto_rawDescData = protoimpl.X.CompressGZIP(file_app_quota_config_proto_rawDescData)
+	})
+	return file_app_quota_config_proto_rawDescData
+}
+
+var file_app_quota_config_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_app_quota_config_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_app_quota_config_proto_goTypes = []interface{}{
+	(ResetPeriod)(0),  // 0: xray.app.quota.ResetPeriod
+	(*UserQuota)(nil), // 1: xray.app.quota.UserQuota
+	(*Config)(nil),    // 2: xray.app.quota.Config
+}
+var file_app_quota_config_proto_depIdxs = []int32{
+	0, // 0: xray.app.quota.UserQuota.reset_period:type_name -> xray.app.quota.ResetPeriod
+	1, // 1: xray.app.quota.Config.user:type_name -> xray.app.quota.UserQuota
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_app_quota_config_proto_init() }
+func file_app_quota_config_proto_init() {
+	if File_app_quota_config_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_app_quota_config_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UserQuota); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_app_quota_config_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Config); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_app_quota_config_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_app_quota_config_proto_goTypes,
+		DependencyIndexes: file_app_quota_config_proto_depIdxs,
+		EnumInfos:         file_app_quota_config_proto_enumTypes,
+		MessageInfos:      file_app_quota_config_proto_msgTypes,
+	}.Build()
+	File_app_quota_config_proto = out.File
+	file_app_quota_config_proto_rawDesc = nil
+	file_app_quota_config_proto_goTypes = nil
+	file_app_quota_config_proto_depIdxs = nil
+}
@@ -0,0 +1,282 @@
+package quota
+
+//go:generate go run github.com/xtls/xray-core/common/errors/errorgen
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/task"
+	"github.com/xtls/xray-core/core"
+	"github.com/xtls/xray-core/features/quota"
+	"github.com/xtls/xray-core/features/routing"
+)
+
+// userQuota is an implementation of quota.Quota.
+type userQuota struct {
+	email string
+	// limit is the traffic budget in bytes, or 0 if unlimited.
+	limit    int64
+	used     int64
+	exceeded int32
+	period   ResetPeriod
+	resetDay uint32
+
+	access      sync.Mutex
+	periodStart time.Time
+
+	// onExceeded is called once the quota is used up, if not nil.
+	onExceeded func()
+}
+
+// Consume implements quota.Quota.
+func (q *userQuota) Consume(n int64) error {
+	if used := atomic.AddInt64(&q.used, n); q.limit == 0 || used <= q.limit {
+		return nil
+	}
+	// A new period may have begun since the last check of the periodic task.
+	if q.resetIfDue(time.Now()) {
+		return nil
+	}
+	if atomic.CompareAndSwapInt32(&q.exceeded, 0, 1) {
+		newError("user ", q.email, " has used up the traffic quota of ", q.limit, " bytes").AtWarning().WriteToLog()
+		if q.onExceeded != nil {
+			// Consume is called by writers of the connections to be closed.
+			go q.onExceeded()
+		}
+	}
+	return quota.ErrExceeded
+}
+
+// Exceeded implements quota.Quota.
+func (q *userQuota) Exceeded() bool {
+	if q.limit == 0 || atomic.LoadInt64(&q.used) < q.limit {
+		return false
+	}
+	return !q.resetIfDue(time.Now())
+}
+
+// resetIfDue resets the usage if a new period has begun since the current one started, and returns whether it has.
+func (q *userQuota) resetIfDue(now time.Time) bool {
+	q.access.Lock()
+	defer q.access.Unlock()
+
+	next := nextReset(q.period, q.resetDay, q.periodStart)
+	if next.IsZero() || now.Before(next) {
+		return false
+	}
+	atomic.StoreInt64(&q.used, 0)
+	atomic.StoreInt32(&q.exceeded, 0)
+	q.periodStart = now
+	newError("traffic quota of user ", q.email, " is reset").AtInfo().WriteToLog()
+	return true
+}
+
+// nextReset returns the first reset time after from, or zero time if the quota is never reset.
+func nextReset(period ResetPeriod, day uint32, from time.Time) time.Time {
+	y, m, d := from.Date()
+	loc := from.Location()
+	switch period {
+	case ResetPeriod_Daily:
+		return time.Date(y, m, d+1, 0, 0, 0, 0, loc)
+	case ResetPeriod_Weekly:
+		days := (int(day%7) - int(from.Weekday()) + 7) % 7
+		if days == 0 {
+			days = 7
+		}
+		return time.Date(y, m, d+days, 0, 0, 0, 0, loc)
+	case ResetPeriod_Monthly:
+		if day < 1 {
+			day = 1
+		} else if day > 28 {
+			day = 28
+		}
+		next := time.Date(y, m, int(day), 0, 0, 0, 0, loc)
+		if !next.After(from) {
+			next = time.Date(y, m+1, int(day), 0, 0, 0, 0, loc)
+		}
+		return next
+	default:
+		return time.Time{}
+	}
+}
+
+// userState is the persisted usage of a user.
+type userState struct {
+	Used        int64 `json:"used"`
+	PeriodStart int64 `json:"periodStart"`
+}
+
+// Manager is an implementation of quota.Manager.
+type Manager struct {
+	users     map[string]*userQuota
+	stateFile string
+	task      *task.Periodic
+	// connections is used to close the connections of users who have used up their quotas. May be nil.
+	connections routing.ConnectionManager
+}
+
+// New creates a new quota Manager with the given config.
+func New(ctx context.Context, config *Config) (*Manager, error) {
+	now := time.Now()
+	m := &Manager{
+		users:     make(map[string]*userQuota, len(config.User)),
+		stateFile: config.StateFile,
+	}
+	for _, u := range config.User {
+		if len(u.Email) == 0 {
+			return nil, newError("email of user quota can not be empty")
+		}
+		email := u.Email
+		m.users[email] = &userQuota{
+			email:       email,
+			limit:       int64(u.Bytes),
+			period:      u.ResetPeriod,
+			resetDay:    u.ResetDay,
+			periodStart: now,
+			onExceeded: func() {
+				m.closeConnections(email)
+			},
+		}
+	}
+
+	if v := core.FromContext(ctx); v != nil {
+		if err := v.RequireFeatures(func(d routing.Dispatcher) {
+			m.connections, _ = d.(routing.ConnectionManager)
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := m.load(); err != nil {
+		return nil, newError("failed to load quota state from ", m.stateFile).Base(err)
+	}
+
+	interval := time.Minute
+	if config.SaveInterval > 0 {
+		interval = time.Duration(config.SaveInterval) * time.Second
+	}
+	m.task = &task.Periodic{
+		Interval: interval,
+		Execute: func() error {
+			now := time.Now()
+			for _, q := range m.users {
+				q.resetIfDue(now)
+			}
+			if err := m.save(); err != nil {
+				newError("failed to save quota state to ", m.stateFile).Base(err).AtWarning().WriteToLog()
+			}
+			return nil
+		},
+	}
+
+	return m, nil
+}
+
+// closeConnections closes the connections of the user, who has used up the quota.
+func (m *Manager) closeConnections(email string) {
+	if m.connections == nil {
+		return
+	}
+	if n := m.connections.CloseUserConnections(email); n > 0 {
+		newError("closed ", n, " connections of user ", email, " over the traffic quota").AtInfo().WriteToLog()
+	}
+}
+
+func (m *Manager) load() error {
+	if len(m.stateFile) == 0 {
+		return nil
+	}
+	data, err := os.ReadFile(m.stateFile)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	states := make(map[string]*userState)
+	if err := json.Unmarshal(data, &states); err != nil {
+		return err
+	}
+	now := time.Now()
+	for email, state := range states {
+		q, found := m.users[email]
+		if !found {
+			continue
+		}
+		q.used = state.Used
+		q.periodStart = time.Unix(state.PeriodStart, 0)
+		q.resetIfDue(now)
+	}
+	return nil
+}
+
+func (m *Manager) save() error {
+	if len(m.stateFile) == 0 {
+		return nil
+	}
+	states := make(map[string]*userState, len(m.users))
+	for email, q := range m.users {
+		q.access.Lock()
+		states[email] = &userState{
+			Used:        atomic.LoadInt64(&q.used),
+			PeriodStart: q.periodStart.Unix(),
+		}
+		q.access.Unlock()
+	}
+	data, err := json.Marshal(states)
+	if err != nil {
+		return err
+	}
+	// Write to a temporary file first, so that the state file is never left half written.
+	tmp, err := os.CreateTemp(filepath.Dir(m.stateFile), filepath.Base(m.stateFile)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), m.stateFile)
+}
+
+// Type implements common.HasType.
+func (*Manager) Type() interface{} {
+	return quota.ManagerType()
+}
+
+// ForUser implements quota.Manager.
+func (m *Manager) ForUser(email string) quota.Quota {
+	if q, found := m.users[email]; found {
+		return q
+	}
+	return nil
+}
+
+// Start implements common.Runnable.
+func (m *Manager) Start() error {
+	return m.task.Start()
+}
+
+// Close implements common.Closable.
+func (m *Manager) Close() error {
+	m.task.Close()
+	return m.save()
+}
+
+func init() {
+	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
+		return New(ctx, config.(*Config))
+	}))
+}
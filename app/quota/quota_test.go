@@ -0,0 +1,95 @@
+package quota
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/xtls/xray-core/common"
+	feature_quota "github.com/xtls/xray-core/features/quota"
+)
+
+func TestQuotaConsume(t *testing.T) {
+	m, err := New(context.Background(), &Config{
+		User: []*UserQuota{
+			{Email: "test@example.com", Bytes: 1000},
+			{Email: "unlimited@example.com"},
+		},
+	})
+	common.Must(err)
+
+	if q := m.ForUser("other@example.com"); q != nil {
+		t.Error("expected no quota for user without one")
+	}
+
+	q := m.ForUser("test@example.com")
+	if err := q.Consume(600); err != nil {
+		t.Error("unexpected error: ", err)
+	}
+	if q.Exceeded() {
+		t.Error("quota should not be exceeded")
+	}
+	if err := q.Consume(600); err != feature_quota.ErrExceeded {
+		t.Error("expected ErrExceeded, but got ", err)
+	}
+	if !q.Exceeded() {
+		t.Error("quota should be exceeded")
+	}
+
+	q = m.ForUser("unlimited@example.com")
+	if err := q.Consume(1 << 40); err != nil {
+		t.Error("unexpected error of unlimited quota: ", err)
+	}
+	if q.Exceeded() {
+		t.Error("unlimited quota should not be exceeded")
+	}
+}
+
+func TestQuotaPersistence(t *testing.T) {
+	config := &Config{
+		User: []*UserQuota{
+			{Email: "test@example.com", Bytes: 1000},
+			{Email: "daily@example.com", Bytes: 1000, ResetPeriod: ResetPeriod_Daily},
+		},
+		StateFile: filepath.Join(t.TempDir(), "quota.json"),
+	}
+
+	m, err := New(context.Background(), config)
+	common.Must(err)
+	common.Must(m.Start())
+	m.ForUser("test@example.com").Consume(1000)
+	m.ForUser("daily@example.com").Consume(1000)
+	common.Must(m.Close())
+
+	m, err = New(context.Background(), config)
+	common.Must(err)
+	if !m.ForUser("test@example.com").Exceeded() {
+		t.Error("usage should be restored from state file")
+	}
+	if !m.ForUser("daily@example.com").Exceeded() {
+		t.Error("usage within the same day should be restored from state file")
+	}
+}
+
+func TestNextReset(t *testing.T) {
+	// 2024-01-10 is a Wednesday.
+	from := time.Date(2024, 1, 10, 15, 30, 0, 0, time.UTC)
+	cases := []struct {
+		period ResetPeriod
+		day    uint32
+		want   time.Time
+	}{
+		{ResetPeriod_Never, 0, time.Time{}},
+		{ResetPeriod_Daily, 0, time.Date(2024, 1, 11, 0, 0, 0, 0, time.UTC)},
+		{ResetPeriod_Weekly, 1, time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)},
+		{ResetPeriod_Weekly, 3, time.Date(2024, 1, 17, 0, 0, 0, 0, time.UTC)},
+		{ResetPeriod_Monthly, 15, time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)},
+		{ResetPeriod_Monthly, 1, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, c := range cases {
+		if got := nextReset(c.period, c.day, from); !got.Equal(c.want) {
+			t.Error("next reset of ", c.period, " on day ", c.day, ": expected ", c.want, " but got ", got)
+		}
+	}
+}
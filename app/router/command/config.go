@@ -35,6 +35,16 @@ func (c routingContext) GetSkipDNSResolve() bool {
 	return false
 }
 
+// GetProcess is a mock implementation here to match the interface, as the process is not part of the protobuf message.
+func (c routingContext) GetProcess() *routing.Process {
+	return nil
+}
+
+// GetAction is a mock implementation here to match the interface, as actions are not part of the protobuf message.
+func (c routingContext) GetAction() *routing.Action {
+	return nil
+}
+
 // AsRoutingContext converts a protobuf RoutingContext into an implementation of routing.Context.
 func AsRoutingContext(r *RoutingContext) routing.Context {
 	return routingContext{r}
@@ -58,6 +68,7 @@ var fieldMap = map[string]func(*RoutingContext, routing.Route){
 	"attributes":     func(s *RoutingContext, r routing.Route) { s.Attributes = r.GetAttributes() },
 	"outbound_group": func(s *RoutingContext, r routing.Route) { s.OutboundGroupTags = r.GetOutboundGroupTags() },
 	"outbound":       func(s *RoutingContext, r routing.Route) { s.OutboundTag = r.GetOutboundTag() },
+	"rule":           func(s *RoutingContext, r routing.Route) { s.RuleTag = r.GetRuleTag() },
 }
 
 // AsProtobufMessage takes selectors of fields and returns a function to convert routing.Route to protobuf RoutingContext.
@@ -1,9 +1,13 @@
 package router
 
 import (
+	"path"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/xtls/xray-core/common"
 	"github.com/xtls/xray-core/common/net"
 	"github.com/xtls/xray-core/common/strmatcher"
 	"github.com/xtls/xray-core/features/routing"
@@ -25,6 +29,14 @@ func (v *ConditionChan) Add(cond Condition) *ConditionChan {
 	return v
 }
 
+// Close releases the resources of the conditions, such as rule-set files.
+func (v *ConditionChan) Close() error {
+	for _, cond := range *v {
+		common.Close(cond)
+	}
+	return nil
+}
+
 // Apply applies all conditions registered in this chan.
 func (v *ConditionChan) Apply(ctx routing.Context) bool {
 	for _, cond := range *v {
@@ -309,3 +321,160 @@ func (m *AttributeMatcher) Apply(ctx routing.Context) bool {
 	}
 	return m.Match(attributes)
 }
+
+const minutesPerDay = 24 * 60
+
+// TimeMatcher matches the time a connection is routed against time windows.
+type TimeMatcher struct {
+	ranges   []*TimeRange
+	location *time.Location
+}
+
+func NewTimeMatcher(ranges []*TimeRange, timezone string) (*TimeMatcher, error) {
+	location, err := ParseTimezone(timezone)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range ranges {
+		if r.Start >= minutesPerDay || r.End > minutesPerDay {
+			return nil, newError("invalid time range ", r.Start, "-", r.End)
+		}
+		for _, day := range r.Weekday {
+			if day > 6 {
+				return nil, newError("invalid weekday ", day)
+			}
+		}
+	}
+	return &TimeMatcher{
+		ranges:   ranges,
+		location: location,
+	}, nil
+}
+
+// ParseTimezone returns the location of an IANA timezone name, or of a fixed offset like "+08:00".
+// It returns the local timezone if the name is empty.
+func ParseTimezone(name string) (*time.Location, error) {
+	if len(name) == 0 {
+		return time.Local, nil
+	}
+	if name[0] == '+' || name[0] == '-' {
+		hours, minutes, _ := strings.Cut(name[1:], ":")
+		h, err := strconv.ParseUint(hours, 10, 8)
+		if err != nil || h > 14 {
+			return nil, newError("invalid timezone offset ", name)
+		}
+		var m uint64
+		if len(minutes) > 0 {
+			if m, err = strconv.ParseUint(minutes, 10, 8); err != nil || m > 59 {
+				return nil, newError("invalid timezone offset ", name)
+			}
+		}
+		offset := int(h*3600 + m*60)
+		if name[0] == '-' {
+			offset = -offset
+		}
+		return time.FixedZone(name, offset), nil
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, newError("failed to load timezone ", name).Base(err)
+	}
+	return location, nil
+}
+
+// Match returns true if t is in any of the time windows.
+func (m *TimeMatcher) Match(t time.Time) bool {
+	t = t.In(m.location)
+	day := uint32(t.Weekday())
+	yesterday := (day + 6) % 7
+	minute := uint32(t.Hour()*60 + t.Minute())
+	for _, r := range m.ranges {
+		if r.Start < r.End {
+			if minute >= r.Start && minute < r.End && r.onWeekday(day) {
+				return true
+			}
+			continue
+		}
+		// The window wraps around midnight.
+		if minute >= r.Start && r.onWeekday(day) {
+			return true
+		}
+		if minute < r.End && r.onWeekday(yesterday) {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply implements Condition.
+func (m *TimeMatcher) Apply(ctx routing.Context) bool {
+	return m.Match(time.Now())
+}
+
+func (r *TimeRange) onWeekday(day uint32) bool {
+	if len(r.Weekday) == 0 {
+		return true
+	}
+	for _, d := range r.Weekday {
+		if d == day {
+			return true
+		}
+	}
+	return false
+}
+
+// ProcessMatcher matches the local process that originated a connection by the path or the name of its executable.
+type ProcessMatcher struct {
+	paths map[string]bool
+	names map[string]bool
+}
+
+func NewProcessMatcher(processes []string) *ProcessMatcher {
+	m := &ProcessMatcher{
+		paths: make(map[string]bool),
+		names: make(map[string]bool),
+	}
+	for _, p := range processes {
+		switch {
+		case len(p) == 0:
+		case strings.Contains(p, "/"):
+			m.paths[p] = true
+		default:
+			m.names[p] = true
+		}
+	}
+	return m
+}
+
+// Apply implements Condition.
+func (m *ProcessMatcher) Apply(ctx routing.Context) bool {
+	process := ctx.GetProcess()
+	if process == nil || len(process.Path) == 0 {
+		return false
+	}
+	return m.paths[process.Path] || m.names[path.Base(process.Path)]
+}
+
+// UIDMatcher matches the owner of the local process that originated a connection.
+type UIDMatcher struct {
+	uids map[uint32]bool
+}
+
+func NewUIDMatcher(uids []uint32) *UIDMatcher {
+	m := &UIDMatcher{
+		uids: make(map[uint32]bool, len(uids)),
+	}
+	for _, uid := range uids {
+		m.uids[uid] = true
+	}
+	return m
+}
+
+// Apply implements Condition.
+func (m *UIDMatcher) Apply(ctx routing.Context) bool {
+	process := ctx.GetProcess()
+	if process == nil {
+		return false
+	}
+	return m.uids[process.UID]
+}
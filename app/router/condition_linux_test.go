@@ -0,0 +1,80 @@
+//go:build linux
+
+package router_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/xtls/xray-core/app/router"
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/session"
+	routing_session "github.com/xtls/xray-core/features/routing/session"
+)
+
+func TestProcessMatcher(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	common.Must(err)
+	defer listener.Close()
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	common.Must(err)
+	defer conn.Close()
+
+	executable, err := os.Executable()
+	common.Must(err)
+	executable, err = filepath.EvalSymlinks(executable)
+	common.Must(err)
+
+	cases := []struct {
+		rule  *RoutingRule
+		match bool
+	}{
+		{&RoutingRule{ProcessName: []string{filepath.Base(executable)}}, true},
+		{&RoutingRule{ProcessName: []string{executable}}, true},
+		{&RoutingRule{ProcessName: []string{"/usr/bin/" + filepath.Base(executable)}}, false},
+		{&RoutingRule{ProcessName: []string{"curl"}}, false},
+		{&RoutingRule{Uid: []uint32{uint32(os.Getuid())}}, true},
+		{&RoutingRule{Uid: []uint32{uint32(os.Getuid()) + 1}}, false},
+	}
+	for _, c := range cases {
+		cond, err := c.rule.BuildCondition()
+		common.Must(err)
+
+		// The connection is from this process, as seen by a TPROXY or a local inbound.
+		ctx := &routing_session.Context{
+			Inbound:  &session.Inbound{Source: net.DestinationFromAddr(conn.LocalAddr())},
+			Outbound: &session.Outbound{Target: net.DestinationFromAddr(conn.RemoteAddr())},
+		}
+		if actual := cond.Apply(ctx); actual != c.match {
+			t.Error("rule ", c.rule, ": expected ", c.match, ", but got ", actual)
+		}
+	}
+
+	// A connection opened after the owners of sockets were cached is found too.
+	conn2, err := net.Dial("tcp", listener.Addr().String())
+	common.Must(err)
+	defer conn2.Close()
+	cond, err := (&RoutingRule{ProcessName: []string{executable}}).BuildCondition()
+	common.Must(err)
+	ctx := &routing_session.Context{
+		Inbound:  &session.Inbound{Source: net.DestinationFromAddr(conn2.LocalAddr())},
+		Outbound: &session.Outbound{Target: net.DestinationFromAddr(conn2.RemoteAddr())},
+	}
+	if !cond.Apply(ctx) {
+		t.Error("expected a match for a new connection")
+	}
+
+	// Connections not from a local process never match.
+	cond, err = (&RoutingRule{Uid: []uint32{uint32(os.Getuid())}}).BuildCondition()
+	common.Must(err)
+	ctx = &routing_session.Context{
+		Inbound:  &session.Inbound{Source: net.TCPDestination(net.ParseAddress("192.0.2.1"), 443)},
+		Outbound: &session.Outbound{Target: net.TCPDestination(net.LocalHostIP, 80)},
+	}
+	if cond.Apply(ctx) {
+		t.Error("expected no match for a remote source")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"testing"
+	"time"
 
 	. "github.com/xtls/xray-core/app/router"
 	"github.com/xtls/xray-core/common"
@@ -548,3 +549,41 @@ func BenchmarkMultiGeoIPMatcher(b *testing.B) {
 		_ = matcher.Apply(ctx)
 	}
 }
+
+func TestTimeMatcher(t *testing.T) {
+	// School hours on weekdays, and the night from Friday to Saturday.
+	matcher, err := NewTimeMatcher([]*TimeRange{
+		{Weekday: []uint32{1, 2, 3, 4, 5}, Start: 8 * 60, End: 15*60 + 30},
+		{Weekday: []uint32{5}, Start: 22 * 60, End: 6 * 60},
+	}, "+08:00")
+	common.Must(err)
+
+	location, err := ParseTimezone("+08:00")
+	common.Must(err)
+	cases := []struct {
+		time  time.Time
+		match bool
+	}{
+		{time.Date(2024, 1, 1, 8, 0, 0, 0, location), true},    // Monday
+		{time.Date(2024, 1, 1, 15, 29, 0, 0, location), true},  // Monday
+		{time.Date(2024, 1, 1, 15, 30, 0, 0, location), false}, // Monday
+		{time.Date(2024, 1, 1, 7, 59, 0, 0, location), false},  // Monday
+		{time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), true},    // Monday 08:00 in +08:00
+		{time.Date(2024, 1, 6, 9, 0, 0, 0, location), false},   // Saturday
+		{time.Date(2024, 1, 5, 23, 0, 0, 0, location), true},   // Friday night
+		{time.Date(2024, 1, 6, 5, 59, 0, 0, location), true},   // Saturday morning
+		{time.Date(2024, 1, 7, 5, 0, 0, 0, location), false},   // Sunday morning
+	}
+	for _, c := range cases {
+		if actual := matcher.Match(c.time); actual != c.match {
+			t.Error("time ", c.time, ": expected ", c.match, ", but got ", actual)
+		}
+	}
+
+	if _, err := NewTimeMatcher([]*TimeRange{{Start: 24 * 60}}, ""); err == nil {
+		t.Error("expected error for invalid time range")
+	}
+	if _, err := ParseTimezone("Mars/Olympus_Mons"); err == nil {
+		t.Error("expected error for invalid timezone")
+	}
+}
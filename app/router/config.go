@@ -4,6 +4,7 @@ import (
 	"regexp"
 	"strings"
 
+	"github.com/xtls/xray-core/common"
 	"github.com/xtls/xray-core/common/net"
 	"github.com/xtls/xray-core/features/outbound"
 	"github.com/xtls/xray-core/features/routing"
@@ -14,6 +15,12 @@ type Rule struct {
 	RuleTag   string
 	Balancer  *Balancer
 	Condition Condition
+	Action    *routing.Action
+}
+
+// Close releases the resources of the condition of the rule.
+func (r *Rule) Close() error {
+	return common.Close(r.Condition)
 }
 
 func (r *Rule) GetTag() (string, error) {
@@ -28,6 +35,23 @@ func (r *Rule) Apply(ctx routing.Context) bool {
 	return r.Condition.Apply(ctx)
 }
 
+// Build returns the routing action, or nil if the config is nil.
+func (ra *RuleAction) Build() *routing.Action {
+	if ra == nil {
+		return nil
+	}
+	action := &routing.Action{
+		Reject:         routing.RejectType(ra.Reject),
+		Port:           net.Port(ra.Port),
+		DomainStrategy: routing.DomainStrategy(ra.DomainStrategy),
+		Attributes:     ra.Attributes,
+	}
+	if ra.Address != nil {
+		action.Address = ra.Address.AsAddress()
+	}
+	return action
+}
+
 func (rr *RoutingRule) BuildCondition() (Condition, error) {
 	conds := NewConditionChan()
 
@@ -115,6 +139,31 @@ func (rr *RoutingRule) BuildCondition() (Condition, error) {
 		conds.Add(&AttributeMatcher{configuredKeys})
 	}
 
+	if len(rr.Time) > 0 {
+		cond, err := NewTimeMatcher(rr.Time, rr.Timezone)
+		if err != nil {
+			return nil, newError("failed to build time condition").Base(err)
+		}
+		conds.Add(cond)
+	}
+
+	if len(rr.RuleSet) > 0 {
+		cond, err := NewMultiRuleSetMatcher(rr.RuleSet)
+		if err != nil {
+			return nil, newError("failed to build rule-set condition").Base(err)
+		}
+		conds.Add(cond)
+	}
+
+	// The process is looked up only if all the other conditions match, as it is expensive.
+	if len(rr.ProcessName) > 0 {
+		conds.Add(NewProcessMatcher(rr.ProcessName))
+	}
+
+	if len(rr.Uid) > 0 {
+		conds.Add(NewUIDMatcher(rr.Uid))
+	}
+
 	if conds.Len() == 0 {
 		return nil, newError("this rule has no effective fields").AtWarning()
 	}
@@ -35,6 +35,8 @@ type Route struct {
 	routing.Context
 	outboundGroupTags []string
 	outboundTag       string
+	ruleTag           string
+	action            *routing.Action
 }
 
 // Init initializes the Router.
@@ -59,22 +61,25 @@ func (r *Router) Init(ctx context.Context, config *Config, d dns.Client, ohm out
 	for _, rule := range config.Rule {
 		cond, err := rule.BuildCondition()
 		if err != nil {
+			closeRules(r.rules)
 			return err
 		}
 		rr := &Rule{
 			Condition: cond,
 			Tag:       rule.GetTag(),
 			RuleTag:   rule.GetRuleTag(),
+			Action:    rule.GetAction().Build(),
 		}
+		r.rules = append(r.rules, rr)
 		btag := rule.GetBalancingTag()
 		if len(btag) > 0 {
 			brule, found := r.balancers[btag]
 			if !found {
+				closeRules(r.rules)
 				return newError("balancer ", btag, " not found")
 			}
 			rr.Balancer = brule
 		}
-		r.rules = append(r.rules, rr)
 	}
 
 	return nil
@@ -90,7 +95,7 @@ func (r *Router) PickRoute(ctx routing.Context) (routing.Route, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &Route{Context: ctx, outboundTag: tag}, nil
+	return &Route{Context: ctx, outboundTag: tag, ruleTag: rule.RuleTag, action: rule.Action}, nil
 }
 
 // AddRule implements routing.Router.
@@ -131,36 +136,69 @@ func (r *Router) ReloadRules(config *Config, shouldAppend bool) error {
 		balancers[rule.Tag] = balancer
 	}
 
+	// The rules built here are released if the config fails to apply.
+	built := len(rules)
 	for _, rule := range config.Rule {
 		if RuleExists(rules, rule.GetRuleTag()) {
+			closeRules(rules[built:])
 			return newError("duplicate ruleTag ", rule.GetRuleTag())
 		}
 		cond, err := rule.BuildCondition()
 		if err != nil {
+			closeRules(rules[built:])
 			return err
 		}
 		rr := &Rule{
 			Condition: cond,
 			Tag:       rule.GetTag(),
 			RuleTag:   rule.GetRuleTag(),
+			Action:    rule.GetAction().Build(),
 		}
+		rules = append(rules, rr)
 		btag := rule.GetBalancingTag()
 		if len(btag) > 0 {
 			brule, found := balancers[btag]
 			if !found {
+				closeRules(rules[built:])
 				return newError("balancer ", btag, " not found")
 			}
 			rr.Balancer = brule
 		}
-		rules = append(rules, rr)
 	}
 
+	if !shouldAppend {
+		closeRules(r.rules)
+	}
 	r.balancers = balancers
 	r.rules = rules
 
 	return nil
 }
 
+// closeRules releases the resources of the rules.
+func closeRules(rules []*Rule) {
+	for _, rule := range rules {
+		rule.Close()
+	}
+}
+
+// Reload implements features.Reloadable.
+func (r *Router) Reload(config interface{}) error {
+	c, ok := config.(*Config)
+	if !ok {
+		return newError("Reload: config type error")
+	}
+	if err := r.ReloadRules(c, false); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	r.domainStrategy = c.DomainStrategy
+	r.mu.Unlock()
+
+	return nil
+}
+
 func RuleExists(rules []*Rule, tag string) bool {
 	if tag != "" {
 		for _, rule := range rules {
@@ -182,6 +220,8 @@ func (r *Router) RemoveRule(tag string) error {
 		for _, rule := range r.rules {
 			if rule.RuleTag != tag {
 				newRules = append(newRules, rule)
+			} else {
+				rule.Close()
 			}
 		}
 		r.rules = newRules
@@ -196,7 +236,11 @@ func (r *Router) pickRouteInternal(ctx routing.Context) (*Rule, routing.Context,
 	// this prevents cycle resolving dead loop
 	skipDNSResolve := ctx.GetSkipDNSResolve()
 
-	if r.domainStrategy == Config_IpOnDemand && !skipDNSResolve {
+	r.mu.RLock()
+	domainStrategy := r.domainStrategy
+	r.mu.RUnlock()
+
+	if domainStrategy == Config_IpOnDemand && !skipDNSResolve {
 		ctx = routing_dns.ContextWithDNSClient(ctx, r.dns)
 	}
 
@@ -204,7 +248,7 @@ func (r *Router) pickRouteInternal(ctx routing.Context) (*Rule, routing.Context,
 		return rule, ctx, nil
 	}
 
-	if r.domainStrategy != Config_IpIfNonMatch || len(ctx.GetTargetDomain()) == 0 || skipDNSResolve {
+	if domainStrategy != Config_IpIfNonMatch || len(ctx.GetTargetDomain()) == 0 || skipDNSResolve {
 		return nil, ctx, common.ErrNoClue
 	}
 
@@ -238,6 +282,11 @@ func (r *Router) Start() error {
 
 // Close implements common.Closable.
 func (r *Router) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	closeRules(r.rules)
+	r.rules = nil
 	return nil
 }
 
@@ -256,6 +305,16 @@ func (r *Route) GetOutboundTag() string {
 	return r.outboundTag
 }
 
+// GetRuleTag implements routing.Route.
+func (r *Route) GetRuleTag() string {
+	return r.ruleTag
+}
+
+// GetAction implements routing.Route.
+func (r *Route) GetAction() *routing.Action {
+	return r.action
+}
+
 func init() {
 	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
 		r := new(Router)
@@ -5,12 +5,14 @@ import (
 	"testing"
 
 	"github.com/golang/mock/gomock"
+	"github.com/google/go-cmp/cmp"
 	. "github.com/xtls/xray-core/app/router"
 	"github.com/xtls/xray-core/common"
 	"github.com/xtls/xray-core/common/net"
 	"github.com/xtls/xray-core/common/session"
 	"github.com/xtls/xray-core/features/dns"
 	"github.com/xtls/xray-core/features/outbound"
+	"github.com/xtls/xray-core/features/routing"
 	routing_session "github.com/xtls/xray-core/features/routing/session"
 	"github.com/xtls/xray-core/testing/mocks"
 )
@@ -53,6 +55,61 @@ func TestSimpleRouter(t *testing.T) {
 	}
 }
 
+func TestRuleAction(t *testing.T) {
+	config := &Config{
+		Rule: []*RoutingRule{
+			{
+				Domain: []*Domain{{Type: Domain_Plain, Value: "ads.example.com"}},
+				Action: &RuleAction{Reject: RuleAction_Reset},
+			},
+			{
+				TargetTag: &RoutingRule_Tag{
+					Tag: "test",
+				},
+				Networks: []net.Network{net.Network_TCP},
+				Action: &RuleAction{
+					Address:        net.NewIPOrDomain(net.LocalHostIP),
+					Port:           8080,
+					DomainStrategy: RuleAction_ForceIPv6,
+					Attributes:     map[string]string{"key": "value"},
+				},
+			},
+		},
+	}
+
+	mockCtl := gomock.NewController(t)
+	defer mockCtl.Finish()
+
+	r := new(Router)
+	common.Must(r.Init(context.TODO(), config, mocks.NewDNSClient(mockCtl), nil, nil))
+
+	ctx := session.ContextWithOutbound(context.Background(), &session.Outbound{Target: net.TCPDestination(net.DomainAddress("ads.example.com"), 80)})
+	route, err := r.PickRoute(routing_session.AsRoutingContext(ctx))
+	common.Must(err)
+	if tag := route.GetOutboundTag(); tag != "" {
+		t.Error("expect no tag, but actually ", tag)
+	}
+	if action := route.GetAction(); action == nil || action.Reject != routing.RejectReset {
+		t.Error("expect reset action, but actually ", action)
+	}
+
+	ctx = session.ContextWithOutbound(context.Background(), &session.Outbound{Target: net.TCPDestination(net.DomainAddress("example.com"), 80)})
+	route, err = r.PickRoute(routing_session.AsRoutingContext(ctx))
+	common.Must(err)
+	if tag := route.GetOutboundTag(); tag != "test" {
+		t.Error("expect tag 'test', but actually ", tag)
+	}
+	expected := &routing.Action{
+		Address:        net.LocalHostIP,
+		Port:           8080,
+		DomainStrategy: routing.DomainStrategyForceIPv6,
+		Attributes:     map[string]string{"key": "value"},
+	}
+	if r := cmp.Diff(route.GetAction(), expected); r != "" {
+		t.Error(r)
+	}
+}
+
 func TestSimpleBalancer(t *testing.T) {
 	config := &Config{
 		Rule: []*RoutingRule{
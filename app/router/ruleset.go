@@ -0,0 +1,366 @@
+package router
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/task"
+	"github.com/xtls/xray-core/features/routing"
+	"google.golang.org/protobuf/proto"
+)
+
+// ruleSetCheckInterval is how often rule-set files are checked for changes.
+const ruleSetCheckInterval = 10 * time.Second
+
+// LoadRuleSet loads the domains and IPs in the rule-set file.
+func LoadRuleSet(file *RuleSetFile) (*RuleSet, error) {
+	b, err := os.ReadFile(file.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	ruleSet := new(RuleSet)
+	switch file.format() {
+	case RuleSetFile_Binary:
+		if err := proto.Unmarshal(b, ruleSet); err != nil {
+			return nil, newError("invalid binary rule-set ", file.Path).Base(err)
+		}
+	case RuleSetFile_JSON:
+		var list struct {
+			Domain []string `json:"domain"`
+			IP     []string `json:"ip"`
+		}
+		if err := json.Unmarshal(b, &list); err != nil {
+			return nil, newError("invalid JSON rule-set ", file.Path).Base(err)
+		}
+		for _, s := range list.Domain {
+			domain, err := parseRuleSetDomain(s)
+			if err != nil {
+				return nil, err
+			}
+			ruleSet.Domain = append(ruleSet.Domain, domain)
+		}
+		for _, s := range list.IP {
+			cidr, ok := parseRuleSetCIDR(s)
+			if !ok {
+				return nil, newError("invalid IP in rule-set: ", s)
+			}
+			ruleSet.Cidr = append(ruleSet.Cidr, cidr)
+		}
+	default:
+		scanner := bufio.NewScanner(bytes.NewReader(b))
+		for scanner.Scan() {
+			line, _, _ := strings.Cut(scanner.Text(), "#")
+			line = strings.TrimSpace(line)
+			if len(line) == 0 {
+				continue
+			}
+			if cidr, ok := parseRuleSetCIDR(line); ok {
+				ruleSet.Cidr = append(ruleSet.Cidr, cidr)
+				continue
+			}
+			domain, err := parseRuleSetDomain(line)
+			if err != nil {
+				return nil, err
+			}
+			ruleSet.Domain = append(ruleSet.Domain, domain)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	}
+	return ruleSet, nil
+}
+
+func (f *RuleSetFile) format() RuleSetFile_Format {
+	if f.Format != RuleSetFile_Auto {
+		return f.Format
+	}
+	switch strings.ToLower(filepath.Ext(f.Path)) {
+	case ".pb":
+		return RuleSetFile_Binary
+	case ".json":
+		return RuleSetFile_JSON
+	default:
+		return RuleSetFile_Text
+	}
+}
+
+// parseRuleSetDomain parses a domain in a rule-set. Domains without a type match their subdomains too.
+func parseRuleSetDomain(s string) (*Domain, error) {
+	domain := &Domain{Type: Domain_Domain}
+	if prefix, value, found := strings.Cut(s, ":"); found {
+		switch prefix {
+		case "domain":
+		case "full":
+			domain.Type = Domain_Full
+		case "keyword":
+			domain.Type = Domain_Plain
+		case "regexp":
+			domain.Type = Domain_Regex
+			domain.Value = value
+			return domain, nil
+		default:
+			return nil, newError("unknown domain type in rule-set: ", s)
+		}
+		s = value
+	}
+	if len(s) == 0 {
+		return nil, newError("empty domain in rule-set")
+	}
+	domain.Value = strings.ToLower(s)
+	return domain, nil
+}
+
+// parseRuleSetCIDR parses an IP or a CIDR in a rule-set.
+func parseRuleSetCIDR(s string) (*CIDR, bool) {
+	if _, ipNet, err := net.ParseCIDR(s); err == nil {
+		ones, _ := ipNet.Mask.Size()
+		ip := ipNet.IP
+		if ip4 := ip.To4(); ip4 != nil && len(ipNet.Mask) == net.IPv4len {
+			ip = ip4
+		}
+		return &CIDR{Ip: ip, Prefix: uint32(ones)}, true
+	}
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return nil, false
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		return &CIDR{Ip: ip4, Prefix: 32}, true
+	}
+	return &CIDR{Ip: ip, Prefix: 128}, true
+}
+
+type compiledRuleSet struct {
+	domains *DomainMatcher
+	ips     *GeoIPMatcher
+}
+
+func compileRuleSet(ruleSet *RuleSet) (*compiledRuleSet, error) {
+	c := new(compiledRuleSet)
+	if len(ruleSet.Domain) > 0 {
+		domains, err := NewMphMatcherGroup(ruleSet.Domain)
+		if err != nil {
+			return nil, newError("failed to build domain matcher of rule-set").Base(err)
+		}
+		c.domains = domains
+	}
+	if len(ruleSet.Cidr) > 0 {
+		c.ips = new(GeoIPMatcher)
+		if err := c.ips.Init(ruleSet.Cidr); err != nil {
+			return nil, newError("failed to build IP matcher of rule-set").Base(err)
+		}
+	}
+	return c, nil
+}
+
+// RuleSetMatcher matches domains and IPs in a rule-set file. It is recompiled when the file changes, while the
+// matching goes on with the previous content.
+type RuleSetMatcher struct {
+	file     *RuleSetFile
+	key      string
+	compiled atomic.Pointer[compiledRuleSet]
+	// refs is the number of users of the matcher, guarded by the registry.
+	refs int
+
+	access  sync.Mutex
+	modTime time.Time
+	size    int64
+}
+
+// Update reloads the rule-set file if it changed since it was last loaded, and returns whether it is reloaded. The
+// matcher keeps the previous content if the file fails to load.
+func (m *RuleSetMatcher) Update() (bool, error) {
+	m.access.Lock()
+	defer m.access.Unlock()
+
+	info, err := os.Stat(m.file.Path)
+	if err != nil {
+		return false, err
+	}
+	if m.compiled.Load() != nil && info.ModTime().Equal(m.modTime) && info.Size() == m.size {
+		return false, nil
+	}
+	ruleSet, err := LoadRuleSet(m.file)
+	if err != nil {
+		return false, err
+	}
+	compiled, err := compileRuleSet(ruleSet)
+	if err != nil {
+		return false, err
+	}
+	m.compiled.Store(compiled)
+	m.modTime = info.ModTime()
+	m.size = info.Size()
+	newError("rule-set ", m.file.Path, " is loaded with ", len(ruleSet.Domain), " domains and ", len(ruleSet.Cidr), " IPs").AtInfo().WriteToLog()
+	return true, nil
+}
+
+// MatchDomain returns true if the domain is in the rule-set.
+func (m *RuleSetMatcher) MatchDomain(domain string) bool {
+	domains := m.compiled.Load().domains
+	return domains != nil && domains.ApplyDomain(domain)
+}
+
+// MatchIP returns true if the IP is in the rule-set.
+func (m *RuleSetMatcher) MatchIP(ip net.IP) bool {
+	ips := m.compiled.Load().ips
+	return ips != nil && ips.Match(ip)
+}
+
+func (m *RuleSetMatcher) hasIPs() bool {
+	return m.compiled.Load().ips != nil
+}
+
+// Close releases the matcher. Each user of the matcher releases it once, and the file is no longer checked for
+// changes after all of them have.
+func (m *RuleSetMatcher) Close() error {
+	globalRuleSets.release(m)
+	return nil
+}
+
+// ruleSetRegistry keeps the matchers of all rule-set files in use, and checks the files for changes while there are
+// any.
+type ruleSetRegistry struct {
+	sync.Mutex
+	matchers map[string]*RuleSetMatcher
+	checker  *task.Periodic
+}
+
+var globalRuleSets ruleSetRegistry
+
+// NewRuleSetMatcher returns the matcher of the rule-set file. Matchers are shared by all users of the same file, and
+// are updated when the file changes. The matcher must be released by Close once it is no longer used.
+func NewRuleSetMatcher(file *RuleSetFile) (*RuleSetMatcher, error) {
+	return globalRuleSets.get(file)
+}
+
+func (r *ruleSetRegistry) get(file *RuleSetFile) (*RuleSetMatcher, error) {
+	r.Lock()
+	key := file.format().String() + ":" + file.Path
+	if m, found := r.matchers[key]; found {
+		m.refs++
+		r.Unlock()
+		return m, nil
+	}
+
+	m := &RuleSetMatcher{file: file, key: key, refs: 1}
+	if _, err := m.Update(); err != nil {
+		r.Unlock()
+		return nil, newError("failed to load rule-set ", file.Path).Base(err)
+	}
+	if r.matchers == nil {
+		r.matchers = make(map[string]*RuleSetMatcher)
+	}
+	r.matchers[key] = m
+
+	var checker *task.Periodic
+	if r.checker == nil {
+		r.checker = &task.Periodic{
+			Interval: ruleSetCheckInterval,
+			Execute:  r.check,
+		}
+		checker = r.checker
+	}
+	r.Unlock()
+
+	// The checker runs the first check right away, which takes the lock.
+	if checker != nil {
+		checker.Start()
+	}
+	return m, nil
+}
+
+func (r *ruleSetRegistry) release(m *RuleSetMatcher) {
+	r.Lock()
+	m.refs--
+	if m.refs > 0 {
+		r.Unlock()
+		return
+	}
+	delete(r.matchers, m.key)
+	var checker *task.Periodic
+	if len(r.matchers) == 0 {
+		checker = r.checker
+		r.checker = nil
+	}
+	r.Unlock()
+
+	if checker != nil {
+		checker.Close()
+	}
+}
+
+func (r *ruleSetRegistry) check() error {
+	r.Lock()
+	matchers := make([]*RuleSetMatcher, 0, len(r.matchers))
+	for _, m := range r.matchers {
+		matchers = append(matchers, m)
+	}
+	r.Unlock()
+
+	for _, m := range matchers {
+		if _, err := m.Update(); err != nil {
+			newError("failed to reload rule-set ", m.file.Path).Base(err).AtWarning().WriteToLog()
+		}
+	}
+	return nil
+}
+
+// MultiRuleSetMatcher matches the target of connections against rule-sets.
+type MultiRuleSetMatcher struct {
+	matchers []*RuleSetMatcher
+}
+
+func NewMultiRuleSetMatcher(files []*RuleSetFile) (*MultiRuleSetMatcher, error) {
+	m := new(MultiRuleSetMatcher)
+	for _, file := range files {
+		matcher, err := NewRuleSetMatcher(file)
+		if err != nil {
+			m.Close()
+			return nil, err
+		}
+		m.matchers = append(m.matchers, matcher)
+	}
+	return m, nil
+}
+
+// Close releases the rule-set matchers.
+func (m *MultiRuleSetMatcher) Close() error {
+	for _, matcher := range m.matchers {
+		matcher.Close()
+	}
+	return nil
+}
+
+// Apply implements Condition.
+func (m *MultiRuleSetMatcher) Apply(ctx routing.Context) bool {
+	// Domains are matched first, as target IPs may need to be resolved.
+	if domain := ctx.GetTargetDomain(); len(domain) > 0 {
+		for _, matcher := range m.matchers {
+			if matcher.MatchDomain(domain) {
+				return true
+			}
+		}
+	}
+	for _, matcher := range m.matchers {
+		if !matcher.hasIPs() {
+			continue
+		}
+		for _, ip := range ctx.GetTargetIPs() {
+			if matcher.MatchIP(ip) {
+				return true
+			}
+		}
+	}
+	return false
+}
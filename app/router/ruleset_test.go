@@ -0,0 +1,148 @@
+package router_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/xtls/xray-core/app/router"
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/session"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestRuleSet(t *testing.T) {
+	dir := t.TempDir()
+	text := filepath.Join(dir, "list.txt")
+	common.Must(os.WriteFile(text, []byte(`
+# Ads
+ads.example.com
+full:tracker.example.org
+keyword:doubleclick
+regexp:^ad[0-9]+\.example\.net$
+10.0.0.0/8
+2001:db8::1
+`), 0o644))
+	jsonFile := filepath.Join(dir, "list.json")
+	common.Must(os.WriteFile(jsonFile, []byte(`{"domain": ["full:json.example.com"], "ip": ["192.0.2.0/24"]}`), 0o644))
+	ruleSet, err := LoadRuleSet(&RuleSetFile{Path: jsonFile})
+	common.Must(err)
+	b, err := proto.Marshal(ruleSet)
+	common.Must(err)
+	binary := filepath.Join(dir, "list.pb")
+	common.Must(os.WriteFile(binary, b, 0o644))
+
+	cases := []struct {
+		files  []*RuleSetFile
+		target net.Destination
+		match  bool
+	}{
+		{[]*RuleSetFile{{Path: text}}, net.TCPDestination(net.DomainAddress("ads.example.com"), 80), true},
+		{[]*RuleSetFile{{Path: text}}, net.TCPDestination(net.DomainAddress("cdn.ads.example.com"), 80), true},
+		{[]*RuleSetFile{{Path: text}}, net.TCPDestination(net.DomainAddress("www.example.com"), 80), false},
+		{[]*RuleSetFile{{Path: text}}, net.TCPDestination(net.DomainAddress("tracker.example.org"), 80), true},
+		{[]*RuleSetFile{{Path: text}}, net.TCPDestination(net.DomainAddress("a.tracker.example.org"), 80), false},
+		{[]*RuleSetFile{{Path: text}}, net.TCPDestination(net.DomainAddress("www.doubleclick.net"), 80), true},
+		{[]*RuleSetFile{{Path: text}}, net.TCPDestination(net.DomainAddress("ad12.example.net"), 80), true},
+		{[]*RuleSetFile{{Path: text}}, net.TCPDestination(net.ParseAddress("10.1.2.3"), 80), true},
+		{[]*RuleSetFile{{Path: text}}, net.TCPDestination(net.ParseAddress("2001:db8::1"), 80), true},
+		{[]*RuleSetFile{{Path: text}}, net.TCPDestination(net.ParseAddress("192.0.2.1"), 80), false},
+		{[]*RuleSetFile{{Path: jsonFile}}, net.TCPDestination(net.DomainAddress("json.example.com"), 80), true},
+		{[]*RuleSetFile{{Path: binary}}, net.TCPDestination(net.ParseAddress("192.0.2.1"), 80), true},
+		{[]*RuleSetFile{{Path: binary}, {Path: text}}, net.TCPDestination(net.ParseAddress("10.1.2.3"), 80), true},
+		{[]*RuleSetFile{{Path: binary, Format: RuleSetFile_Text}}, net.TCPDestination(net.ParseAddress("192.0.2.1"), 80), false},
+	}
+	for _, c := range cases {
+		cond, err := (&RoutingRule{RuleSet: c.files}).BuildCondition()
+		if err != nil {
+			// The binary file is not valid in the text format.
+			if c.files[0].Format == RuleSetFile_Text {
+				continue
+			}
+			t.Fatal(err)
+		}
+		if actual := cond.Apply(withOutbound(&session.Outbound{Target: c.target})); actual != c.match {
+			t.Error(c.files, " ", c.target, ": expected ", c.match, ", but got ", actual)
+		}
+	}
+
+	if _, err := (&RoutingRule{RuleSet: []*RuleSetFile{{Path: filepath.Join(dir, "missing.txt")}}}).BuildCondition(); err == nil {
+		t.Error("expected error for missing rule-set")
+	}
+}
+
+func TestRuleSetReload(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "list.txt")
+	common.Must(os.WriteFile(file, []byte("old.example.com\n"), 0o644))
+
+	cond, err := (&RoutingRule{RuleSet: []*RuleSetFile{{Path: file}}}).BuildCondition()
+	common.Must(err)
+	matcher, err := NewRuleSetMatcher(&RuleSetFile{Path: file})
+	common.Must(err)
+
+	old := withOutbound(&session.Outbound{Target: net.TCPDestination(net.DomainAddress("old.example.com"), 80)})
+	new := withOutbound(&session.Outbound{Target: net.TCPDestination(net.DomainAddress("new.example.com"), 80)})
+	if !cond.Apply(old) || cond.Apply(new) {
+		t.Fatal("unexpected match before reload")
+	}
+
+	if updated, err := matcher.Update(); err != nil || updated {
+		t.Fatal("expected no update for unchanged file, but got ", updated, err)
+	}
+
+	common.Must(os.WriteFile(file, []byte("new.example.com\n10.0.0.0/8\n"), 0o644))
+	if updated, err := matcher.Update(); err != nil || !updated {
+		t.Fatal("expected update for changed file, but got ", updated, err)
+	}
+	if cond.Apply(old) || !cond.Apply(new) {
+		t.Error("unexpected match after reload")
+	}
+
+	// Invalid content keeps the previous rules.
+	common.Must(os.WriteFile(file, []byte("unknown:example.com\n"), 0o644))
+	if _, err := matcher.Update(); err == nil {
+		t.Error("expected error for invalid rule-set")
+	}
+	if !cond.Apply(new) {
+		t.Error("expected previous rules after failed reload")
+	}
+}
+
+func TestRuleSetRelease(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "list.txt")
+	common.Must(os.WriteFile(file, []byte("example.com\n"), 0o644))
+
+	r := new(Router)
+	common.Must(r.Init(context.Background(), &Config{
+		Rule: []*RoutingRule{
+			{
+				TargetTag: &RoutingRule_Tag{Tag: "block"},
+				RuleSet:   []*RuleSetFile{{Path: file}},
+			},
+		},
+	}, nil, nil, nil))
+
+	matcher, err := NewRuleSetMatcher(&RuleSetFile{Path: file})
+	common.Must(err)
+	if shared, err := NewRuleSetMatcher(&RuleSetFile{Path: file}); err != nil || shared != matcher {
+		t.Error("expected the matcher to be shared")
+	}
+	matcher.Close()
+
+	// The router and the matcher above still use the file.
+	common.Must(r.Close())
+	if shared, err := NewRuleSetMatcher(&RuleSetFile{Path: file}); err != nil || shared != matcher {
+		t.Error("expected the matcher to be kept while in use")
+	}
+	matcher.Close()
+	matcher.Close()
+
+	released, err := NewRuleSetMatcher(&RuleSetFile{Path: file})
+	common.Must(err)
+	defer released.Close()
+	if released == matcher {
+		t.Error("expected the matcher to be released by all users")
+	}
+}
@@ -5,6 +5,7 @@ import "sync/atomic"
 // Counter is an implementation of stats.Counter.
 type Counter struct {
 	value int64
+	gauge bool
 }
 
 // Value implements stats.Counter.
@@ -12,8 +13,11 @@ func (c *Counter) Value() int64 {
 	return atomic.LoadInt64(&c.value)
 }
 
-// Set implements stats.Counter.
+// Set implements stats.Counter. The value of a gauge is left unchanged.
 func (c *Counter) Set(newValue int64) int64 {
+	if c.gauge {
+		return c.Value()
+	}
 	return atomic.SwapInt64(&c.value, newValue)
 }
 
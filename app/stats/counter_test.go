@@ -29,3 +29,22 @@ func TestStatsCounter(t *testing.T) {
 		t.Fatal("unexpected Value() return: ", v, ", wanted ", 0)
 	}
 }
+
+func TestStatsGauge(t *testing.T) {
+	raw, err := common.CreateObject(context.Background(), &Config{})
+	common.Must(err)
+
+	m := raw.(stats.Manager)
+	g, err := stats.GetOrRegisterGauge(m, "test.gauge")
+	common.Must(err)
+
+	g.Add(2)
+	if v := g.Set(0); v != 2 {
+		t.Fatal("unexpected Set(0) return: ", v, ", wanted ", 2)
+	}
+
+	g.Add(-2)
+	if v := g.Value(); v != 0 {
+		t.Fatal("unexpected Value() return: ", v, ", wanted ", 0)
+	}
+}
@@ -48,6 +48,20 @@ func (m *Manager) RegisterCounter(name string) (stats.Counter, error) {
 	return c, nil
 }
 
+// RegisterGauge implements stats.GaugeManager.
+func (m *Manager) RegisterGauge(name string) (stats.Counter, error) {
+	m.access.Lock()
+	defer m.access.Unlock()
+
+	if _, found := m.counters[name]; found {
+		return nil, newError("Counter ", name, " already registered.")
+	}
+	newError("create new gauge ", name).AtDebug().WriteToLog()
+	c := &Counter{gauge: true}
+	m.counters[name] = c
+	return c, nil
+}
+
 // UnregisterCounter implements stats.Manager.
 func (m *Manager) UnregisterCounter(name string) error {
 	m.access.Lock()
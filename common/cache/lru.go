@@ -11,6 +11,10 @@ type Lru interface {
 	GetKeyFromValue(value interface{}) (key interface{}, ok bool)
 	PeekKeyFromValue(value interface{}) (key interface{}, ok bool) // Peek means check but NOT bring to top
 	Put(key, value interface{})
+	// Range calls f for each entry from the most recently used, until f returns false. f must not use the cache.
+	Range(f func(key, value interface{}) bool)
+	// Clear removes all entries and returns the number of them.
+	Clear() int
 }
 
 type lru struct {
@@ -87,3 +91,27 @@ func (l *lru) Put(key, value interface{}) {
 	}
 	l.mu.Unlock()
 }
+
+func (l *lru) Range(f func(key, value interface{}) bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for element := l.doubleLinkedlist.Front(); element != nil; element = element.Next() {
+		e := element.Value.(*lruElement)
+		if !f(e.key, e.value) {
+			return
+		}
+	}
+}
+
+func (l *lru) Clear() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	n := l.doubleLinkedlist.Len()
+	for element := l.doubleLinkedlist.Front(); element != nil; element = element.Next() {
+		e := element.Value.(*lruElement)
+		l.keyToElement.Delete(e.key)
+		l.valueToElement.Delete(e.value)
+	}
+	l.doubleLinkedlist.Init()
+	return n
+}
@@ -84,3 +84,30 @@ func TestPeekKeyFromValue(t *testing.T) {
 		t.Error("should get 2", v)
 	}
 }
+
+func TestLruRangeAndClear(t *testing.T) {
+	lru := NewLru(3)
+	lru.Put(1, 1)
+	lru.Put(2, 2)
+	lru.Put(3, 3)
+	lru.Get(1)
+
+	var keys []interface{}
+	lru.Range(func(key, value interface{}) bool {
+		keys = append(keys, key)
+		return true
+	})
+	if len(keys) != 3 || keys[0] != 1 || keys[1] != 3 || keys[2] != 2 {
+		t.Error("should range from the most recently used", keys)
+	}
+
+	if n := lru.Clear(); n != 3 {
+		t.Error("should clear 3 entries", n)
+	}
+	if _, ok := lru.Get(1); ok {
+		t.Error("should get nil after clear")
+	}
+	if _, ok := lru.PeekKeyFromValue(2); ok {
+		t.Error("should get nil after clear")
+	}
+}
@@ -3,6 +3,7 @@ package log
 import (
 	"context"
 	"strings"
+	"time"
 
 	"github.com/xtls/xray-core/common/serial"
 )
@@ -18,6 +19,8 @@ type AccessStatus string
 const (
 	AccessAccepted = AccessStatus("accepted")
 	AccessRejected = AccessStatus("rejected")
+	// AccessClosed is the status of a message recorded when an accepted connection ends.
+	AccessClosed = AccessStatus("closed")
 )
 
 type AccessMessage struct {
@@ -27,6 +30,18 @@ type AccessMessage struct {
 	Reason interface{}
 	Email  string
 	Detour string
+
+	// The following fields are only filled in by the dispatcher, and are used in structured access logs.
+	InboundTag  string
+	OutboundTag string
+	RuleTag     string
+	// Protocol and Domain are the sniffed protocol and the target domain of the connection.
+	Protocol string
+	Domain   string
+	// Duration and byte counts of the connection, only set when Status is AccessClosed.
+	Duration time.Duration
+	Uplink   int64
+	Downlink int64
 }
 
 func (m *AccessMessage) String() string {
@@ -3,7 +3,9 @@ package log
 import (
 	"io"
 	"log"
+	"math"
 	"os"
+	"sync"
 	"time"
 
 	"gopkg.in/natefinch/lumberjack.v2"
@@ -131,17 +133,88 @@ func (w *consoleLogWriter) Close() error {
 }
 
 type fileLogWriter struct {
-	logCloser io.Closer
-	logger    *log.Logger
+	file    *lumberjack.Logger
+	logger  *log.Logger
+	rotator *timedRotator
 }
 
 func (w *fileLogWriter) Write(s string) error {
+	if w.rotator != nil && w.rotator.due(time.Now()) {
+		// Rotate renames the current file and reopens the path under the lock of the file,
+		// so no message is lost or split between files.
+		if err := w.file.Rotate(); err != nil {
+			return err
+		}
+	}
 	w.logger.Print(s)
 	return nil
 }
 
 func (w *fileLogWriter) Close() error {
-	return w.logCloser.Close()
+	return w.file.Close()
+}
+
+// timedRotator decides when a log file is due for rotation, at fixed intervals aligned to the local time.
+type timedRotator struct {
+	interval time.Duration
+
+	access sync.Mutex
+	next   time.Time
+}
+
+func newTimedRotator(interval time.Duration, last time.Time) *timedRotator {
+	r := &timedRotator{
+		interval: interval,
+	}
+	r.next = r.nextAfter(last)
+	return r
+}
+
+// nextAfter returns the first multiple of interval after t, in the local time of t.
+func (r *timedRotator) nextAfter(t time.Time) time.Time {
+	_, offset := t.Zone()
+	shift := time.Duration(offset) * time.Second
+	return t.Add(shift).Truncate(r.interval).Add(r.interval).Add(-shift)
+}
+
+func (r *timedRotator) due(now time.Time) bool {
+	r.access.Lock()
+	defer r.access.Unlock()
+
+	if now.Before(r.next) {
+		return false
+	}
+	r.next = r.nextAfter(now)
+	return true
+}
+
+// FileRotation contains settings for rotating log files.
+type FileRotation struct {
+	// Maximum size of a log file in megabytes before it is rotated. 0 for no size based rotation.
+	MaxSize int
+	// Interval of time based rotation, aligned to the local time. 0 for no time based rotation.
+	Interval time.Duration
+	// Maximum number of rotated files to keep. 0 to keep all of them.
+	MaxBackups int
+	// Maximum number of days to keep rotated files. 0 to keep them regardless of age.
+	MaxAge int
+	// Whether or not to compress rotated files with gzip.
+	Compress bool
+}
+
+// DefaultFileRotation is the rotation of log files created by CreateFileLogWriter.
+var DefaultFileRotation = FileRotation{
+	MaxSize:    500,
+	MaxBackups: 7,
+	MaxAge:     7,
+	Compress:   true,
+}
+
+// FileLogOptions contains options for creating file log writers.
+type FileLogOptions struct {
+	// Raw disables prefixing messages with the current time. It is for messages that carry their own timestamps.
+	Raw      bool
+	Rotation FileRotation
 }
 
 // CreateStdoutLogWriter returns a LogWriterCreator that creates LogWriter for stdout.
@@ -153,6 +226,16 @@ func CreateStdoutLogWriter() WriterCreator {
 	}
 }
 
+// CreateStdoutRawLogWriter returns a LogWriterCreator that creates LogWriter for stdout, without prefixing
+// messages with the current time. It is for messages that carry their own timestamps.
+func CreateStdoutRawLogWriter() WriterCreator {
+	return func() Writer {
+		return &consoleLogWriter{
+			logger: log.New(os.Stdout, "", 0),
+		}
+	}
+}
+
 // CreateStderrLogWriter returns a LogWriterCreator that creates LogWriter for stderr.
 func CreateStderrLogWriter() WriterCreator {
 	return func() Writer {
@@ -164,22 +247,49 @@ func CreateStderrLogWriter() WriterCreator {
 
 // CreateFileLogWriter returns a LogWriterCreator that creates LogWriter for the given file.
 func CreateFileLogWriter(path string) (WriterCreator, error) {
+	return CreateFileLogWriterWithOptions(path, FileLogOptions{
+		Rotation: DefaultFileRotation,
+	})
+}
+
+// CreateFileLogWriterWithOptions returns a LogWriterCreator that creates LogWriter for the given file with the
+// given options.
+func CreateFileLogWriterWithOptions(path string, options FileLogOptions) (WriterCreator, error) {
 	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0o600)
 	if err != nil {
 		return nil, err
 	}
+	info, err := file.Stat()
 	file.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	flag := log.Ldate | log.Ltime
+	if options.Raw {
+		flag = 0
+	}
+	maxSize := options.Rotation.MaxSize
+	if maxSize <= 0 {
+		maxSize = math.MaxInt32
+	}
+	// Writers are recreated after being idle, so the rotation schedule is shared among them.
+	var rotator *timedRotator
+	if options.Rotation.Interval > 0 {
+		rotator = newTimedRotator(options.Rotation.Interval, info.ModTime())
+	}
 	return func() Writer {
-		logger := &lumberjack.Logger{
+		file := &lumberjack.Logger{
 			Filename:   path,
-			MaxSize:    500, // megabytes
-			MaxBackups: 7,
-			MaxAge:     7,    //days
-			Compress:   true, // disabled by default
+			MaxSize:    maxSize, // megabytes
+			MaxBackups: options.Rotation.MaxBackups,
+			MaxAge:     options.Rotation.MaxAge, // days
+			Compress:   options.Rotation.Compress,
 		}
 		return &fileLogWriter{
-			logCloser: logger,
-			logger:    log.New(logger, "", log.Ldate|log.Ltime),
+			file:    file,
+			logger:  log.New(file, "", flag),
+			rotator: rotator,
 		}
 	}, nil
 }
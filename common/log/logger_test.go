@@ -2,6 +2,7 @@ package log_test
 
 import (
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -36,3 +37,36 @@ func TestFileLogger(t *testing.T) {
 		t.Fatal("Expect log text contains 'Test Log', but actually: ", string(b))
 	}
 }
+
+func TestFileLoggerRotation(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "access.log")
+
+	creator, err := CreateFileLogWriterWithOptions(path, FileLogOptions{
+		Raw: true,
+		Rotation: FileRotation{
+			MaxSize:    1,
+			MaxBackups: 1,
+		},
+	})
+	common.Must(err)
+
+	w := creator()
+	line := strings.Repeat("x", 1023) + "\n"
+	for i := 0; i < 1500; i++ {
+		common.Must(w.Write(line))
+	}
+	common.Must(w.Close())
+
+	files, err := os.ReadDir(dir)
+	common.Must(err)
+	if len(files) != 2 {
+		t.Fatal("expected the log file and a backup, but got ", len(files), " files")
+	}
+
+	b, err := os.ReadFile(path)
+	common.Must(err)
+	if !strings.HasPrefix(string(b), "xxx") {
+		t.Fatal("expected raw messages without timestamp, but actually: ", string(b[:32]))
+	}
+}
@@ -46,6 +46,9 @@ type Inbound struct {
 	Name string
 	// User is the user that authenticates for the inbound. May be nil if the protocol allows anonymous traffic.
 	User *protocol.MemoryUser
+	// ClientCertSubject is the subject of the verified TLS client certificate of the connection. It is the user email
+	// unless the proxy authenticates a user with an email. May be empty.
+	ClientCertSubject string
 	// Conn is actually internet.Connection. May be nil.
 	Conn net.Conn
 	// Timer of the inbound buf copier. May be nil.
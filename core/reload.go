@@ -0,0 +1,331 @@
+package core
+
+import (
+	"github.com/xtls/xray-core/common/serial"
+	"github.com/xtls/xray-core/features"
+	"github.com/xtls/xray-core/features/inbound"
+	"github.com/xtls/xray-core/features/outbound"
+	"google.golang.org/protobuf/proto"
+)
+
+// SetConfigLoader sets the function used by ReloadConfig to obtain a fresh config, usually by re-reading the config files.
+func (s *Instance) SetConfigLoader(loader func() (*Config, error)) {
+	s.access.Lock()
+	defer s.access.Unlock()
+
+	s.configLoader = loader
+}
+
+// ReloadConfig loads a new config through the loader set by SetConfigLoader, and applies it with Reload.
+func (s *Instance) ReloadConfig() error {
+	s.access.Lock()
+	loader := s.configLoader
+	s.access.Unlock()
+
+	if loader == nil {
+		return newError("no config loader is set for this instance")
+	}
+	config, err := loader()
+	if err != nil {
+		return newError("failed to load config").Base(err)
+	}
+	return s.Reload(config)
+}
+
+// Reload diffs the given config against the running one and applies the changes in place.
+// Inbound and outbound handlers are compared by tag, and only the added, removed or modified ones are touched,
+// so that sessions on other handlers stay alive. App settings are handed to the corresponding feature if it is
+// features.Reloadable, otherwise the change is reported and requires a restart.
+func (s *Instance) Reload(config *Config) error {
+	s.access.Lock()
+	defer s.access.Unlock()
+
+	if !s.running {
+		return newError("instance is not running")
+	}
+
+	// Each section records the settings it has applied, so that s.config matches the running instance and the next
+	// reload diffs against it, even if a later section fails.
+	s.config = proto.Clone(s.config).(*Config)
+	if err := s.reloadApps(config.App); err != nil {
+		return err
+	}
+	if err := s.reloadOutbounds(config.Outbound); err != nil {
+		return err
+	}
+	if err := s.reloadInbounds(config.Inbound); err != nil {
+		return err
+	}
+
+	s.config = config
+	newError("config reloaded").AtWarning().WriteToLog()
+	return nil
+}
+
+func typedMessageEqual(a, b *serial.TypedMessage) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	if a.Type != b.Type {
+		return false
+	}
+	ma, errA := a.GetInstance()
+	mb, errB := b.GetInstance()
+	if errA != nil || errB != nil {
+		return proto.Equal(a, b)
+	}
+	return proto.Equal(ma, mb)
+}
+
+func (s *Instance) reloadApps(apps []*serial.TypedMessage) error {
+	oldApps := make(map[string]*serial.TypedMessage, len(s.config.GetApp()))
+	applied := make(map[string]*serial.TypedMessage, len(s.config.GetApp()))
+	for _, app := range s.config.GetApp() {
+		oldApps[app.Type] = app
+		applied[app.Type] = app
+	}
+	defer func() {
+		recorded := make([]*serial.TypedMessage, 0, len(applied))
+		for _, app := range apps {
+			if a, found := applied[app.Type]; found {
+				recorded = append(recorded, a)
+				delete(applied, app.Type)
+			}
+		}
+		for _, a := range applied {
+			recorded = append(recorded, a)
+		}
+		s.config.App = recorded
+	}()
+
+	for _, app := range apps {
+		old, found := oldApps[app.Type]
+		delete(oldApps, app.Type)
+		if found && typedMessageEqual(old, app) {
+			continue
+		}
+		// Apps which are not reloaded are recorded anyway, so that the restart they require is reported once.
+		applied[app.Type] = app
+		if !found {
+			newError("app ", app.Type, " is added, restart is required to enable it").AtWarning().WriteToLog()
+			continue
+		}
+		feature, found := s.appFeatures[app.Type]
+		if !found {
+			newError("app ", app.Type, " is changed, restart is required to apply it").AtWarning().WriteToLog()
+			continue
+		}
+		reloadable, ok := feature.(features.Reloadable)
+		if !ok {
+			newError("app ", app.Type, " does not support reloading, restart is required to apply it").AtWarning().WriteToLog()
+			continue
+		}
+		settings, err := app.GetInstance()
+		if err != nil {
+			applied[app.Type] = old
+			return newError("failed to parse settings of app ", app.Type).Base(err)
+		}
+		if err := reloadable.Reload(settings); err != nil {
+			applied[app.Type] = old
+			return newError("failed to reload app ", app.Type).Base(err)
+		}
+		newError("app ", app.Type, " reloaded").AtInfo().WriteToLog()
+	}
+
+	for appType := range oldApps {
+		delete(applied, appType)
+		newError("app ", appType, " is removed, restart is required to disable it").AtWarning().WriteToLog()
+	}
+
+	return nil
+}
+
+func (s *Instance) reloadInbounds(configs []*InboundHandlerConfig) error {
+	ihm := s.GetFeature(inbound.ManagerType()).(inbound.Manager)
+
+	oldConfigs := make(map[string]*InboundHandlerConfig, len(s.config.GetInbound()))
+	var oldUntagged []*InboundHandlerConfig
+	for _, c := range s.config.GetInbound() {
+		if c.Tag == "" {
+			oldUntagged = append(oldUntagged, c)
+			continue
+		}
+		oldConfigs[c.Tag] = c
+	}
+
+	var newUntagged []*InboundHandlerConfig
+	var added []*InboundHandlerConfig
+	// applied holds the tags of the handlers running with their new config.
+	applied := make(map[string]bool, len(configs))
+	for _, c := range configs {
+		if c.Tag == "" {
+			newUntagged = append(newUntagged, c)
+			continue
+		}
+		old, found := oldConfigs[c.Tag]
+		delete(oldConfigs, c.Tag)
+		if found && inboundConfigEqual(old, c) {
+			applied[c.Tag] = true
+			continue
+		}
+		added = append(added, c)
+	}
+
+	if !inboundConfigsEqual(oldUntagged, newUntagged) {
+		return newError("inbounds without tag can not be reloaded, tag them or restart instead")
+	}
+
+	// Removed handlers are gone even if adding fails, and the ones not added yet are not running at all.
+	defer func() {
+		recorded := make([]*InboundHandlerConfig, 0, len(configs))
+		for _, c := range configs {
+			if c.Tag == "" || applied[c.Tag] {
+				recorded = append(recorded, c)
+			}
+		}
+		s.config.Inbound = recorded
+	}()
+
+	for tag := range oldConfigs {
+		if err := ihm.RemoveHandler(s.ctx, tag); err != nil {
+			newError("failed to remove inbound ", tag).Base(err).AtWarning().WriteToLog()
+			continue
+		}
+		newError("inbound ", tag, " removed").AtInfo().WriteToLog()
+	}
+
+	for _, c := range added {
+		if err := ihm.RemoveHandler(s.ctx, c.Tag); err == nil {
+			newError("inbound ", c.Tag, " removed for reloading").AtInfo().WriteToLog()
+		}
+	}
+
+	for _, c := range added {
+		if err := AddInboundHandler(s, c); err != nil {
+			return newError("failed to add inbound ", c.Tag).Base(err)
+		}
+		applied[c.Tag] = true
+		newError("inbound ", c.Tag, " added").AtInfo().WriteToLog()
+	}
+
+	return nil
+}
+
+func (s *Instance) reloadOutbounds(configs []*OutboundHandlerConfig) error {
+	ohm := s.GetFeature(outbound.ManagerType()).(outbound.Manager)
+
+	oldConfigs := make(map[string]*OutboundHandlerConfig, len(s.config.GetOutbound()))
+	var oldUntagged []*OutboundHandlerConfig
+	for _, c := range s.config.GetOutbound() {
+		if c.Tag == "" {
+			oldUntagged = append(oldUntagged, c)
+			continue
+		}
+		oldConfigs[c.Tag] = c
+	}
+
+	// The first outbound is the default one. As the manager only assigns the default handler when there is none,
+	// a change of the first outbound requires the previous default to be removed before the new one is added.
+	var oldDefault, newDefault string
+	if len(s.config.GetOutbound()) > 0 {
+		oldDefault = s.config.GetOutbound()[0].Tag
+	}
+	if len(configs) > 0 {
+		newDefault = configs[0].Tag
+	}
+	defaultChanged := oldDefault != newDefault
+
+	var newUntagged []*OutboundHandlerConfig
+	var added []*OutboundHandlerConfig
+	// applied holds the tags of the handlers running with their new config.
+	applied := make(map[string]bool, len(configs))
+	for _, c := range configs {
+		if c.Tag == "" {
+			newUntagged = append(newUntagged, c)
+			continue
+		}
+		old, found := oldConfigs[c.Tag]
+		delete(oldConfigs, c.Tag)
+		if found && outboundConfigEqual(old, c) && !(defaultChanged && (c.Tag == oldDefault || c.Tag == newDefault)) {
+			applied[c.Tag] = true
+			continue
+		}
+		added = append(added, c)
+	}
+
+	if !outboundConfigsEqual(oldUntagged, newUntagged) {
+		return newError("outbounds without tag can not be reloaded, tag them or restart instead")
+	}
+
+	// Removed handlers are gone even if adding fails, and the ones not added yet are not running at all.
+	defer func() {
+		recorded := make([]*OutboundHandlerConfig, 0, len(configs))
+		for _, c := range configs {
+			if c.Tag == "" || applied[c.Tag] {
+				recorded = append(recorded, c)
+			}
+		}
+		s.config.Outbound = recorded
+	}()
+
+	for tag := range oldConfigs {
+		if err := ohm.RemoveHandler(s.ctx, tag); err != nil {
+			newError("failed to remove outbound ", tag).Base(err).AtWarning().WriteToLog()
+			continue
+		}
+		newError("outbound ", tag, " removed").AtInfo().WriteToLog()
+	}
+
+	// Outbound handlers are not closed on removal, so that sessions using them finish normally.
+	for _, c := range added {
+		if err := ohm.RemoveHandler(s.ctx, c.Tag); err == nil {
+			newError("outbound ", c.Tag, " removed for reloading").AtInfo().WriteToLog()
+		}
+	}
+
+	for _, c := range added {
+		if err := AddOutboundHandler(s, c); err != nil {
+			return newError("failed to add outbound ", c.Tag).Base(err)
+		}
+		applied[c.Tag] = true
+		newError("outbound ", c.Tag, " added").AtInfo().WriteToLog()
+	}
+
+	return nil
+}
+
+func inboundConfigEqual(a, b *InboundHandlerConfig) bool {
+	return a.Tag == b.Tag &&
+		typedMessageEqual(a.ReceiverSettings, b.ReceiverSettings) &&
+		typedMessageEqual(a.ProxySettings, b.ProxySettings)
+}
+
+func inboundConfigsEqual(a, b []*InboundHandlerConfig) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !inboundConfigEqual(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func outboundConfigEqual(a, b *OutboundHandlerConfig) bool {
+	return a.Tag == b.Tag &&
+		typedMessageEqual(a.SenderSettings, b.SenderSettings) &&
+		typedMessageEqual(a.ProxySettings, b.ProxySettings)
+}
+
+func outboundConfigsEqual(a, b []*OutboundHandlerConfig) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !outboundConfigEqual(a[i], b[i]) {
+			return false
+		}
+	}
+	return true
+}
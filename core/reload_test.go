@@ -0,0 +1,137 @@
+package core_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/xtls/xray-core/app/dispatcher"
+	"github.com/xtls/xray-core/app/proxyman"
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/serial"
+	. "github.com/xtls/xray-core/core"
+	"github.com/xtls/xray-core/features/inbound"
+	"github.com/xtls/xray-core/features/outbound"
+	_ "github.com/xtls/xray-core/main/distro/all"
+	"github.com/xtls/xray-core/proxy/dokodemo"
+	"github.com/xtls/xray-core/proxy/freedom"
+	"github.com/xtls/xray-core/testing/servers/tcp"
+)
+
+func dokodemoInbound(tag string, port net.Port) *InboundHandlerConfig {
+	return &InboundHandlerConfig{
+		Tag: tag,
+		ReceiverSettings: serial.ToTypedMessage(&proxyman.ReceiverConfig{
+			PortList: &net.PortList{Range: []*net.PortRange{net.SinglePortRange(port)}},
+			Listen:   net.NewIPOrDomain(net.LocalHostIP),
+		}),
+		ProxySettings: serial.ToTypedMessage(&dokodemo.Config{
+			Address:     net.NewIPOrDomain(net.LocalHostIP),
+			Port:        uint32(port),
+			NetworkList: &net.NetworkList{Network: []net.Network{net.Network_TCP}},
+		}),
+	}
+}
+
+func TestInstanceReload(t *testing.T) {
+	apps := []*serial.TypedMessage{
+		serial.ToTypedMessage(&dispatcher.Config{}),
+		serial.ToTypedMessage(&proxyman.InboundConfig{}),
+		serial.ToTypedMessage(&proxyman.OutboundConfig{}),
+	}
+	portA := tcp.PickPort()
+	portB := tcp.PickPort()
+	portC := tcp.PickPort()
+
+	server, err := New(&Config{
+		App:     apps,
+		Inbound: []*InboundHandlerConfig{dokodemoInbound("a", portA), dokodemoInbound("b", portB)},
+		Outbound: []*OutboundHandlerConfig{
+			{Tag: "direct", ProxySettings: serial.ToTypedMessage(&freedom.Config{})},
+		},
+	})
+	common.Must(err)
+	common.Must(server.Start())
+	defer server.Close()
+
+	ihm := server.GetFeature(inbound.ManagerType()).(inbound.Manager)
+	ohm := server.GetFeature(outbound.ManagerType()).(outbound.Manager)
+	handlerA, err := ihm.GetHandler(context.Background(), "a")
+	common.Must(err)
+	direct := ohm.GetHandler("direct")
+
+	common.Must(server.Reload(&Config{
+		App:     apps,
+		Inbound: []*InboundHandlerConfig{dokodemoInbound("a", portA), dokodemoInbound("c", portC)},
+		Outbound: []*OutboundHandlerConfig{
+			{Tag: "block", ProxySettings: serial.ToTypedMessage(&freedom.Config{DomainStrategy: freedom.Config_USE_IP})},
+			{Tag: "direct", ProxySettings: serial.ToTypedMessage(&freedom.Config{})},
+		},
+	}))
+
+	if h, err := ihm.GetHandler(context.Background(), "a"); err != nil || h != handlerA {
+		t.Error("unchanged inbound a should be kept")
+	}
+	if _, err := ihm.GetHandler(context.Background(), "b"); err == nil {
+		t.Error("inbound b should be removed")
+	}
+	if _, err := ihm.GetHandler(context.Background(), "c"); err != nil {
+		t.Error("inbound c should be added: ", err)
+	}
+	if h := ohm.GetHandler("direct"); h == nil || h == direct {
+		t.Error("outbound direct should be recreated as it is no longer the default one")
+	}
+	if h := ohm.GetDefaultHandler(); h == nil || h.Tag() != "block" {
+		t.Error("default outbound should be block")
+	}
+
+	if err := server.Reload(&Config{
+		App:     apps,
+		Inbound: []*InboundHandlerConfig{dokodemoInbound("", portA)},
+	}); err == nil {
+		t.Error("expected error when untagged inbounds are changed")
+	}
+}
+
+func TestInstanceReloadFailure(t *testing.T) {
+	apps := []*serial.TypedMessage{
+		serial.ToTypedMessage(&dispatcher.Config{}),
+		serial.ToTypedMessage(&proxyman.InboundConfig{}),
+		serial.ToTypedMessage(&proxyman.OutboundConfig{}),
+	}
+	portA := tcp.PickPort()
+	portB := tcp.PickPort()
+	config := &Config{
+		App:     apps,
+		Inbound: []*InboundHandlerConfig{dokodemoInbound("a", portA), dokodemoInbound("b", portB)},
+		Outbound: []*OutboundHandlerConfig{
+			{Tag: "direct", ProxySettings: serial.ToTypedMessage(&freedom.Config{})},
+		},
+	}
+
+	server, err := New(config)
+	common.Must(err)
+	common.Must(server.Start())
+	defer server.Close()
+
+	ihm := server.GetFeature(inbound.ManagerType()).(inbound.Manager)
+
+	broken := dokodemoInbound("c", tcp.PickPort())
+	broken.ProxySettings = &serial.TypedMessage{Type: "xray.proxy.unknown.Config"}
+	if err := server.Reload(&Config{
+		App:      apps,
+		Inbound:  []*InboundHandlerConfig{dokodemoInbound("b", portB), broken},
+		Outbound: config.Outbound,
+	}); err == nil {
+		t.Fatal("expected error when an inbound can not be created")
+	}
+	if _, err := ihm.GetHandler(context.Background(), "a"); err == nil {
+		t.Error("inbound a should be removed")
+	}
+
+	// The failed reload has removed inbound a, so reloading the original config adds it back.
+	common.Must(server.Reload(config))
+	if _, err := ihm.GetHandler(context.Background(), "a"); err != nil {
+		t.Error("inbound a should be added back: ", err)
+	}
+}
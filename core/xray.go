@@ -93,6 +93,11 @@ type Instance struct {
 	featureResolutions []resolution
 	running            bool
 
+	// config is the config currently applied to the instance, used for diffing on Reload.
+	config       *Config
+	appFeatures  map[string]features.Feature
+	configLoader func() (*Config, error)
+
 	ctx context.Context
 }
 
@@ -191,6 +196,8 @@ func initInstanceWithConfig(config *Config, server *Instance) (bool, error) {
 		return true, err
 	}
 
+	server.config = config
+	server.appFeatures = make(map[string]features.Feature)
 	for _, appSettings := range config.App {
 		settings, err := appSettings.GetInstance()
 		if err != nil {
@@ -204,6 +211,7 @@ func initInstanceWithConfig(config *Config, server *Instance) (bool, error) {
 			if err := server.AddFeature(feature); err != nil {
 				return true, err
 			}
+			server.appFeatures[appSettings.Type] = feature
 		}
 	}
 
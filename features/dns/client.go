@@ -1,10 +1,13 @@
 package dns
 
 import (
+	"context"
+
 	"github.com/xtls/xray-core/common/errors"
 	"github.com/xtls/xray-core/common/net"
 	"github.com/xtls/xray-core/common/serial"
 	"github.com/xtls/xray-core/features"
+	"golang.org/x/net/dns/dnsmessage"
 )
 
 // IPOption is an object for IP query options.
@@ -24,6 +27,22 @@ type Client interface {
 	LookupIP(domain string, option IPOption) ([]net.IP, error)
 }
 
+// ContextClient is a Client that relates queries to the request in the context, such as its source.
+//
+// xray:api:beta
+type ContextClient interface {
+	// LookupIPWithContext is the same as LookupIP, with the context of the request that made the query.
+	LookupIPWithContext(ctx context.Context, domain string, option IPOption) ([]net.IP, error)
+}
+
+// RawClient is a Client that resolves queries of any type, such as MX, TXT, SRV or HTTPS.
+//
+// xray:api:beta
+type RawClient interface {
+	// LookupRaw returns the response to the query of the given type for the domain, with the context of the request that made the query.
+	LookupRaw(ctx context.Context, domain string, qType dnsmessage.Type) (*dnsmessage.Message, error)
+}
+
 type HostsLookup interface {
 	LookupHosts(domain string) *net.Address
 }
@@ -21,3 +21,19 @@ type FakeDNSEngineRev0 interface {
 	IsIPInIPPool(ip net.Address) bool
 	GetFakeIPForDomain3(domain string, IPv4, IPv6 bool) []net.Address
 }
+
+// FakeDNSMapping is a mapping between a domain and a fake IP.
+type FakeDNSMapping struct {
+	Domain string
+	IP     net.Address
+}
+
+// FakeDNSMappingManager is implemented by FakeDNSEngines that allow inspecting and flushing their mappings.
+//
+// xray:api:beta
+type FakeDNSMappingManager interface {
+	// FakeDNSMappings returns all mappings, the most recently used first.
+	FakeDNSMappings() []FakeDNSMapping
+	// FlushFakeDNS removes all mappings, and returns the number of them.
+	FlushFakeDNS() int
+}
@@ -0,0 +1,24 @@
+package dns
+
+import (
+	"time"
+)
+
+// ServerHealth is the health of a name server.
+type ServerHealth struct {
+	Server string
+	// Healthy is false if the server failed recently, and is skipped until its backoff ends.
+	Healthy bool
+	// Failures is the number of consecutive failures.
+	Failures    int
+	SuccessRate float64
+	Latency     time.Duration
+}
+
+// HealthReporter is implemented by Clients that track the health of their name servers.
+//
+// xray:api:beta
+type HealthReporter interface {
+	// ServerHealth returns the health of all name servers.
+	ServerHealth() []ServerHealth
+}
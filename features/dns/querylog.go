@@ -0,0 +1,30 @@
+package dns
+
+import (
+	"time"
+
+	"github.com/xtls/xray-core/common/net"
+)
+
+// QueryRecord is a query answered by a name server or the hosts.
+type QueryRecord struct {
+	Time time.Time
+	// Client is the source of the request that made the query, if known.
+	Client net.Destination
+	Domain string
+	// Type is the query type, such as A, AAAA or MX. IP queries of both families are "A+AAAA".
+	Type     string
+	Server   string
+	Elapsed  time.Duration
+	CacheHit bool
+	Answers  []string
+	Error    error
+}
+
+// QueryLogger is implemented by Clients that keep recent queries.
+//
+// xray:api:beta
+type QueryLogger interface {
+	// RecentQueries returns recent queries, the most recent first.
+	RecentQueries() []*QueryRecord
+}
@@ -15,3 +15,9 @@ type Feature interface {
 func PrintDeprecatedFeatureWarning(feature string) {
 	newError("You are using a deprecated feature: " + feature + ". Please update your config file with latest configuration format, or update your client software.").WriteToLog()
 }
+
+// Reloadable is the interface for features that are able to apply a changed config in place, without being recreated.
+type Reloadable interface {
+	// Reload applies the given config, which is of the same type as the one the feature was created from.
+	Reload(config interface{}) error
+}
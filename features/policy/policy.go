@@ -2,11 +2,15 @@ package policy
 
 import (
 	"context"
+	"math"
 	"runtime"
 	"time"
 
+	"github.com/xtls/xray-core/common/buf"
+	"github.com/xtls/xray-core/common/net"
 	"github.com/xtls/xray-core/common/platform"
 	"github.com/xtls/xray-core/features"
+	"golang.org/x/time/rate"
 )
 
 // Timeout contains limits for connection timeout.
@@ -35,6 +39,22 @@ type Buffer struct {
 	PerConnection int32
 }
 
+// RateLimit contains settings for bandwidth limit.
+type RateLimit struct {
+	// Uplink speed limit, in bytes per second. 0 for unlimited.
+	Uplink uint64
+	// Downlink speed limit, in bytes per second. 0 for unlimited.
+	Downlink uint64
+}
+
+// UserLimit contains limits on the concurrent usage of a user, counted across all inbounds.
+type UserLimit struct {
+	// Maximum number of concurrent connections. 0 for unlimited.
+	MaxConnections uint32
+	// Maximum number of distinct source IPs with concurrent connections. 0 for unlimited.
+	MaxSourceIPs uint32
+}
+
 // SystemStats contains stat policy settings on system level.
 type SystemStats struct {
 	// Whether or not to enable stat counter for uplink traffic in inbound handlers.
@@ -55,9 +75,11 @@ type System struct {
 
 // Session is session based settings for controlling Xray requests. It contains various settings (or limits) that may differ for different users in the context.
 type Session struct {
-	Timeouts Timeout // Timeout settings
-	Stats    Stats
-	Buffer   Buffer
+	Timeouts  Timeout // Timeout settings
+	Stats     Stats
+	Buffer    Buffer
+	RateLimit RateLimit
+	UserLimit UserLimit
 }
 
 // Manager is a feature that provides Policy for the given user by its id or level.
@@ -73,6 +95,29 @@ type Manager interface {
 	ForSystem() System
 }
 
+// RateLimiterManager is implemented by Managers that share rate limiters among all connections of a user,
+// and allow changing the limits at runtime.
+type RateLimiterManager interface {
+	// RateLimitersForUser returns the uplink and downlink limiters for a connection of the given user. A nil limiter
+	// means no limit. release must be called once the connection ends, so that the limiters of idle users are removed.
+	RateLimitersForUser(email string, level uint32) (uplink *rate.Limiter, downlink *rate.Limiter, release func())
+	// SetUserRateLimit changes the rate limit of the given user. Speed 0 means unlimited.
+	SetUserRateLimit(email string, uplink, downlink uint64)
+	// SetLevelRateLimit changes the rate limit of the given level. Speed 0 means unlimited.
+	SetLevelRateLimit(level uint32, uplink, downlink uint64)
+}
+
+// ConnectionTracker is implemented by Managers that track online connections of users, to enforce UserLimit.
+type ConnectionTracker interface {
+	// TrackConnection registers a connection of the given user from the source IP. It returns a function to be
+	// called when the connection ends, or an error if the connection exceeds the UserLimit of the user.
+	TrackConnection(email string, level uint32, source net.Address) (func(), error)
+	// OnlineIPs returns the number of connections from each source IP of the given user.
+	OnlineIPs(email string) map[string]uint32
+	// OnlineUsers returns the emails of users having at least one connection.
+	OnlineUsers() []string
+}
+
 // ManagerType returns the type of Manager interface. Can be used to implement common.HasType.
 //
 // xray:api:stable
@@ -145,3 +190,34 @@ func BufferPolicyFromContext(ctx context.Context) Buffer {
 	}
 	return pPolicy.(Buffer)
 }
+
+// NewRateLimiter creates a limiter for the given speed in bytes per second. It returns nil if speed is 0, which means no limit.
+func NewRateLimiter(speed uint64) *rate.Limiter {
+	if speed == 0 {
+		return nil
+	}
+	return rate.NewLimiter(rate.Limit(speed), rateLimiterBurst(speed))
+}
+
+// UpdateRateLimiter changes the speed of the given limiter in place, so that connections using it are affected immediately.
+// Speed 0 removes the limit.
+func UpdateRateLimiter(l *rate.Limiter, speed uint64) {
+	if speed == 0 {
+		l.SetLimit(rate.Inf)
+		return
+	}
+	l.SetBurst(rateLimiterBurst(speed))
+	l.SetLimit(rate.Limit(speed))
+}
+
+func rateLimiterBurst(speed uint64) int {
+	// Allow at least a few full buffers at a time, otherwise writers have to wait for every chunk on low speeds.
+	const minBurst = 4 * buf.Size
+	if speed < minBurst {
+		return minBurst
+	}
+	if speed > math.MaxInt32 {
+		return math.MaxInt32
+	}
+	return int(speed)
+}
@@ -0,0 +1,37 @@
+package quota
+
+//go:generate go run github.com/xtls/xray-core/common/errors/errorgen
+
+import (
+	"github.com/xtls/xray-core/features"
+)
+
+// ErrExceeded is returned when the traffic quota of a user is used up.
+var ErrExceeded = newError("traffic quota exceeded")
+
+// Quota is the traffic budget of a user.
+//
+// xray:api:beta
+type Quota interface {
+	// Consume adds n bytes to the usage, and returns ErrExceeded if the quota is used up.
+	Consume(n int64) error
+	// Exceeded returns whether the quota is used up.
+	Exceeded() bool
+}
+
+// Manager is a feature that tracks traffic quotas of users.
+//
+// xray:api:beta
+type Manager interface {
+	features.Feature
+
+	// ForUser returns the Quota of the given user, or nil if the user has no quota.
+	ForUser(email string) Quota
+}
+
+// ManagerType returns the type of Manager interface. Can be used to implement common.HasType.
+//
+// xray:api:beta
+func ManagerType() interface{} {
+	return (*Manager)(nil)
+}
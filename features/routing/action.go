@@ -0,0 +1,47 @@
+package routing
+
+import (
+	"github.com/xtls/xray-core/common/net"
+)
+
+// RejectType is how a connection is rejected by a routing rule.
+type RejectType byte
+
+const (
+	// RejectNone does not reject the connection.
+	RejectNone RejectType = iota
+	// RejectReset resets TCP connections, and closes others.
+	RejectReset
+	// RejectHTTP403 answers with an HTTP 403 response.
+	RejectHTTP403
+	// RejectDrop drops all traffic silently until the connection ends.
+	RejectDrop
+)
+
+// DomainStrategy is how the destination domain is resolved before the connection is dispatched.
+type DomainStrategy byte
+
+const (
+	// DomainStrategyAsIs passes the domain to the outbound as is.
+	DomainStrategyAsIs DomainStrategy = iota
+	// DomainStrategyUseIP resolves the domain, and keeps it if it fails to resolve.
+	DomainStrategyUseIP
+	DomainStrategyUseIPv4
+	DomainStrategyUseIPv6
+	// DomainStrategyForceIP resolves the domain, and closes the connection if it fails to resolve.
+	DomainStrategyForceIP
+	DomainStrategyForceIPv4
+	DomainStrategyForceIPv6
+)
+
+// Action is what a routing rule does to the matched connection, besides choosing its outbound.
+type Action struct {
+	Reject RejectType
+	// Address rewrites the destination address if not nil.
+	Address net.Address
+	// Port rewrites the destination port if not zero.
+	Port           net.Port
+	DomainStrategy DomainStrategy
+	// Attributes are set on the session content.
+	Attributes map[string]string
+}
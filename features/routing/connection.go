@@ -0,0 +1,34 @@
+package routing
+
+import (
+	"time"
+
+	"github.com/xtls/xray-core/common/net"
+)
+
+// Connection is a snapshot of an active connection dispatched by a Dispatcher.
+type Connection struct {
+	// ID is the session ID of the connection, as shown in logs.
+	ID          uint32
+	InboundTag  string
+	Email       string
+	Source      net.Destination
+	Target      net.Destination
+	OutboundTag string
+	StartTime   time.Time
+	// Uplink and Downlink are the bytes transferred so far.
+	Uplink   int64
+	Downlink int64
+}
+
+// ConnectionManager is implemented by Dispatchers that track their active connections.
+//
+// xray:api:beta
+type ConnectionManager interface {
+	// Connections returns snapshots of all active connections.
+	Connections() []*Connection
+	// CloseConnection closes the connection with the given ID. It returns false if there is no such connection.
+	CloseConnection(id uint32) bool
+	// CloseUserConnections closes all connections of the given user, and returns the number of them.
+	CloseUserConnections(email string) int
+}
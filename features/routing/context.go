@@ -40,4 +40,17 @@ type Context interface {
 
 	// GetSkipDNSResolve returns a flag switch for weather skip dns resolve during route pick.
 	GetSkipDNSResolve() bool
+
+	// GetProcess returns the local process that originated the connection, or nil if it is unknown.
+	GetProcess() *Process
+}
+
+// Process is a local process that originated a connection.
+type Process struct {
+	// PID is the process ID, or 0 if only the owner of the connection is known.
+	PID int
+	// UID is the user ID of the owner of the connection.
+	UID uint32
+	// Path is the path of the executable, or empty if it is unknown.
+	Path string
 }
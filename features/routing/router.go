@@ -30,6 +30,12 @@ type Route interface {
 
 	// GetOutboundTag returns the tag of the outbound the connection was dispatched to.
 	GetOutboundTag() string
+
+	// GetRuleTag returns the tag of the routing rule that matched, or empty if the rule has no tag.
+	GetRuleTag() string
+
+	// GetAction returns the action of the routing rule that matched, or nil if the rule has no action.
+	GetAction() *Action
 }
 
 // RouterType return the type of Router interface. Can be used to implement common.HasType.
@@ -1,5 +1,7 @@
 package session
 
+//go:generate go run github.com/xtls/xray-core/common/errors/errorgen
+
 import (
 	"context"
 
@@ -13,6 +15,9 @@ type Context struct {
 	Inbound  *session.Inbound
 	Outbound *session.Outbound
 	Content  *session.Content
+
+	process         *routing.Process
+	processResolved bool
 }
 
 // GetInboundTag implements routing.Context.
@@ -122,6 +127,24 @@ func (ctx *Context) GetSkipDNSResolve() bool {
 	return ctx.Content.SkipDNSResolve
 }
 
+// GetProcess implements routing.Context. The process is looked up once, when it is first asked for.
+func (ctx *Context) GetProcess() *routing.Process {
+	if ctx.processResolved {
+		return ctx.process
+	}
+	ctx.processResolved = true
+	if ctx.Inbound == nil || !ctx.Inbound.Source.IsValid() || !ctx.Inbound.Source.Address.Family().IsIP() {
+		return nil
+	}
+	process, err := findProcess(ctx.GetNetwork(), ctx.Inbound.Source)
+	if err != nil {
+		newError("failed to find process of ", ctx.Inbound.Source).Base(err).AtDebug().WriteToLog()
+		return nil
+	}
+	ctx.process = process
+	return process
+}
+
 // AsRoutingContext creates a context from context.context with session info.
 func AsRoutingContext(ctx context.Context) routing.Context {
 	return &Context{
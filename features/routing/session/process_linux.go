@@ -0,0 +1,168 @@
+//go:build linux
+
+package session
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/features/routing"
+)
+
+// findProcess finds the local process owning the socket whose local address is the given source.
+func findProcess(network net.Network, source net.Destination) (*routing.Process, error) {
+	var tables []string
+	switch network {
+	case net.Network_TCP:
+		tables = []string{"/proc/net/tcp", "/proc/net/tcp6"}
+	case net.Network_UDP:
+		tables = []string{"/proc/net/udp", "/proc/net/udp6"}
+	default:
+		return nil, newError("unsupported network ", network)
+	}
+
+	start := time.Now()
+	for _, table := range tables {
+		// UDP sockets are usually not bound to a specific address.
+		uid, inode, found, err := findSocket(table, source, network == net.Network_UDP)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			continue
+		}
+		process := &routing.Process{UID: uid}
+		// Sockets of processes of other users may not be visible without privileges.
+		if pid, found := findSocketOwner(inode, start); found {
+			process.PID = pid
+			process.Path, _ = os.Readlink("/proc/" + strconv.Itoa(pid) + "/exe")
+		}
+		return process, nil
+	}
+	return nil, newError("no socket found")
+}
+
+// findSocket finds the socket bound to the source in a /proc/net table, and returns its owner uid and inode. If
+// unspecified is true, sockets bound to the unspecified address on the source port also match.
+func findSocket(table string, source net.Destination, unspecified bool) (uint32, string, bool, error) {
+	f, err := os.Open(table)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, "", false, nil
+		}
+		return 0, "", false, err
+	}
+	defer f.Close()
+
+	ip := source.Address.IP()
+	scanner := bufio.NewScanner(f)
+	scanner.Scan() // Skip the header.
+	for scanner.Scan() {
+		// sl local_address rem_address st tx_queue:rx_queue tr:tm->when retrnsmt uid timeout inode
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 10 {
+			continue
+		}
+		localIP, localPort, ok := parseSocketAddress(fields[1])
+		if !ok || localPort != source.Port {
+			continue
+		}
+		if !localIP.Equal(ip) && !(unspecified && localIP.IsUnspecified()) {
+			continue
+		}
+		uid, err := strconv.ParseUint(fields[7], 10, 32)
+		if err != nil {
+			continue
+		}
+		return uint32(uid), fields[9], true, nil
+	}
+	return 0, "", false, scanner.Err()
+}
+
+// parseSocketAddress parses an address like "0100007F:0050" in a /proc/net table. The IP is printed in 32-bit
+// words in the host byte order.
+func parseSocketAddress(s string) (net.IP, net.Port, bool) {
+	host, port, found := strings.Cut(s, ":")
+	if !found {
+		return nil, 0, false
+	}
+	b, err := hex.DecodeString(host)
+	if err != nil || (len(b) != net.IPv4len && len(b) != net.IPv6len) {
+		return nil, 0, false
+	}
+	ip := make(net.IP, len(b))
+	for i := 0; i < len(b); i += 4 {
+		binary.NativeEndian.PutUint32(ip[i:], binary.BigEndian.Uint32(b[i:]))
+	}
+	p, err := strconv.ParseUint(port, 16, 16)
+	if err != nil {
+		return nil, 0, false
+	}
+	return ip, net.Port(p), true
+}
+
+// socketOwnerTTL is how long the owners found by a scan of /proc are reused, so that connections arriving together
+// share one scan.
+const socketOwnerTTL = 2 * time.Second
+
+var socketOwners struct {
+	sync.Mutex
+	pids    map[string]int // by socket inode
+	scanned time.Time
+}
+
+// findSocketOwner finds the process that has the socket of the inode open. The socket was found in a /proc/net table
+// at the given time.
+func findSocketOwner(inode string, found time.Time) (int, bool) {
+	socketOwners.Lock()
+	defer socketOwners.Unlock()
+
+	if pid, ok := socketOwners.pids[inode]; ok && time.Since(socketOwners.scanned) < socketOwnerTTL {
+		return pid, true
+	}
+	// The socket is not visible, if it was not found by a scan after it was opened.
+	if socketOwners.scanned.After(found) {
+		return 0, false
+	}
+	socketOwners.scanned = time.Now()
+	socketOwners.pids = scanSocketOwners()
+	pid, ok := socketOwners.pids[inode]
+	return pid, ok
+}
+
+// scanSocketOwners finds the processes that have sockets open, by the inodes of the sockets.
+func scanSocketOwners() map[string]int {
+	pids := make(map[string]int)
+	procs, err := os.ReadDir("/proc")
+	if err != nil {
+		return pids
+	}
+	for _, proc := range procs {
+		pid, err := strconv.Atoi(proc.Name())
+		if err != nil {
+			continue
+		}
+		fdDir := "/proc/" + proc.Name() + "/fd"
+		fds, err := os.ReadDir(fdDir)
+		if err != nil {
+			continue
+		}
+		for _, fd := range fds {
+			link, err := os.Readlink(fdDir + "/" + fd.Name())
+			if err != nil {
+				continue
+			}
+			if inode, ok := strings.CutPrefix(link, "socket:["); ok {
+				pids[strings.TrimSuffix(inode, "]")] = pid
+			}
+		}
+	}
+	return pids
+}
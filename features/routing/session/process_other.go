@@ -0,0 +1,12 @@
+//go:build !linux
+
+package session
+
+import (
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/features/routing"
+)
+
+func findProcess(network net.Network, source net.Destination) (*routing.Process, error) {
+	return nil, newError("process lookup is only supported on Linux")
+}
@@ -79,6 +79,29 @@ type Manager interface {
 	GetChannel(string) Channel
 }
 
+// GaugeManager is a Manager of gauges, the counters of current values such as the number of active connections.
+// Gauges only change by Add, so that they stay correct when counters are reset.
+type GaugeManager interface {
+	Manager
+
+	// RegisterGauge registers a new gauge to the manager. The identifier string must not be empty, and unique among other counters.
+	RegisterGauge(string) (Counter, error)
+}
+
+// GetOrRegisterGauge tries to get the counter first. If not exist, it then tries to create a new gauge, or a counter
+// if the manager has no gauges.
+func GetOrRegisterGauge(m Manager, name string) (Counter, error) {
+	counter := m.GetCounter(name)
+	if counter != nil {
+		return counter, nil
+	}
+
+	if gm, ok := m.(GaugeManager); ok {
+		return gm.RegisterGauge(name)
+	}
+	return m.RegisterCounter(name)
+}
+
 // GetOrRegisterCounter tries to get the StatCounter first. If not exist, it then tries to create a new counter.
 func GetOrRegisterCounter(m Manager, name string) (Counter, error) {
 	counter := m.GetCounter(name)
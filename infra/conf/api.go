@@ -4,6 +4,8 @@ import (
 	"strings"
 
 	"github.com/xtls/xray-core/app/commander"
+	dispatcherservice "github.com/xtls/xray-core/app/dispatcher/command"
+	dnsservice "github.com/xtls/xray-core/app/dns/command"
 	loggerservice "github.com/xtls/xray-core/app/log/command"
 	observatoryservice "github.com/xtls/xray-core/app/observatory/command"
 	handlerservice "github.com/xtls/xray-core/app/proxyman/command"
@@ -38,6 +40,10 @@ func (c *APIConfig) Build() (*commander.Config, error) {
 			services = append(services, serial.ToTypedMessage(&observatoryservice.Config{}))
 		case "routingservice":
 			services = append(services, serial.ToTypedMessage(&routerservice.Config{}))
+		case "connectionservice":
+			services = append(services, serial.ToTypedMessage(&dispatcherservice.Config{}))
+		case "dnsservice":
+			services = append(services, serial.ToTypedMessage(&dnsservice.Config{}))
 		}
 	}
 
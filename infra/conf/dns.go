@@ -18,6 +18,7 @@ type NameServerConfig struct {
 	Domains       []string
 	ExpectIPs     StringList
 	QueryStrategy string
+	RuleSet       StringList
 }
 
 func (c *NameServerConfig) UnmarshalJSON(data []byte) error {
@@ -35,6 +36,7 @@ func (c *NameServerConfig) UnmarshalJSON(data []byte) error {
 		Domains       []string   `json:"domains"`
 		ExpectIPs     StringList `json:"expectIps"`
 		QueryStrategy string     `json:"queryStrategy"`
+		RuleSet       StringList `json:"ruleSet"`
 	}
 	if err := json.Unmarshal(data, &advanced); err == nil {
 		c.Address = advanced.Address
@@ -44,6 +46,7 @@ func (c *NameServerConfig) UnmarshalJSON(data []byte) error {
 		c.Domains = advanced.Domains
 		c.ExpectIPs = advanced.ExpectIPs
 		c.QueryStrategy = advanced.QueryStrategy
+		c.RuleSet = advanced.RuleSet
 		return nil
 	}
 
@@ -116,6 +119,7 @@ func (c *NameServerConfig) Build() (*dns.NameServer, error) {
 		Geoip:             geoipList,
 		OriginalRules:     originalRules,
 		QueryStrategy:     resolveQueryStrategy(c.QueryStrategy),
+		RuleSet:           parseRuleSetFiles(c.RuleSet),
 	}, nil
 }
 
@@ -136,6 +140,11 @@ type DNSConfig struct {
 	DisableCache           bool                `json:"disableCache"`
 	DisableFallback        bool                `json:"disableFallback"`
 	DisableFallbackIfMatch bool                `json:"disableFallbackIfMatch"`
+	CacheSize              uint32              `json:"cacheSize"`
+	ServeStale             uint32              `json:"serveStale"`
+	Prefetch               bool                `json:"prefetch"`
+	QueryLogSize           int32               `json:"queryLogSize"`
+	ServerStrategy         string              `json:"serverStrategy"`
 }
 
 type HostAddress struct {
@@ -315,6 +324,10 @@ func (c *DNSConfig) Build() (*dns.Config, error) {
 		DisableCache:           c.DisableCache,
 		DisableFallback:        c.DisableFallback,
 		DisableFallbackIfMatch: c.DisableFallbackIfMatch,
+		CacheSize:              c.CacheSize,
+		ServeStale:             c.ServeStale,
+		Prefetch:               c.Prefetch,
+		QueryLogSize:           c.QueryLogSize,
 		QueryStrategy:          resolveQueryStrategy(c.QueryStrategy),
 	}
 
@@ -325,6 +338,15 @@ func (c *DNSConfig) Build() (*dns.Config, error) {
 		config.ClientIp = []byte(c.ClientIP.IP())
 	}
 
+	switch strings.ToLower(c.ServerStrategy) {
+	case "", "sequential":
+		config.ServerStrategy = dns.ServerStrategy_Sequential
+	case "fastest":
+		config.ServerStrategy = dns.ServerStrategy_Fastest
+	default:
+		return nil, newError(`unknown "serverStrategy": `, c.ServerStrategy)
+	}
+
 	for _, server := range c.Servers {
 		ns, err := server.Build()
 		if err != nil {
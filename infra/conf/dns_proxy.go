@@ -1,6 +1,8 @@
 package conf
 
 import (
+	"strings"
+
 	"github.com/xtls/xray-core/common/net"
 	"github.com/xtls/xray-core/proxy/dns"
 	"google.golang.org/protobuf/proto"
@@ -28,10 +30,35 @@ func (c *DNSOutboundConfig) Build() (proto.Message, error) {
 	switch c.NonIPQuery {
 	case "":
 		c.NonIPQuery = "drop"
-	case "drop", "skip":
+	case "drop", "skip", "resolve":
 	default:
 		return nil, newError(`unknown "nonIPQuery": `, c.NonIPQuery)
 	}
 	config.Non_IPQuery = c.NonIPQuery
 	return config, nil
 }
+
+type DNSInboundConfig struct {
+	Mode      string `json:"mode"`
+	Path      string `json:"path"`
+	UserLevel uint32 `json:"userLevel"`
+}
+
+func (c *DNSInboundConfig) Build() (proto.Message, error) {
+	config := &dns.ServerConfig{
+		Path:      c.Path,
+		UserLevel: c.UserLevel,
+	}
+	switch strings.ToLower(c.Mode) {
+	case "", "tcp", "dot":
+		config.Mode = dns.ServerConfig_TCP
+	case "http", "doh":
+		config.Mode = dns.ServerConfig_HTTP
+	default:
+		return nil, newError(`unknown "mode": `, c.Mode)
+	}
+	if len(config.Path) > 0 && config.Path[0] != '/' {
+		return nil, newError(`"path" must start with "/": `, c.Path)
+	}
+	return config, nil
+}
@@ -30,5 +30,42 @@ func TestDnsProxyConfig(t *testing.T) {
 				Non_IPQuery: "drop",
 			},
 		},
+		{
+			Input: `{
+				"nonIPQuery": "resolve"
+			}`,
+			Parser: loadJSON(creator),
+			Output: &dns.Config{
+				Server:      &net.Endpoint{},
+				Non_IPQuery: "resolve",
+			},
+		},
+	})
+}
+
+func TestDnsInboundConfig(t *testing.T) {
+	creator := func() Buildable {
+		return new(DNSInboundConfig)
+	}
+
+	runMultiTestCase(t, []TestCase{
+		{
+			Input:  `{}`,
+			Parser: loadJSON(creator),
+			Output: &dns.ServerConfig{},
+		},
+		{
+			Input: `{
+				"mode": "doh",
+				"path": "/resolve",
+				"userLevel": 1
+			}`,
+			Parser: loadJSON(creator),
+			Output: &dns.ServerConfig{
+				Mode:      dns.ServerConfig_HTTP,
+				Path:      "/resolve",
+				UserLevel: 1,
+			},
+		},
 	})
 }
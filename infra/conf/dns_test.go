@@ -145,5 +145,35 @@ func TestDNSConfigParsing(t *testing.T) {
 				DisableFallback: true,
 			},
 		},
+		{
+			Input: `{
+				"servers": ["8.8.8.8"],
+				"cacheSize": 4096,
+				"serveStale": 86400,
+				"prefetch": true,
+				"queryLogSize": -1,
+				"serverStrategy": "fastest"
+			}`,
+			Parser: parserCreator(),
+			Output: &dns.Config{
+				NameServer: []*dns.NameServer{
+					{
+						Address: &net.Endpoint{
+							Address: &net.IPOrDomain{
+								Address: &net.IPOrDomain_Ip{
+									Ip: []byte{8, 8, 8, 8},
+								},
+							},
+							Network: net.Network_UDP,
+						},
+					},
+				},
+				CacheSize:      4096,
+				ServeStale:     86400,
+				Prefetch:       true,
+				QueryLogSize:   -1,
+				ServerStrategy: dns.ServerStrategy_Fastest,
+			},
+		},
 	})
 }
@@ -15,21 +15,79 @@ func DefaultLogConfig() *log.Config {
 	}
 }
 
+type LogRotationConfig struct {
+	MaxSize    uint32 `json:"maxSize"`
+	Interval   string `json:"interval"`
+	MaxBackups uint32 `json:"maxBackups"`
+	MaxAge     uint32 `json:"maxAge"`
+	Compress   bool   `json:"compress"`
+}
+
+func (c *LogRotationConfig) Build() (*log.Rotation, error) {
+	if c == nil {
+		return nil, nil
+	}
+	rotation := &log.Rotation{
+		MaxSize:    c.MaxSize,
+		MaxBackups: c.MaxBackups,
+		MaxAge:     c.MaxAge,
+		Compress:   c.Compress,
+	}
+	switch strings.ToLower(c.Interval) {
+	case "", "never":
+		rotation.Interval = log.RotationInterval_Never
+	case "hourly":
+		rotation.Interval = log.RotationInterval_Hourly
+	case "daily":
+		rotation.Interval = log.RotationInterval_Daily
+	default:
+		return nil, newError("unknown log rotation interval: ", c.Interval)
+	}
+	return rotation, nil
+}
+
 type LogConfig struct {
-	AccessLog string `json:"access"`
-	ErrorLog  string `json:"error"`
-	LogLevel  string `json:"loglevel"`
-	DNSLog    bool   `json:"dnsLog"`
+	AccessLog      string             `json:"access"`
+	AccessFormat   string             `json:"accessFormat"`
+	AccessFields   []string           `json:"accessFields"`
+	AccessRotation *LogRotationConfig `json:"accessRotation"`
+	ErrorLog       string             `json:"error"`
+	ErrorRotation  *LogRotationConfig `json:"errorRotation"`
+	LogLevel       string             `json:"loglevel"`
+	DNSLog         bool               `json:"dnsLog"`
 }
 
-func (v *LogConfig) Build() *log.Config {
+func (v *LogConfig) Build() (*log.Config, error) {
 	if v == nil {
-		return nil
+		return nil, nil
 	}
 	config := &log.Config{
-		ErrorLogType:  log.LogType_Console,
-		AccessLogType: log.LogType_Console,
-		EnableDnsLog:  v.DNSLog,
+		ErrorLogType:    log.LogType_Console,
+		AccessLogType:   log.LogType_Console,
+		EnableDnsLog:    v.DNSLog,
+		AccessLogFields: v.AccessFields,
+	}
+
+	switch strings.ToLower(v.AccessFormat) {
+	case "", "text":
+		config.AccessLogFormat = log.AccessLogFormat_Text
+	case "json":
+		config.AccessLogFormat = log.AccessLogFormat_JSON
+	case "logfmt":
+		config.AccessLogFormat = log.AccessLogFormat_Logfmt
+	default:
+		return nil, newError("unknown access log format: ", v.AccessFormat)
+	}
+	if err := log.ValidateAccessFields(v.AccessFields); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if config.AccessLogRotation, err = v.AccessRotation.Build(); err != nil {
+		return nil, newError("invalid access log rotation").Base(err)
+	}
+	if config.ErrorLogRotation, err = v.ErrorRotation.Build(); err != nil {
+		return nil, newError("invalid error log rotation").Base(err)
 	}
 
 	if v.AccessLog == "none" {
@@ -59,5 +117,5 @@ func (v *LogConfig) Build() *log.Config {
 	default:
 		config.ErrorLogLevel = clog.Severity_Warning
 	}
-	return config
+	return config, nil
 }
@@ -0,0 +1,75 @@
+package conf_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/xtls/xray-core/app/log"
+	clog "github.com/xtls/xray-core/common/log"
+	. "github.com/xtls/xray-core/infra/conf"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestLogConfig(t *testing.T) {
+	parser := func(s string) (proto.Message, error) {
+		config := new(LogConfig)
+		if err := json.Unmarshal([]byte(s), config); err != nil {
+			return nil, err
+		}
+		return config.Build()
+	}
+
+	runMultiTestCase(t, []TestCase{
+		{
+			Input:  `{}`,
+			Parser: parser,
+			Output: &log.Config{
+				ErrorLogType:  log.LogType_Console,
+				ErrorLogLevel: clog.Severity_Warning,
+				AccessLogType: log.LogType_Console,
+			},
+		},
+		{
+			Input: `{
+				"access": "/var/log/xray/access.log",
+				"accessFormat": "JSON",
+				"accessFields": ["time", "email", "duration"],
+				"accessRotation": {
+					"maxSize": 100,
+					"interval": "daily",
+					"maxBackups": 30,
+					"compress": true
+				},
+				"error": "/var/log/xray/error.log",
+				"errorRotation": {
+					"maxAge": 7
+				}
+			}`,
+			Parser: parser,
+			Output: &log.Config{
+				ErrorLogLevel:   clog.Severity_Warning,
+				AccessLogType:   log.LogType_File,
+				AccessLogPath:   "/var/log/xray/access.log",
+				AccessLogFormat: log.AccessLogFormat_JSON,
+				AccessLogFields: []string{"time", "email", "duration"},
+				AccessLogRotation: &log.Rotation{
+					MaxSize:    100,
+					Interval:   log.RotationInterval_Daily,
+					MaxBackups: 30,
+					Compress:   true,
+				},
+				ErrorLogType: log.LogType_File,
+				ErrorLogPath: "/var/log/xray/error.log",
+				ErrorLogRotation: &log.Rotation{
+					MaxAge: 7,
+				},
+			},
+		},
+	})
+
+	for _, input := range []string{`{"accessFormat": "xml"}`, `{"accessFields": ["unknown"]}`, `{"errorRotation": {"interval": "yearly"}}`} {
+		if _, err := parser(input); err == nil {
+			t.Error("expected error for ", input)
+		}
+	}
+}
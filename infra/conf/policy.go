@@ -5,13 +5,29 @@ import (
 )
 
 type Policy struct {
-	Handshake         *uint32 `json:"handshake"`
-	ConnectionIdle    *uint32 `json:"connIdle"`
-	UplinkOnly        *uint32 `json:"uplinkOnly"`
-	DownlinkOnly      *uint32 `json:"downlinkOnly"`
-	StatsUserUplink   bool    `json:"statsUserUplink"`
-	StatsUserDownlink bool    `json:"statsUserDownlink"`
-	BufferSize        *int32  `json:"bufferSize"`
+	Handshake         *uint32    `json:"handshake"`
+	ConnectionIdle    *uint32    `json:"connIdle"`
+	UplinkOnly        *uint32    `json:"uplinkOnly"`
+	DownlinkOnly      *uint32    `json:"downlinkOnly"`
+	StatsUserUplink   bool       `json:"statsUserUplink"`
+	StatsUserDownlink bool       `json:"statsUserDownlink"`
+	BufferSize        *int32     `json:"bufferSize"`
+	RateLimit         *RateLimit `json:"rateLimit"`
+	MaxConnections    uint32     `json:"maxConnections"`
+	MaxSourceIPs      uint32     `json:"maxSourceIPs"`
+}
+
+// RateLimit is the bandwidth limit in bytes per second, 0 for unlimited.
+type RateLimit struct {
+	Uplink   uint64 `json:"uplink"`
+	Downlink uint64 `json:"downlink"`
+}
+
+func (r *RateLimit) Build() *policy.Policy_RateLimit {
+	return &policy.Policy_RateLimit{
+		Uplink:   r.Uplink,
+		Downlink: r.Downlink,
+	}
 }
 
 func (t *Policy) Build() (*policy.Policy, error) {
@@ -47,6 +63,17 @@ func (t *Policy) Build() (*policy.Policy, error) {
 		}
 	}
 
+	if t.RateLimit != nil {
+		p.RateLimit = t.RateLimit.Build()
+	}
+
+	if t.MaxConnections > 0 || t.MaxSourceIPs > 0 {
+		p.UserLimit = &policy.Policy_UserLimit{
+			MaxConnections: t.MaxConnections,
+			MaxSourceIps:   t.MaxSourceIPs,
+		}
+	}
+
 	return p, nil
 }
 
@@ -69,8 +96,9 @@ func (p *SystemPolicy) Build() (*policy.SystemPolicy, error) {
 }
 
 type PolicyConfig struct {
-	Levels map[uint32]*Policy `json:"levels"`
-	System *SystemPolicy      `json:"system"`
+	Levels         map[uint32]*Policy    `json:"levels"`
+	System         *SystemPolicy         `json:"system"`
+	UserRateLimits map[string]*RateLimit `json:"userRateLimits"`
 }
 
 func (c *PolicyConfig) Build() (*policy.Config, error) {
@@ -96,5 +124,14 @@ func (c *PolicyConfig) Build() (*policy.Config, error) {
 		config.System = sc
 	}
 
+	if len(c.UserRateLimits) > 0 {
+		config.UserRateLimit = make(map[string]*policy.Policy_RateLimit, len(c.UserRateLimits))
+		for email, r := range c.UserRateLimits {
+			if r != nil {
+				config.UserRateLimit[email] = r.Build()
+			}
+		}
+	}
+
 	return config, nil
 }
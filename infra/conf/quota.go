@@ -0,0 +1,66 @@
+package conf
+
+import (
+	"strings"
+
+	"github.com/xtls/xray-core/app/quota"
+)
+
+type UserQuotaConfig struct {
+	Email       string `json:"email"`
+	Bytes       uint64 `json:"bytes"`
+	ResetPeriod string `json:"resetPeriod"`
+	ResetDay    uint32 `json:"resetDay"`
+}
+
+func (c *UserQuotaConfig) Build() (*quota.UserQuota, error) {
+	if c.Email == "" {
+		return nil, newError("email of user quota can't be empty.")
+	}
+
+	config := &quota.UserQuota{
+		Email:    c.Email,
+		Bytes:    c.Bytes,
+		ResetDay: c.ResetDay,
+	}
+	switch strings.ToLower(c.ResetPeriod) {
+	case "", "never":
+		config.ResetPeriod = quota.ResetPeriod_Never
+	case "daily":
+		config.ResetPeriod = quota.ResetPeriod_Daily
+	case "weekly":
+		if c.ResetDay > 6 {
+			return nil, newError("reset day of weekly quota must be in 0 (Sunday) to 6, but got ", c.ResetDay)
+		}
+		config.ResetPeriod = quota.ResetPeriod_Weekly
+	case "monthly":
+		if c.ResetDay < 1 || c.ResetDay > 28 {
+			return nil, newError("reset day of monthly quota must be in 1 to 28, but got ", c.ResetDay)
+		}
+		config.ResetPeriod = quota.ResetPeriod_Monthly
+	default:
+		return nil, newError("unknown reset period: ", c.ResetPeriod)
+	}
+	return config, nil
+}
+
+type QuotaConfig struct {
+	Users        []*UserQuotaConfig `json:"users"`
+	StateFile    string             `json:"stateFile"`
+	SaveInterval uint32             `json:"saveInterval"`
+}
+
+func (c *QuotaConfig) Build() (*quota.Config, error) {
+	config := &quota.Config{
+		StateFile:    c.StateFile,
+		SaveInterval: c.SaveInterval,
+	}
+	for _, u := range c.Users {
+		uq, err := u.Build()
+		if err != nil {
+			return nil, err
+		}
+		config.User = append(config.User, uq)
+	}
+	return config, nil
+}
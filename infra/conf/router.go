@@ -146,6 +146,59 @@ type RouterRule struct {
 	BalancerTag string `json:"balancerTag"`
 
 	DomainMatcher string `json:"domainMatcher"`
+
+	Action *RuleActionConfig `json:"action"`
+}
+
+// RuleActionConfig is the action of a routing rule, besides choosing the outbound.
+type RuleActionConfig struct {
+	Reject         string            `json:"reject"`
+	Address        *Address          `json:"address"`
+	Port           uint16            `json:"port"`
+	DomainStrategy string            `json:"domainStrategy"`
+	Attributes     map[string]string `json:"attrs"`
+}
+
+// Build implements Buildable.
+func (c *RuleActionConfig) Build() (*router.RuleAction, error) {
+	action := &router.RuleAction{
+		Port:       uint32(c.Port),
+		Attributes: c.Attributes,
+	}
+	switch strings.ToLower(c.Reject) {
+	case "", "none":
+		action.Reject = router.RuleAction_None
+	case "reset", "rst":
+		action.Reject = router.RuleAction_Reset
+	case "http403", "http":
+		action.Reject = router.RuleAction_HTTP403
+	case "drop":
+		action.Reject = router.RuleAction_Drop
+	default:
+		return nil, newError("unknown reject type: ", c.Reject)
+	}
+	if c.Address != nil {
+		action.Address = c.Address.Build()
+	}
+	switch strings.ToLower(c.DomainStrategy) {
+	case "asis", "":
+		action.DomainStrategy = router.RuleAction_AsIs
+	case "useip":
+		action.DomainStrategy = router.RuleAction_UseIP
+	case "useipv4":
+		action.DomainStrategy = router.RuleAction_UseIPv4
+	case "useipv6":
+		action.DomainStrategy = router.RuleAction_UseIPv6
+	case "forceip":
+		action.DomainStrategy = router.RuleAction_ForceIP
+	case "forceipv4":
+		action.DomainStrategy = router.RuleAction_ForceIPv4
+	case "forceipv6":
+		action.DomainStrategy = router.RuleAction_ForceIPv6
+	default:
+		return nil, newError("unsupported domain strategy in rule action: ", c.DomainStrategy)
+	}
+	return action, nil
 }
 
 func ParseIP(s string) (*router.CIDR, error) {
@@ -554,6 +607,11 @@ func parseFieldRule(msg json.RawMessage) (*router.RoutingRule, error) {
 		InboundTag *StringList       `json:"inboundTag"`
 		Protocols  *StringList       `json:"protocol"`
 		Attributes map[string]string `json:"attrs"`
+		Time       *StringList       `json:"time"`
+		Timezone   string            `json:"timezone"`
+		Process    *StringList       `json:"process"`
+		UID        []uint32          `json:"uid"`
+		RuleSet    *StringList       `json:"ruleSet"`
 	}
 	rawFieldRule := new(RawFieldRule)
 	err := json.Unmarshal(msg, rawFieldRule)
@@ -572,10 +630,20 @@ func parseFieldRule(msg json.RawMessage) (*router.RoutingRule, error) {
 		rule.TargetTag = &router.RoutingRule_BalancingTag{
 			BalancingTag: rawFieldRule.BalancerTag,
 		}
+	case rawFieldRule.Action != nil:
+		// Rules with an action may use the default outbound, or reject the connection.
 	default:
 		return nil, newError("neither outboundTag nor balancerTag is specified in routing rule")
 	}
 
+	if rawFieldRule.Action != nil {
+		action, err := rawFieldRule.Action.Build()
+		if err != nil {
+			return nil, err
+		}
+		rule.Action = action
+	}
+
 	if rawFieldRule.DomainMatcher != "" {
 		rule.DomainMatcher = rawFieldRule.DomainMatcher
 	}
@@ -650,9 +718,124 @@ func parseFieldRule(msg json.RawMessage) (*router.RoutingRule, error) {
 		rule.Attributes = rawFieldRule.Attributes
 	}
 
+	if rawFieldRule.Time != nil {
+		for _, s := range *rawFieldRule.Time {
+			timeRange, err := parseTimeRange(s)
+			if err != nil {
+				return nil, newError("invalid time in routing rule: ", s).Base(err)
+			}
+			rule.Time = append(rule.Time, timeRange)
+		}
+	}
+	if len(rawFieldRule.Timezone) > 0 {
+		if _, err := router.ParseTimezone(rawFieldRule.Timezone); err != nil {
+			return nil, err
+		}
+		rule.Timezone = rawFieldRule.Timezone
+	}
+
+	if rawFieldRule.RuleSet != nil {
+		rule.RuleSet = parseRuleSetFiles(*rawFieldRule.RuleSet)
+	}
+
+	if rawFieldRule.Process != nil {
+		rule.ProcessName = *rawFieldRule.Process
+	}
+
+	if len(rawFieldRule.UID) > 0 {
+		rule.Uid = rawFieldRule.UID
+	}
+
 	return rule, nil
 }
 
+// parseRuleSetFiles parses paths of rule-set files. The format is guessed from the file extension, unless the path
+// has a "text:", "json:" or "binary:" prefix.
+func parseRuleSetFiles(paths []string) []*router.RuleSetFile {
+	files := make([]*router.RuleSetFile, 0, len(paths))
+	for _, path := range paths {
+		file := &router.RuleSetFile{Path: path}
+		if prefix, p, found := strings.Cut(path, ":"); found {
+			switch prefix {
+			case "text":
+				file.Format, file.Path = router.RuleSetFile_Text, p
+			case "json":
+				file.Format, file.Path = router.RuleSetFile_JSON, p
+			case "binary":
+				file.Format, file.Path = router.RuleSetFile_Binary, p
+			}
+		}
+		files = append(files, file)
+	}
+	return files
+}
+
+var weekdays = map[string]uint32{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+// parseTimeRange parses a time window like "mon-fri 08:00-15:30". Either the weekdays or the time may be omitted,
+// meaning every day or the whole day respectively.
+func parseTimeRange(s string) (*router.TimeRange, error) {
+	timeRange := new(router.TimeRange)
+	fields := strings.Fields(strings.ToLower(s))
+	if len(fields) == 0 || len(fields) > 2 {
+		return nil, newError("expecting weekdays and/or time range")
+	}
+	for _, field := range fields {
+		if strings.Contains(field, ":") {
+			from, to, found := strings.Cut(field, "-")
+			if !found {
+				return nil, newError("invalid time range ", field)
+			}
+			start, err := parseMinuteOfDay(from)
+			if err != nil {
+				return nil, err
+			}
+			end, err := parseMinuteOfDay(to)
+			if err != nil {
+				return nil, err
+			}
+			timeRange.Start, timeRange.End = start, end
+			continue
+		}
+		for _, days := range strings.Split(field, ",") {
+			from, to, found := strings.Cut(days, "-")
+			first, ok := weekdays[from]
+			if !ok {
+				return nil, newError("invalid weekday ", from)
+			}
+			last := first
+			if found {
+				if last, ok = weekdays[to]; !ok {
+					return nil, newError("invalid weekday ", to)
+				}
+			}
+			for day := first; ; day = (day + 1) % 7 {
+				timeRange.Weekday = append(timeRange.Weekday, day)
+				if day == last {
+					break
+				}
+			}
+		}
+	}
+	return timeRange, nil
+}
+
+// parseMinuteOfDay parses a time like "15:30" into minutes since midnight. "24:00" is allowed as the end of a day.
+func parseMinuteOfDay(s string) (uint32, error) {
+	hours, minutes, _ := strings.Cut(s, ":")
+	h, err := strconv.ParseUint(hours, 10, 32)
+	if err != nil {
+		return 0, newError("invalid time ", s).Base(err)
+	}
+	m, err := strconv.ParseUint(minutes, 10, 32)
+	if err != nil || m > 59 || h*60+m > 24*60 {
+		return 0, newError("invalid time ", s)
+	}
+	return uint32(h*60 + m), nil
+}
+
 func ParseRule(msg json.RawMessage) (*router.RoutingRule, error) {
 	rawRule := new(RouterRule)
 	err := json.Unmarshal(msg, rawRule)
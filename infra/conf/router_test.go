@@ -354,3 +354,152 @@ func TestRouterConfig(t *testing.T) {
 		},
 	})
 }
+
+func TestTimeRule(t *testing.T) {
+	rule, err := ParseRule(json.RawMessage(`{
+		"type": "field",
+		"user": ["student@xray.com"],
+		"time": ["mon-fri 08:00-15:30", "sat,sun", "fri-mon 22:00-06:00", "12:00-24:00"],
+		"timezone": "+08:00",
+		"outboundTag": "restricted"
+	}`))
+	common.Must(err)
+
+	expected := &router.RoutingRule{
+		TargetTag: &router.RoutingRule_Tag{
+			Tag: "restricted",
+		},
+		UserEmail: []string{"student@xray.com"},
+		Time: []*router.TimeRange{
+			{Weekday: []uint32{1, 2, 3, 4, 5}, Start: 480, End: 930},
+			{Weekday: []uint32{6, 0}},
+			{Weekday: []uint32{5, 6, 0, 1}, Start: 1320, End: 360},
+			{Start: 720, End: 1440},
+		},
+		Timezone: "+08:00",
+	}
+	if !proto.Equal(rule, expected) {
+		t.Fatal("expected ", expected, ", but got ", rule)
+	}
+
+	for _, input := range []string{
+		`{"type": "field", "time": ["someday"], "outboundTag": "a"}`,
+		`{"type": "field", "time": ["08:00-25:00"], "outboundTag": "a"}`,
+		`{"type": "field", "time": ["08:00"], "outboundTag": "a"}`,
+		`{"type": "field", "time": ["mon"], "timezone": "Nowhere/Special", "outboundTag": "a"}`,
+	} {
+		if _, err := ParseRule(json.RawMessage(input)); err == nil {
+			t.Error("expected error for ", input)
+		}
+	}
+}
+
+func TestRuleAction(t *testing.T) {
+	rule, err := ParseRule(json.RawMessage(`{
+		"type": "field",
+		"domain": ["ads.example.com"],
+		"action": {"reject": "http403"}
+	}`))
+	common.Must(err)
+
+	expected := &router.RoutingRule{
+		Domain: []*router.Domain{
+			{Type: router.Domain_Plain, Value: "ads.example.com"},
+		},
+		Action: &router.RuleAction{
+			Reject: router.RuleAction_HTTP403,
+		},
+	}
+	if !proto.Equal(rule, expected) {
+		t.Fatal("expected ", expected, ", but got ", rule)
+	}
+
+	rule, err = ParseRule(json.RawMessage(`{
+		"type": "field",
+		"port": 53,
+		"outboundTag": "direct",
+		"action": {
+			"address": "1.1.1.1",
+			"port": 5353,
+			"domainStrategy": "ForceIPv4",
+			"attrs": {"dns": "rewritten"}
+		}
+	}`))
+	common.Must(err)
+
+	expected = &router.RoutingRule{
+		TargetTag: &router.RoutingRule_Tag{
+			Tag: "direct",
+		},
+		PortList: &net.PortList{
+			Range: []*net.PortRange{{From: 53, To: 53}},
+		},
+		Action: &router.RuleAction{
+			Address: &net.IPOrDomain{
+				Address: &net.IPOrDomain_Ip{
+					Ip: []byte{1, 1, 1, 1},
+				},
+			},
+			Port:           5353,
+			DomainStrategy: router.RuleAction_ForceIPv4,
+			Attributes:     map[string]string{"dns": "rewritten"},
+		},
+	}
+	if !proto.Equal(rule, expected) {
+		t.Fatal("expected ", expected, ", but got ", rule)
+	}
+
+	for _, input := range []string{
+		`{"type": "field", "domain": ["a.com"], "action": {"reject": "explode"}}`,
+		`{"type": "field", "domain": ["a.com"], "action": {"domainStrategy": "UseIPv4v6"}}`,
+		`{"type": "field", "domain": ["a.com"]}`,
+	} {
+		if _, err := ParseRule(json.RawMessage(input)); err == nil {
+			t.Error("expected error for ", input)
+		}
+	}
+}
+
+func TestProcessRule(t *testing.T) {
+	rule, err := ParseRule(json.RawMessage(`{
+		"type": "field",
+		"process": ["/usr/bin/curl", "wget"],
+		"uid": [0, 1000],
+		"outboundTag": "direct"
+	}`))
+	common.Must(err)
+
+	expected := &router.RoutingRule{
+		TargetTag: &router.RoutingRule_Tag{
+			Tag: "direct",
+		},
+		ProcessName: []string{"/usr/bin/curl", "wget"},
+		Uid:         []uint32{0, 1000},
+	}
+	if !proto.Equal(rule, expected) {
+		t.Fatal("expected ", expected, ", but got ", rule)
+	}
+}
+
+func TestRuleSetRule(t *testing.T) {
+	rule, err := ParseRule(json.RawMessage(`{
+		"type": "field",
+		"ruleSet": ["ads.txt", "/etc/xray/cn.pb", "json:lists/direct.conf"],
+		"outboundTag": "block"
+	}`))
+	common.Must(err)
+
+	expected := &router.RoutingRule{
+		TargetTag: &router.RoutingRule_Tag{
+			Tag: "block",
+		},
+		RuleSet: []*router.RuleSetFile{
+			{Path: "ads.txt"},
+			{Path: "/etc/xray/cn.pb"},
+			{Path: "lists/direct.conf", Format: router.RuleSetFile_JSON},
+		},
+	}
+	if !proto.Equal(rule, expected) {
+		t.Fatal("expected ", expected, ", but got ", rule)
+	}
+}
@@ -1,9 +1,11 @@
 package conf
 
 import (
+	"bytes"
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
+	"encoding/pem"
 	"math"
 	"net/url"
 	"runtime"
@@ -11,9 +13,9 @@ import (
 	"strings"
 	"syscall"
 
+	"github.com/OmarTariq612/goech"
 	"github.com/xtls/xray-core/common/net"
 	"github.com/xtls/xray-core/common/platform/filesystem"
-	"github.com/xtls/xray-core/common/protocol"
 	"github.com/xtls/xray-core/common/serial"
 	"github.com/xtls/xray-core/transport/internet"
 	"github.com/xtls/xray-core/transport/internet/domainsocket"
@@ -23,6 +25,7 @@ import (
 	"github.com/xtls/xray-core/transport/internet/kcp"
 	"github.com/xtls/xray-core/transport/internet/quic"
 	"github.com/xtls/xray-core/transport/internet/reality"
+	"github.com/xtls/xray-core/transport/internet/splithttp"
 	"github.com/xtls/xray-core/transport/internet/tcp"
 	"github.com/xtls/xray-core/transport/internet/tls"
 	"github.com/xtls/xray-core/transport/internet/websocket"
@@ -56,6 +59,12 @@ type KCPConfig struct {
 	WriteBufferSize *uint32         `json:"writeBufferSize"`
 	HeaderConfig    json.RawMessage `json:"header"`
 	Seed            *string         `json:"seed"`
+	FEC             *KCPFECConfig   `json:"fec"`
+}
+
+type KCPFECConfig struct {
+	DataShards   uint32 `json:"dataShards"`
+	ParityShards uint32 `json:"parityShards"`
 }
 
 // Build implements Buildable.
@@ -117,6 +126,16 @@ func (c *KCPConfig) Build() (proto.Message, error) {
 		config.Seed = &kcp.EncryptionSeed{Seed: *c.Seed}
 	}
 
+	if c.FEC != nil {
+		if c.FEC.DataShards == 0 || c.FEC.ParityShards == 0 || c.FEC.DataShards+c.FEC.ParityShards > 256 {
+			return nil, newError("invalid mKCP FEC shards: ", c.FEC.DataShards, "+", c.FEC.ParityShards).AtError()
+		}
+		config.Fec = &kcp.FEC{
+			DataShards:   c.FEC.DataShards,
+			ParityShards: c.FEC.ParityShards,
+		}
+	}
+
 	return config, nil
 }
 
@@ -221,6 +240,38 @@ func (c *HttpUpgradeConfig) Build() (proto.Message, error) {
 	return config, nil
 }
 
+type SplitHTTPConfig struct {
+	Host                 string            `json:"host"`
+	Path                 string            `json:"path"`
+	Headers              map[string]string `json:"headers"`
+	MaxUploadSize        int32             `json:"maxUploadSize"`
+	MaxConcurrentUploads int32             `json:"maxConcurrentUploads"`
+}
+
+// Build implements Buildable.
+func (c *SplitHTTPConfig) Build() (proto.Message, error) {
+	// Host priority: Host field > headers field > address.
+	if c.Host == "" && c.Headers["host"] != "" {
+		c.Host = c.Headers["host"]
+	} else if c.Host == "" && c.Headers["Host"] != "" {
+		c.Host = c.Headers["Host"]
+	}
+	if c.MaxUploadSize < 0 {
+		return nil, newError("maxUploadSize must not be negative")
+	}
+	if c.MaxConcurrentUploads < 0 {
+		return nil, newError("maxConcurrentUploads must not be negative")
+	}
+	config := &splithttp.Config{
+		Path:                 c.Path,
+		Host:                 c.Host,
+		Header:               c.Headers,
+		MaxUploadSize:        c.MaxUploadSize,
+		MaxConcurrentUploads: c.MaxConcurrentUploads,
+	}
+	return config, nil
+}
+
 type HTTPConfig struct {
 	Host               *StringList            `json:"host"`
 	Path               string                 `json:"path"`
@@ -267,44 +318,38 @@ func (c *HTTPConfig) Build() (proto.Message, error) {
 }
 
 type QUICConfig struct {
-	Header   json.RawMessage `json:"header"`
-	Security string          `json:"security"`
-	Key      string          `json:"key"`
+	Header          json.RawMessage `json:"header"`
+	Security        string          `json:"security"`
+	Key             string          `json:"key"`
+	MaxIdleTimeout  uint32          `json:"maxIdleTimeout"`
+	KeepAlivePeriod uint32          `json:"keepAlivePeriod"`
+	ZeroRTT         bool            `json:"zeroRtt"`
+	Datagram        bool            `json:"datagram"`
+	Migration       bool            `json:"migration"`
 }
 
 // Build implements Buildable.
 func (c *QUICConfig) Build() (proto.Message, error) {
-	config := &quic.Config{
-		Key: c.Key,
-	}
-
+	legacy := len(c.Key) > 0 || (len(c.Security) > 0 && strings.ToLower(c.Security) != "none")
 	if len(c.Header) > 0 {
-		headerConfig, _, err := kcpHeaderLoader.Load(c.Header)
-		if err != nil {
-			return nil, newError("invalid QUIC header config.").Base(err).AtError()
-		}
-		ts, err := headerConfig.(Buildable).Build()
-		if err != nil {
-			return nil, newError("invalid QUIC header config").Base(err).AtError()
+		if _, name, err := kcpHeaderLoader.Load(c.Header); err != nil || name != "none" {
+			legacy = true
 		}
-		config.Header = serial.ToTypedMessage(ts)
 	}
-
-	var st protocol.SecurityType
-	switch strings.ToLower(c.Security) {
-	case "aes-128-gcm":
-		st = protocol.SecurityType_AES128_GCM
-	case "chacha20-poly1305":
-		st = protocol.SecurityType_CHACHA20_POLY1305
-	default:
-		st = protocol.SecurityType_NONE
+	if legacy {
+		newError(`QUIC "header", "security" and "key" are removed and ignored. Use "tls" security instead.`).AtWarning().WriteToLog()
 	}
-
-	config.Security = &protocol.SecurityConfig{
-		Type: st,
+	if c.Migration && c.KeepAlivePeriod == 0 {
+		return nil, newError(`QUIC "migration" requires "keepAlivePeriod"`)
 	}
 
-	return config, nil
+	return &quic.Config{
+		MaxIdleTimeout:  c.MaxIdleTimeout,
+		KeepAlivePeriod: c.KeepAlivePeriod,
+		ZeroRtt:         c.ZeroRTT,
+		Datagram:        c.Datagram,
+		Migration:       c.Migration,
+	}, nil
 }
 
 type DomainSocketConfig struct {
@@ -397,6 +442,84 @@ type TLSConfig struct {
 	PinnedPeerCertificateChainSha256     *[]string        `json:"pinnedPeerCertificateChainSha256"`
 	PinnedPeerCertificatePublicKeySha256 *[]string        `json:"pinnedPeerCertificatePublicKeySha256"`
 	MasterKeyLog                         string           `json:"masterKeyLog"`
+	ECHConfigList                        *StringList      `json:"echConfigList"`
+	ECHQueryDomain                       string           `json:"echQueryDomain"`
+	ECHForce                             bool             `json:"echForce"`
+	ECHServerKeysFile                    string           `json:"echServerKeysFile"`
+	ECHServerKeys                        *StringList      `json:"echServerKeys"`
+	ClientAuth                           string           `json:"clientAuth"`
+}
+
+// parseECHBytes decodes ECH configs or keys in the PEM format generated by `xray tls ech`, or in base64.
+func parseECHBytes(data []byte, pemType string) ([]byte, error) {
+	if !bytes.Contains(data, []byte("-----BEGIN")) {
+		return base64.StdEncoding.DecodeString(string(bytes.TrimSpace(data)))
+	}
+	var decoded []byte
+	for {
+		block, rest := pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != pemType {
+			return nil, newError("unexpected PEM block: ", block.Type)
+		}
+		decoded = append(decoded, block.Bytes...)
+		data = rest
+	}
+	if len(decoded) == 0 {
+		return nil, newError("no ", pemType, " PEM block")
+	}
+	return decoded, nil
+}
+
+func (c *TLSConfig) buildECH(config *tls.Config) error {
+	if c.ECHConfigList != nil && len(*c.ECHConfigList) > 0 {
+		configList, err := parseECHBytes([]byte(strings.Join(*c.ECHConfigList, "\n")), "ECH CONFIGS")
+		if err != nil {
+			return newError("failed to decode echConfigList").Base(err)
+		}
+		if _, err := goech.UnmarshalECHConfigList(configList); err != nil {
+			return newError("invalid echConfigList").Base(err)
+		}
+		config.EchConfigList = configList
+	}
+	config.EchQueryDomain = c.ECHQueryDomain
+	config.EchForce = c.ECHForce
+
+	if len(c.ECHServerKeysFile) > 0 || (c.ECHServerKeys != nil && len(*c.ECHServerKeys) > 0) {
+		var keysStr []string
+		if c.ECHServerKeys != nil {
+			keysStr = *c.ECHServerKeys
+		}
+		keysData, err := readFileOrString(c.ECHServerKeysFile, keysStr)
+		if err != nil {
+			return newError("failed to read echServerKeys").Base(err)
+		}
+		keys, err := parseECHBytes(keysData, "ECH KEYS")
+		if err != nil {
+			return newError("failed to decode echServerKeys").Base(err)
+		}
+		if _, err := goech.UnmarshalECHKeySetList(keys); err != nil {
+			return newError("invalid echServerKeys").Base(err)
+		}
+		config.EchServerKeys = keys
+	}
+
+	useECH := len(config.EchConfigList) > 0 || len(config.EchQueryDomain) > 0
+	if config.EchForce && !useECH {
+		return newError("echForce requires echConfigList or echQueryDomain")
+	}
+	if useECH && config.Fingerprint != "" {
+		return newError("ECH is not supported with uTLS fingerprints")
+	}
+	if useECH || len(config.EchServerKeys) > 0 {
+		switch config.MaxVersion {
+		case "1.0", "1.1", "1.2":
+			return newError("ECH requires TLS 1.3, but maxVersion is ", config.MaxVersion)
+		}
+	}
+	return nil
 }
 
 // Build implements Buildable.
@@ -453,6 +576,37 @@ func (c *TLSConfig) Build() (proto.Message, error) {
 
 	config.MasterKeyLog = c.MasterKeyLog
 
+	switch strings.ToLower(c.ClientAuth) {
+	case "", "none":
+		config.ClientAuth = tls.ClientAuth_NONE
+	case "request":
+		config.ClientAuth = tls.ClientAuth_REQUEST
+	case "require":
+		config.ClientAuth = tls.ClientAuth_REQUIRE
+	case "verify":
+		config.ClientAuth = tls.ClientAuth_VERIFY
+	default:
+		return nil, newError("unknown clientAuth: ", c.ClientAuth)
+	}
+	if config.ClientAuth == tls.ClientAuth_REQUEST || config.ClientAuth == tls.ClientAuth_VERIFY {
+		hasCA := false
+		for _, cert := range config.Certificate {
+			if cert.Usage == tls.Certificate_AUTHORITY_VERIFY {
+				hasCA = true
+			}
+		}
+		if !hasCA {
+			return nil, newError("clientAuth ", c.ClientAuth, " requires certificates of verify usage")
+		}
+	}
+	if config.ClientAuth == tls.ClientAuth_REQUIRE && len(config.PinnedPeerCertificateChainSha256) == 0 {
+		newError("clientAuth require accepts any client certificate without pinnedPeerCertificateChainSha256").AtWarning().WriteToLog()
+	}
+
+	if err := c.buildECH(config); err != nil {
+		return nil, err
+	}
+
 	return config, nil
 }
 
@@ -646,6 +800,8 @@ func (p TransportProtocol) Build() (string, error) {
 		return "grpc", nil
 	case "httpupgrade":
 		return "httpupgrade", nil
+	case "splithttp":
+		return "splithttp", nil
 	default:
 		return "", newError("Config: unknown transport protocol: ", p)
 	}
@@ -760,6 +916,7 @@ type StreamConfig struct {
 	GRPCConfig          *GRPCConfig         `json:"grpcSettings"`
 	GUNConfig           *GRPCConfig         `json:"gunSettings"`
 	HTTPUPGRADESettings *HttpUpgradeConfig  `json:"httpupgradeSettings"`
+	SplitHTTPSettings   *SplitHTTPConfig    `json:"splithttpSettings"`
 }
 
 // Build implements Buildable.
@@ -789,8 +946,9 @@ func (c *StreamConfig) Build() (*internet.StreamConfig, error) {
 		config.SecuritySettings = append(config.SecuritySettings, tm)
 		config.SecurityType = tm.Type
 	case "reality":
-		if config.ProtocolName != "tcp" && config.ProtocolName != "http" && config.ProtocolName != "grpc" && config.ProtocolName != "domainsocket" {
-			return nil, newError("REALITY only supports TCP, H2, gRPC and DomainSocket for now.")
+		// REALITY relies on TCP to forward unauthenticated clients to the target, so it is never available over QUIC.
+		if config.ProtocolName != "tcp" && config.ProtocolName != "http" && config.ProtocolName != "grpc" && config.ProtocolName != "domainsocket" && config.ProtocolName != "splithttp" {
+			return nil, newError("REALITY only supports TCP, H2, gRPC, DomainSocket and SplitHTTP for now.")
 		}
 		if c.REALITYSettings == nil {
 			return nil, newError(`REALITY: Empty "realitySettings".`)
@@ -890,6 +1048,16 @@ func (c *StreamConfig) Build() (*internet.StreamConfig, error) {
 			Settings:     serial.ToTypedMessage(hs),
 		})
 	}
+	if c.SplitHTTPSettings != nil {
+		hs, err := c.SplitHTTPSettings.Build()
+		if err != nil {
+			return nil, newError("Failed to build SplitHTTP config.").Base(err)
+		}
+		config.TransportSettings = append(config.TransportSettings, &internet.TransportConfig{
+			ProtocolName: "splithttp",
+			Settings:     serial.ToTypedMessage(hs),
+		})
+	}
 	if c.SocketSettings != nil {
 		ss, err := c.SocketSettings.Build()
 		if err != nil {
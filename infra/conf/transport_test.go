@@ -1,10 +1,15 @@
 package conf_test
 
 import (
+	"encoding/base64"
 	"encoding/json"
+	"encoding/pem"
+	"strings"
 	"testing"
 
-	"github.com/xtls/xray-core/common/protocol"
+	"github.com/OmarTariq612/goech"
+	"github.com/cloudflare/circl/hpke"
+	"github.com/xtls/xray-core/common"
 	"github.com/xtls/xray-core/common/serial"
 	. "github.com/xtls/xray-core/infra/conf"
 	"github.com/xtls/xray-core/transport/global"
@@ -12,10 +17,11 @@ import (
 	"github.com/xtls/xray-core/transport/internet/grpc"
 	"github.com/xtls/xray-core/transport/internet/headers/http"
 	"github.com/xtls/xray-core/transport/internet/headers/noop"
-	"github.com/xtls/xray-core/transport/internet/headers/tls"
 	"github.com/xtls/xray-core/transport/internet/kcp"
 	"github.com/xtls/xray-core/transport/internet/quic"
+	"github.com/xtls/xray-core/transport/internet/splithttp"
 	"github.com/xtls/xray-core/transport/internet/tcp"
+	"github.com/xtls/xray-core/transport/internet/tls"
 	"github.com/xtls/xray-core/transport/internet/websocket"
 	"google.golang.org/protobuf/proto"
 )
@@ -198,16 +204,20 @@ func TestTransportConfig(t *testing.T) {
 					"mtu": 1200,
 					"header": {
 						"type": "none"
+					},
+					"fec": {
+						"dataShards": 10,
+						"parityShards": 3
 					}
 				},
 				"wsSettings": {
 					"path": "/t"
 				},
 				"quicSettings": {
-					"key": "abcd",
-					"header": {
-						"type": "dtls"
-					}
+					"keepAlivePeriod": 10,
+					"zeroRtt": true,
+					"datagram": true,
+					"migration": true
 				},
 				"grpcSettings": {
 					"serviceName": "name",
@@ -264,6 +274,7 @@ func TestTransportConfig(t *testing.T) {
 						Settings: serial.ToTypedMessage(&kcp.Config{
 							Mtu:          &kcp.MTU{Value: 1200},
 							HeaderConfig: serial.ToTypedMessage(&noop.Config{}),
+							Fec:          &kcp.FEC{DataShards: 10, ParityShards: 3},
 						}),
 					},
 					{
@@ -275,11 +286,10 @@ func TestTransportConfig(t *testing.T) {
 					{
 						ProtocolName: "quic",
 						Settings: serial.ToTypedMessage(&quic.Config{
-							Key: "abcd",
-							Security: &protocol.SecurityConfig{
-								Type: protocol.SecurityType_NONE,
-							},
-							Header: serial.ToTypedMessage(&tls.PacketConfig{}),
+							KeepAlivePeriod: 10,
+							ZeroRtt:         true,
+							Datagram:        true,
+							Migration:       true,
 						}),
 					},
 					{
@@ -310,5 +320,188 @@ func TestTransportConfig(t *testing.T) {
 				},
 			},
 		},
+		{
+			Input: `{
+				"splithttpSettings": {
+					"path": "/sh",
+					"headers": {
+						"Host": "example.com"
+					},
+					"maxUploadSize": 100000,
+					"maxConcurrentUploads": 4
+				}
+			}`,
+			Parser: createParser(),
+			Output: &global.Config{
+				TransportSettings: []*internet.TransportConfig{
+					{
+						ProtocolName: "splithttp",
+						Settings: serial.ToTypedMessage(&splithttp.Config{
+							Host:                 "example.com",
+							Path:                 "/sh",
+							Header:               map[string]string{"Host": "example.com"},
+							MaxUploadSize:        100000,
+							MaxConcurrentUploads: 4,
+						}),
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestQUICConfigUnsupported(t *testing.T) {
+	for _, input := range []string{
+		`{"network": "quic", "security": "reality", "realitySettings": {}}`,
+	} {
+		config := new(StreamConfig)
+		common.Must(json.Unmarshal([]byte(input), config))
+		if _, err := config.Build(); err == nil {
+			t.Error("expected error for ", input)
+		}
+	}
+}
+
+func TestQUICDatagramProtocols(t *testing.T) {
+	for protocol, valid := range map[string]bool{
+		"vless":       true,
+		"trojan":      true,
+		"vmess":       false,
+		"shadowsocks": false,
+	} {
+		config := new(OutboundDetourConfig)
+		common.Must(json.Unmarshal([]byte(`{
+			"protocol": "`+protocol+`",
+			"streamSettings": {"network": "quic", "quicSettings": {"datagram": true}}
+		}`), config))
+		// The settings of the protocol are empty, so only the error of the datagram option is checked.
+		_, err := config.Build()
+		if refused := err != nil && strings.Contains(err.Error(), `"datagram"`); refused == valid {
+			t.Error("datagram with ", protocol, ": ", err)
+		}
+	}
+}
+
+func TestTLSConfigECH(t *testing.T) {
+	keySet, err := goech.GenerateECHKeySet(0, "public.example.com", hpke.KEM_X25519_HKDF_SHA256)
+	common.Must(err)
+	configList, err := keySet.ECHConfig.MarshalBinary()
+	common.Must(err)
+	keys, err := keySet.MarshalBinary()
+	common.Must(err)
+
+	parse := func(s string) (proto.Message, error) {
+		config := new(TLSConfig)
+		if err := json.Unmarshal([]byte(s), config); err != nil {
+			return nil, err
+		}
+		return config.Build()
+	}
+	pemLines := func(pemType string, data []byte) string {
+		lines := strings.Split(strings.TrimSpace(string(pem.EncodeToMemory(&pem.Block{Type: pemType, Bytes: data}))), "\n")
+		content, err := json.Marshal(lines)
+		common.Must(err)
+		return string(content)
+	}
+
+	runMultiTestCase(t, []TestCase{
+		{
+			Input: `{
+				"serverName": "www.example.com",
+				"echConfigList": "` + base64.StdEncoding.EncodeToString(configList) + `",
+				"echForce": true
+			}`,
+			Parser: parse,
+			Output: &tls.Config{
+				ServerName:    "www.example.com",
+				EchConfigList: configList,
+				EchForce:      true,
+				Certificate:   []*tls.Certificate{},
+			},
+		},
+		{
+			Input: `{
+				"echConfigList": ` + pemLines("ECH CONFIGS", configList) + `,
+				"echQueryDomain": "www.example.com"
+			}`,
+			Parser: parse,
+			Output: &tls.Config{
+				EchConfigList:  configList,
+				EchQueryDomain: "www.example.com",
+				Certificate:    []*tls.Certificate{},
+			},
+		},
+		{
+			Input: `{
+				"echServerKeys": ` + pemLines("ECH KEYS", keys) + `
+			}`,
+			Parser: parse,
+			Output: &tls.Config{
+				EchServerKeys: keys,
+				Certificate:   []*tls.Certificate{},
+			},
+		},
+	})
+
+	for _, input := range []string{
+		`{"echForce": true}`,
+		`{"echQueryDomain": "www.example.com", "fingerprint": "chrome"}`,
+		`{"echConfigList": "` + base64.StdEncoding.EncodeToString(configList) + `", "maxVersion": "1.2"}`,
+		`{"echServerKeys": ` + pemLines("ECH CONFIGS", configList) + `}`,
+	} {
+		if _, err := parse(input); err == nil {
+			t.Error("expected error for ", input)
+		}
+	}
+}
+
+func TestTLSConfigClientAuth(t *testing.T) {
+	parse := func(s string) (proto.Message, error) {
+		config := new(TLSConfig)
+		if err := json.Unmarshal([]byte(s), config); err != nil {
+			return nil, err
+		}
+		return config.Build()
+	}
+
+	runMultiTestCase(t, []TestCase{
+		{
+			Input: `{
+				"clientAuth": "verify",
+				"certificates": [{
+					"certificate": ["ca"],
+					"usage": "verify"
+				}]
+			}`,
+			Parser: parse,
+			Output: &tls.Config{
+				Certificate: []*tls.Certificate{{
+					Certificate:    []byte("ca"),
+					Usage:          tls.Certificate_AUTHORITY_VERIFY,
+					OneTimeLoading: true,
+				}},
+				ClientAuth: tls.ClientAuth_VERIFY,
+			},
+		},
+		{
+			Input: `{
+				"clientAuth": "require"
+			}`,
+			Parser: parse,
+			Output: &tls.Config{
+				Certificate: []*tls.Certificate{},
+				ClientAuth:  tls.ClientAuth_REQUIRE,
+			},
+		},
 	})
+
+	for _, input := range []string{
+		`{"clientAuth": "request"}`,
+		`{"clientAuth": "verify", "certificates": [{"certificate": ["cert"], "key": ["key"]}]}`,
+		`{"clientAuth": "optional"}`,
+	} {
+		if _, err := parse(input); err == nil {
+			t.Error("expected error for ", input)
+		}
+	}
 }
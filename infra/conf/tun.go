@@ -0,0 +1,36 @@
+package conf
+
+import (
+	"github.com/xtls/xray-core/proxy/tun"
+	"google.golang.org/protobuf/proto"
+)
+
+type TunConfig struct {
+	Name           string   `json:"name"`
+	MTU            uint32   `json:"mtu"`
+	Address        []string `json:"address"`
+	UserLevel      uint32   `json:"userLevel"`
+	AutoRoute      bool     `json:"autoRoute"`
+	Route          []string `json:"route"`
+	ExcludeAddress []string `json:"excludeAddress"`
+	ExcludeMark    uint32   `json:"excludeMark"`
+	RouteTable     uint32   `json:"routeTable"`
+}
+
+func (c *TunConfig) Build() (proto.Message, error) {
+	address := c.Address
+	if len(address) == 0 {
+		address = []string{"172.19.0.1/30"}
+	}
+	return &tun.Config{
+		Name:           c.Name,
+		Mtu:            c.MTU,
+		Address:        address,
+		UserLevel:      c.UserLevel,
+		AutoRoute:      c.AutoRoute,
+		Route:          c.Route,
+		ExcludeAddress: c.ExcludeAddress,
+		ExcludeMark:    c.ExcludeMark,
+		RouteTable:     c.RouteTable,
+	}, nil
+}
@@ -0,0 +1,47 @@
+package conf_test
+
+import (
+	"testing"
+
+	. "github.com/xtls/xray-core/infra/conf"
+	"github.com/xtls/xray-core/proxy/tun"
+)
+
+func TestTunConfig(t *testing.T) {
+	creator := func() Buildable {
+		return new(TunConfig)
+	}
+
+	runMultiTestCase(t, []TestCase{
+		{
+			Input:  `{}`,
+			Parser: loadJSON(creator),
+			Output: &tun.Config{
+				Address: []string{"172.19.0.1/30"},
+			},
+		},
+		{
+			Input: `{
+				"name": "xray0",
+				"mtu": 9000,
+				"address": ["172.19.0.1/30", "fdfe:dcba:9876::1/126"],
+				"userLevel": 1,
+				"autoRoute": true,
+				"excludeAddress": ["192.168.0.0/16"],
+				"excludeMark": 255,
+				"routeTable": 100
+			}`,
+			Parser: loadJSON(creator),
+			Output: &tun.Config{
+				Name:           "xray0",
+				Mtu:            9000,
+				Address:        []string{"172.19.0.1/30", "fdfe:dcba:9876::1/126"},
+				UserLevel:      1,
+				AutoRoute:      true,
+				ExcludeAddress: []string{"192.168.0.0/16"},
+				ExcludeMark:    255,
+				RouteTable:     100,
+			},
+		},
+	})
+}
@@ -19,6 +19,7 @@ import (
 
 var (
 	inboundConfigLoader = NewJSONConfigLoader(ConfigCreatorCache{
+		"dns":           func() interface{} { return new(DNSInboundConfig) },
 		"dokodemo-door": func() interface{} { return new(DokodemoConfig) },
 		"http":          func() interface{} { return new(HTTPServerConfig) },
 		"shadowsocks":   func() interface{} { return new(ShadowsocksServerConfig) },
@@ -26,6 +27,7 @@ var (
 		"vless":         func() interface{} { return new(VLessInboundConfig) },
 		"vmess":         func() interface{} { return new(VMessInboundConfig) },
 		"trojan":        func() interface{} { return new(TrojanServerConfig) },
+		"tun":           func() interface{} { return new(TunConfig) },
 		"wireguard":     func() interface{} { return &WireGuardConfig{IsClient: false} },
 	}, "protocol", "settings")
 
@@ -181,13 +183,16 @@ type InboundDetourConfig struct {
 func (c *InboundDetourConfig) Build() (*core.InboundHandlerConfig, error) {
 	receiverSettings := &proxyman.ReceiverConfig{}
 
-	if c.ListenOn == nil {
+	switch {
+	case strings.EqualFold(c.Protocol, "tun"):
+		// TUN inbound reads from its device, and listens on no port.
+	case c.ListenOn == nil:
 		// Listen on anyip, must set PortList
 		if c.PortList == nil {
 			return nil, newError("Listen on AnyIP but no Port(s) set in InboundDetour.")
 		}
 		receiverSettings.PortList = c.PortList.Build()
-	} else {
+	default:
 		// Listen on specific IP or Unix Domain Socket
 		receiverSettings.Listen = c.ListenOn.Build()
 		listenDS := c.ListenOn.Family().IsDomain() && (filepath.IsAbs(c.ListenOn.Domain()) || c.ListenOn.Domain()[0] == '@')
@@ -324,6 +329,15 @@ func (c *OutboundDetourConfig) Build() (*core.OutboundHandlerConfig, error) {
 			return nil, err
 		}
 		senderSettings.StreamSettings = ss
+
+		// QUIC datagrams may be lost or reordered, which only protocols writing each UDP packet as a whole survive.
+		if qs := c.StreamSetting.QUICSettings; qs != nil && qs.Datagram && ss.ProtocolName == "quic" {
+			switch strings.ToLower(c.Protocol) {
+			case "vless", "trojan":
+			default:
+				return nil, newError(`QUIC "datagram" is not supported by `, c.Protocol, ", only by VLESS and Trojan")
+			}
+		}
 	}
 
 	if c.ProxySettings != nil {
@@ -417,6 +431,7 @@ type Config struct {
 	FakeDNS          *FakeDNSConfig          `json:"fakeDns"`
 	Observatory      *ObservatoryConfig      `json:"observatory"`
 	BurstObservatory *BurstObservatoryConfig `json:"burstObservatory"`
+	Quota            *QuotaConfig            `json:"quota"`
 }
 
 func (c *Config) findInboundTag(tag string) int {
@@ -485,6 +500,10 @@ func (c *Config) Override(o *Config, fn string) {
 		c.BurstObservatory = o.BurstObservatory
 	}
 
+	if o.Quota != nil {
+		c.Quota = o.Quota
+	}
+
 	// deprecated attrs... keep them for now
 	if o.InboundConfig != nil {
 		c.InboundConfig = o.InboundConfig
@@ -557,6 +576,9 @@ func applyTransportConfig(s *StreamConfig, t *TransportConfig) {
 	if s.HTTPUPGRADESettings == nil {
 		s.HTTPUPGRADESettings = t.HTTPUPGRADEConfig
 	}
+	if s.SplitHTTPSettings == nil {
+		s.SplitHTTPSettings = t.SplitHTTPConfig
+	}
 }
 
 // Build implements Buildable.
@@ -597,7 +619,11 @@ func (c *Config) Build() (*core.Config, error) {
 
 	var logConfMsg *serial.TypedMessage
 	if c.LogConfig != nil {
-		logConfMsg = serial.ToTypedMessage(c.LogConfig.Build())
+		logConf, err := c.LogConfig.Build()
+		if err != nil {
+			return nil, newError("failed to parse log config").Base(err)
+		}
+		logConfMsg = serial.ToTypedMessage(logConf)
 	} else {
 		logConfMsg = serial.ToTypedMessage(DefaultLogConfig())
 	}
@@ -661,6 +687,14 @@ func (c *Config) Build() (*core.Config, error) {
 		config.App = append(config.App, serial.ToTypedMessage(r))
 	}
 
+	if c.Quota != nil {
+		r, err := c.Quota.Build()
+		if err != nil {
+			return nil, newError("failed to parse quota config").Base(err)
+		}
+		config.App = append(config.App, serial.ToTypedMessage(r))
+	}
+
 	var inbounds []InboundDetourConfig
 
 	if c.InboundConfig != nil {
@@ -24,5 +24,14 @@ var CmdAPI = &base.Command{
 		cmdAddRules,
 		cmdRemoveRules,
 		cmdSourceIpBlock,
+		cmdSetRateLimit,
+		cmdGetOnlineIPs,
+		cmdListConnections,
+		cmdCloseConnections,
+		cmdListFakeDNS,
+		cmdFlushFakeDNS,
+		cmdListDNSQueries,
+		cmdListDNSServers,
+		cmdReloadConfig,
 	},
 }
@@ -0,0 +1,39 @@
+package api
+
+import (
+	handlerService "github.com/xtls/xray-core/app/proxyman/command"
+	"github.com/xtls/xray-core/main/commands/base"
+)
+
+var cmdReloadConfig = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api reload [--server=127.0.0.1:8080]",
+	Short:       "Reload config files",
+	Long: `
+Make Xray re-read its config files and apply the changes in place,
+the same as sending SIGHUP to the process. Inbounds and outbounds
+which are not changed keep their connections.
+Arguments:
+	-s, -server 
+		The API server address. Default 127.0.0.1:8080
+	-t, -timeout
+		Timeout seconds to call API. Default 3
+`,
+	Run: executeReloadConfig,
+}
+
+func executeReloadConfig(cmd *base.Command, args []string) {
+	setSharedFlags(cmd)
+	cmd.Flag.Parse(args)
+
+	conn, ctx, close := dialAPIServer()
+	defer close()
+
+	client := handlerService.NewHandlerServiceClient(conn)
+	r := &handlerService.ReloadConfigRequest{}
+	resp, err := client.ReloadConfig(ctx, r)
+	if err != nil {
+		base.Fatalf("failed to reload config: %s", err)
+	}
+	showJSONResponse(resp)
+}
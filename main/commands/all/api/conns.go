@@ -0,0 +1,49 @@
+package api
+
+import (
+	dispatcherService "github.com/xtls/xray-core/app/dispatcher/command"
+	"github.com/xtls/xray-core/main/commands/base"
+)
+
+var cmdListConnections = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api conns [--server=127.0.0.1:8080] [-inbound ''] [-email '']",
+	Short:       "List active connections",
+	Long: `
+List the active connections with their inbound, user, source, target,
+outbound, start time and traffic so far.
+Arguments:
+	-s, -server 
+		The API server address. Default 127.0.0.1:8080
+	-t, -timeout
+		Timeout seconds to call API. Default 3
+	-inbound
+		Only list connections of the inbound with the given tag.
+	-email
+		Only list connections of the user with the given email.
+Example:
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 -email "love@xray.com"
+`,
+	Run: executeListConnections,
+}
+
+func executeListConnections(cmd *base.Command, args []string) {
+	setSharedFlags(cmd)
+	inbound := cmd.Flag.String("inbound", "", "")
+	email := cmd.Flag.String("email", "", "")
+	cmd.Flag.Parse(args)
+
+	conn, ctx, close := dialAPIServer()
+	defer close()
+
+	client := dispatcherService.NewConnectionServiceClient(conn)
+	r := &dispatcherService.ListConnectionsRequest{
+		InboundTag: *inbound,
+		Email:      *email,
+	}
+	resp, err := client.ListConnections(ctx, r)
+	if err != nil {
+		base.Fatalf("failed to list connections: %s", err)
+	}
+	showJSONResponse(resp)
+}
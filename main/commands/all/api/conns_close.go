@@ -0,0 +1,59 @@
+package api
+
+import (
+	"strconv"
+
+	dispatcherService "github.com/xtls/xray-core/app/dispatcher/command"
+	"github.com/xtls/xray-core/main/commands/base"
+)
+
+var cmdCloseConnections = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api connclose [--server=127.0.0.1:8080] [-email ''] [id]...",
+	Short:       "Close active connections",
+	Long: `
+Close active connections by their IDs, as listed by "{{.Exec}} api conns",
+or all connections of a user.
+Arguments:
+	-s, -server 
+		The API server address. Default 127.0.0.1:8080
+	-t, -timeout
+		Timeout seconds to call API. Default 3
+	-email
+		Close all connections of the user with the given email.
+Example:
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 1234567 7654321
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 -email "love@xray.com"
+`,
+	Run: executeCloseConnections,
+}
+
+func executeCloseConnections(cmd *base.Command, args []string) {
+	setSharedFlags(cmd)
+	email := cmd.Flag.String("email", "", "")
+	cmd.Flag.Parse(args)
+
+	r := &dispatcherService.CloseConnectionsRequest{
+		Email: *email,
+	}
+	for _, arg := range cmd.Flag.Args() {
+		id, err := strconv.ParseUint(arg, 10, 32)
+		if err != nil {
+			base.Fatalf("invalid connection ID %s: %s", arg, err)
+		}
+		r.Id = append(r.Id, uint32(id))
+	}
+	if len(r.Id) == 0 && len(r.Email) == 0 {
+		base.Fatalf("no connection ID or email specified")
+	}
+
+	conn, ctx, close := dialAPIServer()
+	defer close()
+
+	client := dispatcherService.NewConnectionServiceClient(conn)
+	resp, err := client.CloseConnections(ctx, r)
+	if err != nil {
+		base.Fatalf("failed to close connections: %s", err)
+	}
+	showJSONResponse(resp)
+}
@@ -0,0 +1,54 @@
+package api
+
+import (
+	dnsService "github.com/xtls/xray-core/app/dns/command"
+	"github.com/xtls/xray-core/main/commands/base"
+)
+
+var cmdListDNSQueries = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api dnsqueries [--server=127.0.0.1:8080] [-domain ''] [-dnsserver ''] [-limit 0]",
+	Short:       "List recent DNS queries",
+	Long: `
+List recent DNS queries, the most recent first, with the client, the name
+server that answered, the latency, whether the answer was cached, and the
+answers.
+Arguments:
+	-s, -server 
+		The API server address. Default 127.0.0.1:8080
+	-t, -timeout
+		Timeout seconds to call API. Default 3
+	-domain
+		Only list queries of domains containing the given string.
+	-dnsserver
+		Only list queries answered by the given name server, e.g. "UDP//8.8.8.8:53" or "hosts".
+	-limit
+		Maximum number of queries to list. Default 0, unlimited.
+Example:
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 -domain "example.com" -limit 10
+`,
+	Run: executeListDNSQueries,
+}
+
+func executeListDNSQueries(cmd *base.Command, args []string) {
+	setSharedFlags(cmd)
+	domain := cmd.Flag.String("domain", "", "")
+	dnsServer := cmd.Flag.String("dnsserver", "", "")
+	limit := cmd.Flag.Uint("limit", 0, "")
+	cmd.Flag.Parse(args)
+
+	conn, ctx, close := dialAPIServer()
+	defer close()
+
+	client := dnsService.NewDNSServiceClient(conn)
+	r := &dnsService.ListQueriesRequest{
+		Domain: *domain,
+		Server: *dnsServer,
+		Limit:  uint32(*limit),
+	}
+	resp, err := client.ListQueries(ctx, r)
+	if err != nil {
+		base.Fatalf("failed to list DNS queries: %s", err)
+	}
+	showJSONResponse(resp)
+}
@@ -0,0 +1,39 @@
+package api
+
+import (
+	dnsService "github.com/xtls/xray-core/app/dns/command"
+	"github.com/xtls/xray-core/main/commands/base"
+)
+
+var cmdListDNSServers = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api dnsservers [--server=127.0.0.1:8080]",
+	Short:       "List DNS name servers and their health",
+	Long: `
+List the name servers of the DNS module, with their success rate, average
+latency, and whether they are skipped as unhealthy.
+Arguments:
+	-s, -server 
+		The API server address. Default 127.0.0.1:8080
+	-t, -timeout
+		Timeout seconds to call API. Default 3
+Example:
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080
+`,
+	Run: executeListDNSServers,
+}
+
+func executeListDNSServers(cmd *base.Command, args []string) {
+	setSharedFlags(cmd)
+	cmd.Flag.Parse(args)
+
+	conn, ctx, close := dialAPIServer()
+	defer close()
+
+	client := dnsService.NewDNSServiceClient(conn)
+	resp, err := client.ListServers(ctx, &dnsService.ListServersRequest{})
+	if err != nil {
+		base.Fatalf("failed to list DNS servers: %s", err)
+	}
+	showJSONResponse(resp)
+}
@@ -0,0 +1,44 @@
+package api
+
+import (
+	dnsService "github.com/xtls/xray-core/app/dns/command"
+	"github.com/xtls/xray-core/main/commands/base"
+)
+
+var cmdListFakeDNS = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api fakedns [--server=127.0.0.1:8080] [-domain '']",
+	Short:       "List fake DNS mappings",
+	Long: `
+List the mappings between domains and fake IPs, the most recently used first.
+Arguments:
+	-s, -server 
+		The API server address. Default 127.0.0.1:8080
+	-t, -timeout
+		Timeout seconds to call API. Default 3
+	-domain
+		Only list mappings of domains containing the given string.
+Example:
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 -domain "example.com"
+`,
+	Run: executeListFakeDNS,
+}
+
+func executeListFakeDNS(cmd *base.Command, args []string) {
+	setSharedFlags(cmd)
+	domain := cmd.Flag.String("domain", "", "")
+	cmd.Flag.Parse(args)
+
+	conn, ctx, close := dialAPIServer()
+	defer close()
+
+	client := dnsService.NewDNSServiceClient(conn)
+	r := &dnsService.ListFakeDNSRequest{
+		Domain: *domain,
+	}
+	resp, err := client.ListFakeDNS(ctx, r)
+	if err != nil {
+		base.Fatalf("failed to list fake DNS mappings: %s", err)
+	}
+	showJSONResponse(resp)
+}
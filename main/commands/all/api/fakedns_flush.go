@@ -0,0 +1,38 @@
+package api
+
+import (
+	dnsService "github.com/xtls/xray-core/app/dns/command"
+	"github.com/xtls/xray-core/main/commands/base"
+)
+
+var cmdFlushFakeDNS = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api fakednsflush [--server=127.0.0.1:8080]",
+	Short:       "Flush fake DNS mappings",
+	Long: `
+Remove all mappings between domains and fake IPs.
+Arguments:
+	-s, -server 
+		The API server address. Default 127.0.0.1:8080
+	-t, -timeout
+		Timeout seconds to call API. Default 3
+Example:
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080
+`,
+	Run: executeFlushFakeDNS,
+}
+
+func executeFlushFakeDNS(cmd *base.Command, args []string) {
+	setSharedFlags(cmd)
+	cmd.Flag.Parse(args)
+
+	conn, ctx, close := dialAPIServer()
+	defer close()
+
+	client := dnsService.NewDNSServiceClient(conn)
+	resp, err := client.FlushFakeDNS(ctx, &dnsService.FlushFakeDNSRequest{})
+	if err != nil {
+		base.Fatalf("failed to flush fake DNS mappings: %s", err)
+	}
+	showJSONResponse(resp)
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	handlerService "github.com/xtls/xray-core/app/proxyman/command"
+	"github.com/xtls/xray-core/main/commands/base"
+)
+
+var cmdGetOnlineIPs = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api onlineips [--server=127.0.0.1:8080] [-email '']",
+	Short:       "List online IPs of users",
+	Long: `
+List the source IPs with active connections of users, counted across all
+inbounds.
+Arguments:
+	-s, -server 
+		The API server address. Default 127.0.0.1:8080
+	-t, -timeout
+		Timeout seconds to call API. Default 3
+	-email
+		Email of the user. If empty, all online users are listed.
+Example:
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 -email "love@xray.com"
+`,
+	Run: executeGetOnlineIPs,
+}
+
+func executeGetOnlineIPs(cmd *base.Command, args []string) {
+	setSharedFlags(cmd)
+	email := cmd.Flag.String("email", "", "")
+	cmd.Flag.Parse(args)
+
+	conn, ctx, close := dialAPIServer()
+	defer close()
+
+	client := handlerService.NewHandlerServiceClient(conn)
+	r := &handlerService.GetOnlineIPsRequest{
+		Email: *email,
+	}
+	resp, err := client.GetOnlineIPs(ctx, r)
+	if err != nil {
+		base.Fatalf("failed to get online IPs: %s", err)
+	}
+	showJSONResponse(resp)
+}
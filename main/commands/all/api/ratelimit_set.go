@@ -0,0 +1,58 @@
+package api
+
+import (
+	handlerService "github.com/xtls/xray-core/app/proxyman/command"
+	"github.com/xtls/xray-core/main/commands/base"
+)
+
+var cmdSetRateLimit = &base.Command{
+	CustomFlags: true,
+	UsageLine:   "{{.Exec}} api ratelimit [--server=127.0.0.1:8080] [-email ''] [-level 0] [-uplink 0] [-downlink 0]",
+	Short:       "Set bandwidth limit",
+	Long: `
+Set the bandwidth limit of a user or a policy level. The limit of a user
+is shared by all its connections, and takes effect on existing connections
+immediately.
+Arguments:
+	-s, -server 
+		The API server address. Default 127.0.0.1:8080
+	-t, -timeout
+		Timeout seconds to call API. Default 3
+	-email
+		Email of the user. If empty, the limit applies to the level.
+	-level
+		Policy level. Default 0
+	-uplink
+		Uplink speed limit in bytes per second. 0 for unlimited.
+	-downlink
+		Downlink speed limit in bytes per second. 0 for unlimited.
+Example:
+	{{.Exec}} {{.LongName}} --server=127.0.0.1:8080 -email "love@xray.com" -uplink 1048576 -downlink 10485760
+`,
+	Run: executeSetRateLimit,
+}
+
+func executeSetRateLimit(cmd *base.Command, args []string) {
+	setSharedFlags(cmd)
+	email := cmd.Flag.String("email", "", "")
+	level := cmd.Flag.Uint("level", 0, "")
+	uplink := cmd.Flag.Uint64("uplink", 0, "")
+	downlink := cmd.Flag.Uint64("downlink", 0, "")
+	cmd.Flag.Parse(args)
+
+	conn, ctx, close := dialAPIServer()
+	defer close()
+
+	client := handlerService.NewHandlerServiceClient(conn)
+	r := &handlerService.SetRateLimitRequest{
+		Email:    *email,
+		Level:    uint32(*level),
+		Uplink:   *uplink,
+		Downlink: *downlink,
+	}
+	resp, err := client.SetRateLimit(ctx, r)
+	if err != nil {
+		base.Fatalf("failed to set rate limit: %s", err)
+	}
+	showJSONResponse(resp)
+}
@@ -47,14 +47,14 @@ func executeConvert(cmd *base.Command, args []string) {
 		common.Must(err)
 		c, err := serial.DecodeJSONConfig(r)
 		if err != nil {
-			base.Fatalf(err.Error())
+			base.Fatalf("%s", err)
 		}
 		conf.Override(c, arg)
 	}
 
 	pbConfig, err := conf.Build()
 	if err != nil {
-		base.Fatalf(err.Error())
+		base.Fatalf("%s", err)
 	}
 
 	bytesConfig, err := proto.Marshal(pbConfig)
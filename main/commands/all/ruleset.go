@@ -0,0 +1,53 @@
+package all
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/xtls/xray-core/app/router"
+	"github.com/xtls/xray-core/main/commands/base"
+	"google.golang.org/protobuf/proto"
+)
+
+var cmdRuleSet = &base.Command{
+	UsageLine: `{{.Exec}} ruleset [-i "input.txt"] [-o "output.pb"]`,
+	Short:     `Compile a rule-set file into the binary format`,
+	Long: `
+Compile a rule-set file of the text or JSON format into the binary format,
+which loads faster.
+
+The input format is guessed from the file extension: JSON for ".json", and
+text otherwise.
+
+Example:
+
+    {{.Exec}} ruleset -i "ads.txt" -o "ads.pb"
+`,
+}
+
+func init() {
+	cmdRuleSet.Run = executeRuleSet // break init loop
+}
+
+var (
+	inputRuleSet  = cmdRuleSet.Flag.String("i", "", "")
+	outputRuleSet = cmdRuleSet.Flag.String("o", "", "")
+)
+
+func executeRuleSet(cmd *base.Command, args []string) {
+	if len(*inputRuleSet) == 0 || len(*outputRuleSet) == 0 {
+		base.Fatalf("both input and output files are required")
+	}
+	ruleSet, err := router.LoadRuleSet(&router.RuleSetFile{Path: *inputRuleSet})
+	if err != nil {
+		base.Fatalf("failed to load rule-set: %s", err)
+	}
+	b, err := proto.Marshal(ruleSet)
+	if err != nil {
+		base.Fatalf("failed to marshal rule-set: %s", err)
+	}
+	if err := os.WriteFile(*outputRuleSet, b, 0o644); err != nil {
+		base.Fatalf("failed to write rule-set: %s", err)
+	}
+	fmt.Println("Compiled", len(ruleSet.Domain), "domains and", len(ruleSet.Cidr), "IPs")
+}
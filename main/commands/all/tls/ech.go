@@ -20,6 +20,9 @@ Generate TLS-ECH certificates.
 
 Set serverName to your custom string: {{.Exec}} tls ech --serverName (string)
 Generate into json format: {{.Exec}} tls ech --json
+
+Put the ECH CONFIGS in "echConfigList" of the TLS settings of clients, and the
+ECH KEYS in "echServerKeys" of the TLS settings of the server.
 `, // Enable PQ signature schemes: {{.Exec}} tls ech --pq-signature-schemes-enabled
 }
 
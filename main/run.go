@@ -53,6 +53,9 @@ var (
 	test        = cmdRun.Flag.Bool("test", false, "Test config file only, without launching Xray server.")
 	format      = cmdRun.Flag.String("format", "auto", "Format of input file.")
 
+	// cliConfigFiles keeps the config files given in command line, as configFiles is extended with files in confdir.
+	cliConfigFiles cmdarg.Arg
+
 	/* We have to do this here because Golang's Test will also need to parse flag, before
 	 * main func in this file is run.
 	 */
@@ -73,6 +76,7 @@ func executeRun(cmd *base.Command, args []string) {
 	}
 
 	printVersion()
+	cliConfigFiles = append(cmdarg.Arg{}, configFiles...)
 	server, err := startXray()
 	if err != nil {
 		fmt.Println("Failed to start:", err)
@@ -103,8 +107,16 @@ func executeRun(cmd *base.Command, args []string) {
 
 	{
 		osSignals := make(chan os.Signal, 1)
-		signal.Notify(osSignals, os.Interrupt, syscall.SIGTERM)
-		<-osSignals
+		signal.Notify(osSignals, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+		for sig := range osSignals {
+			if sig != syscall.SIGHUP {
+				break
+			}
+			newError("SIGHUP received, reloading config").AtWarning().WriteToLog()
+			if err := server.ReloadConfig(); err != nil {
+				newError("failed to reload config").Base(err).AtError().WriteToLog()
+			}
+		}
 	}
 }
 
@@ -210,7 +222,7 @@ func getConfigFormat() string {
 	return f
 }
 
-func startXray() (core.Server, error) {
+func startXray() (*core.Instance, error) {
 	configFiles := getConfigFilePath(true)
 
 	// config, err := core.LoadConfig(getConfigFormat(), configFiles[0], configFiles)
@@ -224,6 +236,19 @@ func startXray() (core.Server, error) {
 	if err != nil {
 		return nil, newError("failed to create server").Base(err)
 	}
+	server.SetConfigLoader(reloadConfig)
 
 	return server, nil
 }
+
+// reloadConfig re-reads the config files, including the ones in confdir, for reloading a running server.
+func reloadConfig() (*core.Config, error) {
+	configFiles = append(cmdarg.Arg{}, cliConfigFiles...)
+	files := getConfigFilePath(false)
+
+	c, err := core.LoadConfig(getConfigFormat(), files)
+	if err != nil {
+		return nil, newError("failed to load config files: [", files.String(), "]").Base(err)
+	}
+	return c, nil
+}
@@ -44,6 +44,8 @@ type ownLinkVerifier interface {
 
 type Handler struct {
 	client          dns.Client
+	contextClient   dns.ContextClient
+	rawClient       dns.RawClient
 	fdns            dns.FakeDNSEngine
 	ownLinkVerifier ownLinkVerifier
 	server          net.Destination
@@ -58,11 +60,21 @@ func (h *Handler) Init(config *Config, dnsClient dns.Client, policyManager polic
 	if v, ok := dnsClient.(ownLinkVerifier); ok {
 		h.ownLinkVerifier = v
 	}
+	if v, ok := dnsClient.(dns.ContextClient); ok {
+		h.contextClient = v
+	}
 
 	if config.Server != nil {
 		h.server = config.Server.AsDestination()
 	}
 	h.nonIPQuery = config.Non_IPQuery
+	if h.nonIPQuery == "resolve" {
+		if v, ok := dnsClient.(dns.RawClient); ok {
+			h.rawClient = v
+		} else {
+			newError("DNS client does not resolve non-IP queries, dropping them").AtWarning().WriteToLog()
+		}
+	}
 	return nil
 }
 
@@ -85,12 +97,8 @@ func parseIPQuery(b []byte) (r bool, domain string, id uint16, qType dnsmessage.
 		return
 	}
 	qType = q.Type
-	if qType != dnsmessage.TypeA && qType != dnsmessage.TypeAAAA {
-		return
-	}
-
 	domain = q.Name.String()
-	r = true
+	r = qType == dnsmessage.TypeA || qType == dnsmessage.TypeAAAA
 	return
 }
 
@@ -116,6 +124,7 @@ func (h *Handler) Process(ctx context.Context, link *transport.Link, d internet.
 	}
 
 	newError("handling DNS traffic to ", dest).WriteToLog(session.ExportIDToError(ctx))
+	queryCtx := ctx
 
 	conn := &outboundConn{
 		dialer: func() (stat.Connection, error) {
@@ -181,9 +190,11 @@ func (h *Handler) Process(ctx context.Context, link *transport.Link, d internet.
 			if !h.isOwnLink(ctx) {
 				isIPQuery, domain, id, qType := parseIPQuery(b.Bytes())
 				if isIPQuery {
-					go h.handleIPQuery(id, qType, domain, writer)
+					go h.handleIPQuery(queryCtx, id, qType, domain, writer)
+				} else if h.rawClient != nil && len(domain) > 0 {
+					go h.handleRawQuery(queryCtx, id, qType, domain, writer)
 				}
-				if isIPQuery || h.nonIPQuery == "drop" || qType == 65 {
+				if isIPQuery || h.nonIPQuery == "drop" || h.nonIPQuery == "resolve" || qType == 65 {
 					b.Release()
 					continue
 				}
@@ -221,7 +232,26 @@ func (h *Handler) Process(ctx context.Context, link *transport.Link, d internet.
 	return nil
 }
 
-func (h *Handler) handleIPQuery(id uint16, qType dnsmessage.Type, domain string, writer dns_proto.MessageWriter) {
+// lookupIP looks up IPs with the context of the request, if the DNS client supports it.
+func (h *Handler) lookupIP(ctx context.Context, domain string, option dns.IPOption) ([]net.IP, error) {
+	if h.contextClient != nil {
+		return h.contextClient.LookupIPWithContext(ctx, domain, option)
+	}
+	return h.client.LookupIP(domain, option)
+}
+
+func (h *Handler) handleIPQuery(ctx context.Context, id uint16, qType dnsmessage.Type, domain string, writer dns_proto.MessageWriter) {
+	b := h.answerIPQuery(ctx, id, qType, domain)
+	if b == nil {
+		return
+	}
+	if err := writer.WriteMessage(b); err != nil {
+		newError("write IP answer").Base(err).WriteToLog()
+	}
+}
+
+// answerIPQuery resolves an A or AAAA query and returns the packed answer, or nil if there is nothing to answer.
+func (h *Handler) answerIPQuery(ctx context.Context, id uint16, qType dnsmessage.Type, domain string) *buf.Buffer {
 	var ips []net.IP
 	var err error
 
@@ -229,13 +259,13 @@ func (h *Handler) handleIPQuery(id uint16, qType dnsmessage.Type, domain string,
 
 	switch qType {
 	case dnsmessage.TypeA:
-		ips, err = h.client.LookupIP(domain, dns.IPOption{
+		ips, err = h.lookupIP(ctx, domain, dns.IPOption{
 			IPv4Enable: true,
 			IPv6Enable: false,
 			FakeEnable: true,
 		})
 	case dnsmessage.TypeAAAA:
-		ips, err = h.client.LookupIP(domain, dns.IPOption{
+		ips, err = h.lookupIP(ctx, domain, dns.IPOption{
 			IPv4Enable: false,
 			IPv6Enable: true,
 			FakeEnable: true,
@@ -245,7 +275,7 @@ func (h *Handler) handleIPQuery(id uint16, qType dnsmessage.Type, domain string,
 	rcode := dns.RCodeFromError(err)
 	if rcode == 0 && len(ips) == 0 && !errors.AllEqual(dns.ErrEmptyResponse, errors.Cause(err)) {
 		newError("ip query").Base(err).WriteToLog()
-		return
+		return nil
 	}
 
 	if fkr0, ok := h.fdns.(dns.FakeDNSEngineRev0); ok && len(ips) > 0 && fkr0.IsIPInIPPool(net.IPAddress(ips[0])) {
@@ -298,13 +328,76 @@ func (h *Handler) handleIPQuery(id uint16, qType dnsmessage.Type, domain string,
 	if err != nil {
 		newError("pack message").Base(err).WriteToLog()
 		b.Release()
-		return
+		return nil
 	}
 	b.Resize(0, int32(len(msgBytes)))
+	return b
+}
 
+func (h *Handler) handleRawQuery(ctx context.Context, id uint16, qType dnsmessage.Type, domain string, writer dns_proto.MessageWriter) {
+	b := h.answerRawQuery(ctx, id, qType, domain)
+	if b == nil {
+		return
+	}
 	if err := writer.WriteMessage(b); err != nil {
-		newError("write IP answer").Base(err).WriteToLog()
+		newError("write ", qType, " answer").Base(err).WriteToLog()
+	}
+}
+
+// answerRawQuery resolves a query of any type by the raw client and returns the packed answer.
+func (h *Handler) answerRawQuery(ctx context.Context, id uint16, qType dnsmessage.Type, domain string) *buf.Buffer {
+	resp := newResponse(id, qType, domain)
+	msg, err := h.rawClient.LookupRaw(ctx, domain, qType)
+	if err != nil {
+		newError(qType, " query for ", domain).Base(err).WriteToLog()
+		resp.RCode = dnsmessage.RCodeServerFailure
+	} else {
+		resp.RCode = msg.RCode
+		resp.Answers = msg.Answers
+		resp.Authorities = msg.Authorities
+		for _, r := range msg.Additionals {
+			// The OPT record is hop by hop (RFC6891 6.1.1).
+			if r.Header.Type != dnsmessage.TypeOPT {
+				resp.Additionals = append(resp.Additionals, r)
+			}
+		}
+	}
+	return packResponse(resp)
+}
+
+func newResponse(id uint16, qType dnsmessage.Type, domain string) *dnsmessage.Message {
+	return &dnsmessage.Message{
+		Header: dnsmessage.Header{
+			ID:                 id,
+			RecursionAvailable: true,
+			RecursionDesired:   true,
+			Response:           true,
+		},
+		Questions: []dnsmessage.Question{{
+			Name:  dnsmessage.MustNewName(domain),
+			Type:  qType,
+			Class: dnsmessage.ClassINET,
+		}},
+	}
+}
+
+func packResponse(resp *dnsmessage.Message) *buf.Buffer {
+	b := buf.New()
+	rawBytes := b.Extend(buf.Size)
+	msgBytes, err := resp.AppendPack(rawBytes[:0])
+	if err == nil && len(msgBytes) > buf.Size {
+		// Too large for the buffer, so let the client retry over TCP (RFC7766 5).
+		resp.Truncated = true
+		resp.Answers, resp.Authorities, resp.Additionals = nil, nil, nil
+		msgBytes, err = resp.AppendPack(rawBytes[:0])
 	}
+	if err != nil {
+		newError("pack message").Base(err).WriteToLog()
+		b.Release()
+		return nil
+	}
+	b.Resize(0, int32(len(msgBytes)))
+	return b
 }
 
 type outboundConn struct {
@@ -68,6 +68,19 @@ func (*staticHandler) ServeDNS(w dns.ResponseWriter, r *dns.Msg) {
 
 		case q.Name == "notexist.google.com." && q.Qtype == dns.TypeAAAA:
 			ans.MsgHdr.Rcode = dns.RcodeNameError
+
+		case q.Name == "google.com." && q.Qtype == dns.TypeMX:
+			rr, err := dns.NewRR("google.com. 300 IN MX 10 smtp.google.com.")
+			common.Must(err)
+			ans.Answer = append(ans.Answer, rr)
+
+		case q.Name == "google.com." && q.Qtype == dns.TypeTXT:
+			rr, err := dns.NewRR(`google.com. 300 IN TXT "v=spf1 -all"`)
+			common.Must(err)
+			ans.Answer = append(ans.Answer, rr)
+
+		case q.Name == "notexist.google.com." && q.Qtype == dns.TypeMX:
+			ans.MsgHdr.Rcode = dns.RcodeNameError
 		}
 	}
 	w.WriteMsg(ans)
@@ -191,6 +204,122 @@ func TestUDPDNSTunnel(t *testing.T) {
 	}
 }
 
+func TestUDPDNSResolveNonIPQuery(t *testing.T) {
+	port := udp.PickPort()
+
+	dnsServer := dns.Server{
+		Addr:    "127.0.0.1:" + port.String(),
+		Net:     "udp",
+		Handler: &staticHandler{},
+		UDPSize: 1200,
+	}
+	defer dnsServer.Shutdown()
+
+	go dnsServer.ListenAndServe()
+	time.Sleep(time.Second)
+
+	serverPort := udp.PickPort()
+	config := &core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&dnsapp.Config{
+				NameServer: []*dnsapp.NameServer{
+					{
+						Address: &net.Endpoint{
+							Network: net.Network_UDP,
+							Address: &net.IPOrDomain{
+								Address: &net.IPOrDomain_Ip{
+									Ip: []byte{127, 0, 0, 1},
+								},
+							},
+							Port: uint32(port),
+						},
+					},
+				},
+			}),
+			serial.ToTypedMessage(&dispatcher.Config{}),
+			serial.ToTypedMessage(&proxyman.OutboundConfig{}),
+			serial.ToTypedMessage(&proxyman.InboundConfig{}),
+			serial.ToTypedMessage(&policy.Config{}),
+		},
+		Inbound: []*core.InboundHandlerConfig{
+			{
+				ProxySettings: serial.ToTypedMessage(&dokodemo.Config{
+					Address:  net.NewIPOrDomain(net.LocalHostIP),
+					Port:     uint32(53),
+					Networks: []net.Network{net.Network_UDP},
+				}),
+				ReceiverSettings: serial.ToTypedMessage(&proxyman.ReceiverConfig{
+					PortList: &net.PortList{Range: []*net.PortRange{net.SinglePortRange(serverPort)}},
+					Listen:   net.NewIPOrDomain(net.LocalHostIP),
+				}),
+			},
+		},
+		Outbound: []*core.OutboundHandlerConfig{
+			{
+				ProxySettings: serial.ToTypedMessage(&dns_proxy.Config{
+					Non_IPQuery: "resolve",
+				}),
+			},
+		},
+	}
+
+	v, err := core.New(config)
+	common.Must(err)
+	common.Must(v.Start())
+	defer v.Close()
+
+	query := func(name string, qType uint16) *dns.Msg {
+		m1 := new(dns.Msg)
+		m1.Id = dns.Id()
+		m1.RecursionDesired = true
+		m1.Question = []dns.Question{{Name: name, Qtype: qType, Qclass: dns.ClassINET}}
+
+		c := new(dns.Client)
+		in, _, err := c.Exchange(m1, "127.0.0.1:"+strconv.Itoa(int(serverPort)))
+		common.Must(err)
+		return in
+	}
+
+	// Query twice, the second answer is from the cache.
+	for i := 0; i < 2; i++ {
+		in := query("google.com.", dns.TypeMX)
+		if len(in.Answer) != 1 {
+			t.Fatal("len(answer): ", len(in.Answer))
+		}
+		rr, ok := in.Answer[0].(*dns.MX)
+		if !ok {
+			t.Fatal("not MX record")
+		}
+		if rr.Mx != "smtp.google.com." || rr.Preference != 10 {
+			t.Error("unexpected MX record: ", rr)
+		}
+		if rr.Hdr.Ttl == 0 || rr.Hdr.Ttl > 300 {
+			t.Error("unexpected TTL: ", rr.Hdr.Ttl)
+		}
+	}
+
+	{
+		in := query("google.com.", dns.TypeTXT)
+		if len(in.Answer) != 1 {
+			t.Fatal("len(answer): ", len(in.Answer))
+		}
+		rr, ok := in.Answer[0].(*dns.TXT)
+		if !ok {
+			t.Fatal("not TXT record")
+		}
+		if r := cmp.Diff(rr.Txt, []string{"v=spf1 -all"}); r != "" {
+			t.Error(r)
+		}
+	}
+
+	{
+		in := query("notexist.google.com.", dns.TypeMX)
+		if in.Rcode != dns.RcodeNameError {
+			t.Error("expected NameError, but got ", in.Rcode)
+		}
+	}
+}
+
 func TestTCPDNSTunnel(t *testing.T) {
 	port := udp.PickPort()
 
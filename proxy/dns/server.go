@@ -0,0 +1,303 @@
+package dns
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/buf"
+	"github.com/xtls/xray-core/common/net"
+	dns_proto "github.com/xtls/xray-core/common/protocol/dns"
+	"github.com/xtls/xray-core/common/session"
+	"github.com/xtls/xray-core/common/signal"
+	"github.com/xtls/xray-core/common/task"
+	"github.com/xtls/xray-core/core"
+	"github.com/xtls/xray-core/features/dns"
+	"github.com/xtls/xray-core/features/policy"
+	"github.com/xtls/xray-core/features/routing"
+	"github.com/xtls/xray-core/transport/internet/reality"
+	"github.com/xtls/xray-core/transport/internet/stat"
+	"github.com/xtls/xray-core/transport/internet/tls"
+	"golang.org/x/net/dns/dnsmessage"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+func init() {
+	common.Must(common.RegisterConfig((*ServerConfig)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
+		s := new(Server)
+		if err := core.RequireFeatures(ctx, func(dnsClient dns.Client, policyManager policy.Manager) error {
+			core.RequireFeatures(ctx, func(fdns dns.FakeDNSEngine) {
+				s.handler.fdns = fdns
+			})
+			return s.Init(config.(*ServerConfig), dnsClient, policyManager)
+		}); err != nil {
+			return nil, err
+		}
+		return s, nil
+	}))
+}
+
+const (
+	defaultPath = "/dns-query"
+	// maxMessageSize is the maximum size of a DNS message over TCP (RFC1035 4.2.2).
+	maxMessageSize = 65535
+)
+
+// Server is an inbound handler that answers DNS over TLS and DNS over HTTPS
+// queries by the DNS module.
+type Server struct {
+	handler Handler
+	mode    ServerConfig_Mode
+	path    string
+	policy  policy.Session
+}
+
+// Init initializes the Server with the given config.
+func (s *Server) Init(config *ServerConfig, dnsClient dns.Client, policyManager policy.Manager) error {
+	if err := s.handler.Init(&Config{UserLevel: config.UserLevel, Non_IPQuery: "resolve"}, dnsClient, policyManager); err != nil {
+		return err
+	}
+	s.mode = config.Mode
+	s.path = config.Path
+	if len(s.path) == 0 {
+		s.path = defaultPath
+	}
+	s.policy = policyManager.ForLevel(config.UserLevel)
+	return nil
+}
+
+// Network implements proxy.Inbound.
+func (s *Server) Network() []net.Network {
+	return []net.Network{net.Network_TCP}
+}
+
+// Process implements proxy.Inbound.
+func (s *Server) Process(ctx context.Context, network net.Network, conn stat.Connection, dispatcher routing.Dispatcher) error {
+	inbound := session.InboundFromContext(ctx)
+	if inbound != nil {
+		inbound.Name = "dns"
+	}
+
+	if s.mode == ServerConfig_HTTP {
+		return s.serveHTTP(ctx, conn)
+	}
+	return s.serveTCP(ctx, conn)
+}
+
+func (s *Server) serveTCP(ctx context.Context, conn stat.Connection) error {
+	reader := dns_proto.NewTCPReader(buf.NewReader(conn))
+	writer := &lockedWriter{writer: &dns_proto.TCPWriter{Writer: buf.NewWriter(conn)}}
+
+	ctx, cancel := context.WithCancel(ctx)
+	timer := signal.CancelAfterInactivity(ctx, cancel, s.policy.Timeouts.ConnectionIdle)
+
+	request := func() error {
+		for {
+			b, err := reader.ReadMessage()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			timer.Update()
+
+			// Queries are answered concurrently, and possibly out of order (RFC7766 6.2.1.1).
+			go func() {
+				resp := s.answer(ctx, b.Bytes())
+				b.Release()
+				if resp == nil {
+					return
+				}
+				if err := writer.WriteMessage(resp); err != nil {
+					newError("failed to write DNS answer").Base(err).WriteToLog(session.ExportIDToError(ctx))
+					cancel()
+					return
+				}
+				timer.Update()
+			}()
+		}
+	}
+
+	if err := task.Run(ctx, request); err != nil {
+		return newError("connection ends").Base(err)
+	}
+	return nil
+}
+
+func (s *Server) serveHTTP(ctx context.Context, conn stat.Connection) error {
+	if negotiatedProtocol(conn) == "h2" {
+		server := &http2.Server{IdleTimeout: s.policy.Timeouts.ConnectionIdle}
+		server.ServeConn(conn, &http2.ServeConnOpts{
+			Context: ctx,
+			Handler: s,
+		})
+		return nil
+	}
+
+	listener := newConnListener(conn)
+	server := &http.Server{
+		// Also accept HTTP/2 with prior knowledge when there is no TLS.
+		Handler:           h2c.NewHandler(s, &http2.Server{IdleTimeout: s.policy.Timeouts.ConnectionIdle}),
+		ReadHeaderTimeout: s.policy.Timeouts.Handshake,
+		IdleTimeout:       s.policy.Timeouts.ConnectionIdle,
+		BaseContext: func(net.Listener) context.Context {
+			return ctx
+		},
+	}
+	if err := server.Serve(listener); err != nil && err != errListenerClosed {
+		return newError("connection ends").Base(err)
+	}
+	return nil
+}
+
+// ServeHTTP answers a DNS over HTTPS query (RFC8484 4.1).
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != s.path {
+		http.NotFound(w, r)
+		return
+	}
+
+	var msg []byte
+	switch r.Method {
+	case http.MethodGet:
+		b, err := base64.RawURLEncoding.DecodeString(r.URL.Query().Get("dns"))
+		if err != nil || len(b) == 0 {
+			http.Error(w, "invalid dns parameter", http.StatusBadRequest)
+			return
+		}
+		msg = b
+	case http.MethodPost:
+		if r.Header.Get("Content-Type") != "application/dns-message" {
+			http.Error(w, "unsupported content type", http.StatusUnsupportedMediaType)
+			return
+		}
+		b, err := io.ReadAll(io.LimitReader(r.Body, maxMessageSize+1))
+		if err != nil {
+			http.Error(w, "failed to read body", http.StatusBadRequest)
+			return
+		}
+		if len(b) > maxMessageSize {
+			http.Error(w, "message too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+		msg = b
+	default:
+		w.Header().Set("Allow", "GET, POST")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	resp := s.answer(r.Context(), msg)
+	if resp == nil {
+		http.Error(w, "invalid dns message", http.StatusBadRequest)
+		return
+	}
+	defer resp.Release()
+
+	w.Header().Set("Content-Type", "application/dns-message")
+	w.WriteHeader(http.StatusOK)
+	w.Write(resp.Bytes())
+}
+
+// answer returns the packed answer of the query, or nil if the query is malformed.
+func (s *Server) answer(ctx context.Context, b []byte) *buf.Buffer {
+	isIPQuery, domain, id, qType := parseIPQuery(b)
+	if len(domain) == 0 {
+		return nil
+	}
+
+	var resp *buf.Buffer
+	switch {
+	case isIPQuery:
+		resp = s.handler.answerIPQuery(ctx, id, qType, domain)
+	case s.handler.rawClient != nil:
+		resp = s.handler.answerRawQuery(ctx, id, qType, domain)
+	}
+	if resp == nil {
+		msg := newResponse(id, qType, domain)
+		msg.RCode = dnsmessage.RCodeServerFailure
+		resp = packResponse(msg)
+	}
+	return resp
+}
+
+func negotiatedProtocol(conn stat.Connection) string {
+	if statConn, ok := conn.(*stat.CounterConnection); ok {
+		conn = statConn.Connection
+	}
+	switch c := conn.(type) {
+	case *tls.Conn:
+		return c.ConnectionState().NegotiatedProtocol
+	case *reality.Conn:
+		return c.ConnectionState().NegotiatedProtocol
+	}
+	return ""
+}
+
+// lockedWriter serializes messages written by concurrent answers.
+type lockedWriter struct {
+	sync.Mutex
+	writer dns_proto.MessageWriter
+}
+
+func (w *lockedWriter) WriteMessage(b *buf.Buffer) error {
+	w.Lock()
+	defer w.Unlock()
+	return w.writer.WriteMessage(b)
+}
+
+var errListenerClosed = newError("listener closed")
+
+// connListener is a net.Listener that accepts a single connection, and is
+// closed as soon as the connection is closed.
+type connListener struct {
+	conn     net.Conn
+	accepted chan net.Conn
+	done     chan struct{}
+	once     sync.Once
+}
+
+func newConnListener(conn net.Conn) *connListener {
+	l := &connListener{
+		accepted: make(chan net.Conn, 1),
+		done:     make(chan struct{}),
+	}
+	l.conn = &listenerConn{Conn: conn, listener: l}
+	l.accepted <- l.conn
+	return l
+}
+
+func (l *connListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.accepted:
+		return conn, nil
+	case <-l.done:
+		return nil, errListenerClosed
+	}
+}
+
+func (l *connListener) Close() error {
+	l.once.Do(func() {
+		close(l.done)
+	})
+	return nil
+}
+
+func (l *connListener) Addr() net.Addr {
+	return l.conn.LocalAddr()
+}
+
+type listenerConn struct {
+	net.Conn
+	listener *connListener
+}
+
+func (c *listenerConn) Close() error {
+	c.listener.Close()
+	return c.Conn.Close()
+}
@@ -0,0 +1,175 @@
+package dns_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/miekg/dns"
+	"github.com/xtls/xray-core/app/dispatcher"
+	dnsapp "github.com/xtls/xray-core/app/dns"
+	"github.com/xtls/xray-core/app/policy"
+	"github.com/xtls/xray-core/app/proxyman"
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/serial"
+	"github.com/xtls/xray-core/core"
+	dns_proxy "github.com/xtls/xray-core/proxy/dns"
+	"github.com/xtls/xray-core/proxy/freedom"
+	"github.com/xtls/xray-core/testing/servers/tcp"
+	"github.com/xtls/xray-core/testing/servers/udp"
+)
+
+func startDNSServerInbound(t *testing.T, config *dns_proxy.ServerConfig) (*core.Instance, net.Port) {
+	port := udp.PickPort()
+
+	dnsServer := dns.Server{
+		Addr:    "127.0.0.1:" + port.String(),
+		Net:     "udp",
+		Handler: &staticHandler{},
+		UDPSize: 1200,
+	}
+	t.Cleanup(func() { dnsServer.Shutdown() })
+
+	go dnsServer.ListenAndServe()
+	time.Sleep(time.Second)
+
+	serverPort := tcp.PickPort()
+	v, err := core.New(&core.Config{
+		App: []*serial.TypedMessage{
+			serial.ToTypedMessage(&dnsapp.Config{
+				NameServer: []*dnsapp.NameServer{
+					{
+						Address: &net.Endpoint{
+							Network: net.Network_UDP,
+							Address: net.NewIPOrDomain(net.LocalHostIP),
+							Port:    uint32(port),
+						},
+					},
+				},
+			}),
+			serial.ToTypedMessage(&dispatcher.Config{}),
+			serial.ToTypedMessage(&proxyman.OutboundConfig{}),
+			serial.ToTypedMessage(&proxyman.InboundConfig{}),
+			serial.ToTypedMessage(&policy.Config{}),
+		},
+		Inbound: []*core.InboundHandlerConfig{
+			{
+				ProxySettings: serial.ToTypedMessage(config),
+				ReceiverSettings: serial.ToTypedMessage(&proxyman.ReceiverConfig{
+					PortList: &net.PortList{Range: []*net.PortRange{net.SinglePortRange(serverPort)}},
+					Listen:   net.NewIPOrDomain(net.LocalHostIP),
+				}),
+			},
+		},
+		Outbound: []*core.OutboundHandlerConfig{
+			{
+				ProxySettings: serial.ToTypedMessage(&freedom.Config{}),
+			},
+		},
+	})
+	common.Must(err)
+	common.Must(v.Start())
+	t.Cleanup(func() { v.Close() })
+	return v, serverPort
+}
+
+func checkDNSServerAnswers(t *testing.T, exchange func(*dns.Msg) (*dns.Msg, error)) {
+	m := new(dns.Msg)
+	m.SetQuestion("google.com.", dns.TypeA)
+	in, err := exchange(m)
+	common.Must(err)
+	if len(in.Answer) != 1 {
+		t.Fatal("len(answer): ", len(in.Answer))
+	}
+	rr, ok := in.Answer[0].(*dns.A)
+	if !ok {
+		t.Fatal("not A record")
+	}
+	if r := cmp.Diff(rr.A[:], net.IP{8, 8, 8, 8}); r != "" {
+		t.Error(r)
+	}
+
+	m = new(dns.Msg)
+	m.SetQuestion("google.com.", dns.TypeMX)
+	in, err = exchange(m)
+	common.Must(err)
+	if len(in.Answer) != 1 {
+		t.Fatal("len(answer): ", len(in.Answer))
+	}
+	if mx, ok := in.Answer[0].(*dns.MX); !ok || mx.Mx != "smtp.google.com." {
+		t.Error("unexpected MX answer: ", in.Answer[0])
+	}
+
+	m = new(dns.Msg)
+	m.SetQuestion("notexist.google.com.", dns.TypeMX)
+	in, err = exchange(m)
+	common.Must(err)
+	if in.Rcode != dns.RcodeNameError {
+		t.Error("expected NXDOMAIN, but got ", dns.RcodeToString[in.Rcode])
+	}
+}
+
+func TestDNSServerTCP(t *testing.T) {
+	_, serverPort := startDNSServerInbound(t, &dns_proxy.ServerConfig{})
+
+	conn, err := dns.Dial("tcp", "127.0.0.1:"+serverPort.String())
+	common.Must(err)
+	defer conn.Close()
+
+	c := &dns.Client{Net: "tcp"}
+	checkDNSServerAnswers(t, func(m *dns.Msg) (*dns.Msg, error) {
+		// All queries share the same connection.
+		in, _, err := c.ExchangeWithConn(m, conn)
+		return in, err
+	})
+}
+
+func TestDNSServerHTTP(t *testing.T) {
+	_, serverPort := startDNSServerInbound(t, &dns_proxy.ServerConfig{
+		Mode: dns_proxy.ServerConfig_HTTP,
+	})
+	url := "http://127.0.0.1:" + serverPort.String() + "/dns-query"
+
+	parse := func(resp *http.Response, err error) (*dns.Msg, error) {
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatal("unexpected status: ", resp.Status)
+		}
+		if ct := resp.Header.Get("Content-Type"); ct != "application/dns-message" {
+			t.Error("unexpected content type: ", ct)
+		}
+		b, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, err
+		}
+		in := new(dns.Msg)
+		return in, in.Unpack(b)
+	}
+
+	checkDNSServerAnswers(t, func(m *dns.Msg) (*dns.Msg, error) {
+		m.Id = 0
+		b, err := m.Pack()
+		common.Must(err)
+		return parse(http.Get(url + "?dns=" + base64.RawURLEncoding.EncodeToString(b)))
+	})
+	checkDNSServerAnswers(t, func(m *dns.Msg) (*dns.Msg, error) {
+		b, err := m.Pack()
+		common.Must(err)
+		return parse(http.Post(url, "application/dns-message", bytes.NewReader(b)))
+	})
+
+	resp, err := http.Get("http://127.0.0.1:" + serverPort.String() + "/other")
+	common.Must(err)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Error("expected 404 for unknown path, but got ", resp.Status)
+	}
+}
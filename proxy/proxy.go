@@ -80,6 +80,19 @@ type Inbound interface {
 	Process(context.Context, net.Network, stat.Connection, routing.Dispatcher) error
 }
 
+// A StandaloneInbound is an Inbound that accepts connections by itself, e.g. from a TUN device, instead of from
+// listeners on ports. The connections are still processed by Process, with their original destinations set as
+// the target of the outbound session.
+type StandaloneInbound interface {
+	Inbound
+
+	// Listen starts accepting connections and passes them to handler. It must not block, while handler may.
+	Listen(handler func(conn stat.Connection, dest net.Destination)) error
+
+	// Close stops accepting connections.
+	Close() error
+}
+
 // An Outbound process outbound connections.
 type Outbound interface {
 	// Process processes the given connection. The given dialer may be used to dial a system outbound connection.
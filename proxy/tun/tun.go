@@ -0,0 +1,206 @@
+// Package tun implements an inbound which captures traffic from a TUN device, and terminates it with a gVisor
+// netstack, so that connections are dispatched with their original destinations.
+package tun
+
+//go:generate go run github.com/xtls/xray-core/common/errors/errorgen
+
+import (
+	"context"
+	"net/netip"
+	"sync"
+
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/buf"
+	"github.com/xtls/xray-core/common/log"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/protocol"
+	"github.com/xtls/xray-core/common/session"
+	"github.com/xtls/xray-core/common/signal"
+	"github.com/xtls/xray-core/common/task"
+	"github.com/xtls/xray-core/core"
+	"github.com/xtls/xray-core/features/policy"
+	"github.com/xtls/xray-core/features/routing"
+	"github.com/xtls/xray-core/transport/internet/stat"
+)
+
+const (
+	defaultName       = "xray%d"
+	defaultMTU        = 1500
+	defaultRouteTable = 2022
+)
+
+func init() {
+	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
+		return New(ctx, config.(*Config))
+	}))
+}
+
+// device is a TUN device bridged to a netstack.
+type device interface {
+	Close() error
+}
+
+// Tun is an inbound connection handler that handles traffic from a TUN device.
+type Tun struct {
+	config        *Config
+	prefixes      []netip.Prefix
+	policyManager policy.Manager
+
+	access sync.Mutex
+	device device
+}
+
+// New creates a new TUN inbound handler.
+func New(ctx context.Context, config *Config) (*Tun, error) {
+	if len(config.Address) == 0 {
+		return nil, newError("no address is set for the TUN device")
+	}
+	t := &Tun{
+		config:        config,
+		policyManager: core.MustFromContext(ctx).GetFeature(policy.ManagerType()).(policy.Manager),
+	}
+	for _, address := range config.Address {
+		prefix, err := netip.ParsePrefix(address)
+		if err != nil {
+			return nil, newError("invalid address of the TUN device: ", address).Base(err)
+		}
+		t.prefixes = append(t.prefixes, prefix)
+	}
+	for _, address := range append(append([]string{}, config.Route...), config.ExcludeAddress...) {
+		if _, err := netip.ParsePrefix(address); err != nil {
+			return nil, newError("invalid route: ", address).Base(err)
+		}
+	}
+	return t, nil
+}
+
+func (t *Tun) name() string {
+	if len(t.config.Name) > 0 {
+		return t.config.Name
+	}
+	return defaultName
+}
+
+func (t *Tun) mtu() int {
+	if t.config.Mtu > 0 {
+		return int(t.config.Mtu)
+	}
+	return defaultMTU
+}
+
+func (t *Tun) routeTable() int {
+	if t.config.RouteTable > 0 {
+		return int(t.config.RouteTable)
+	}
+	return defaultRouteTable
+}
+
+// Network implements proxy.Inbound.
+func (*Tun) Network() []net.Network {
+	return []net.Network{net.Network_TCP, net.Network_UDP}
+}
+
+// Listen implements proxy.StandaloneInbound.
+func (t *Tun) Listen(handler func(conn stat.Connection, dest net.Destination)) error {
+	t.access.Lock()
+	defer t.access.Unlock()
+
+	if t.device != nil {
+		return newError("TUN device is already open")
+	}
+	d, err := t.openDevice(func(dest net.Destination, conn net.Conn) {
+		handler(conn, dest)
+	})
+	if err != nil {
+		return newError("failed to open TUN device").Base(err)
+	}
+	t.device = d
+	return nil
+}
+
+// Close implements proxy.StandaloneInbound.
+func (t *Tun) Close() error {
+	t.access.Lock()
+	defer t.access.Unlock()
+
+	if t.device == nil {
+		return nil
+	}
+	err := t.device.Close()
+	t.device = nil
+	return err
+}
+
+// Process implements proxy.Inbound.
+func (t *Tun) Process(ctx context.Context, network net.Network, conn stat.Connection, dispatcher routing.Dispatcher) error {
+	var dest net.Destination
+	if outbound := session.OutboundFromContext(ctx); outbound != nil {
+		dest = outbound.Target
+	}
+	if !dest.IsValid() {
+		return newError("unable to get destination")
+	}
+
+	inbound := session.InboundFromContext(ctx)
+	inbound.Name = "tun"
+	inbound.User = &protocol.MemoryUser{
+		Level: t.config.UserLevel,
+	}
+
+	ctx = log.ContextWithAccessMessage(ctx, &log.AccessMessage{
+		From:   conn.RemoteAddr(),
+		To:     dest,
+		Status: log.AccessAccepted,
+		Reason: "",
+	})
+	newError("received request for ", dest).WriteToLog(session.ExportIDToError(ctx))
+
+	plcy := t.policyManager.ForLevel(t.config.UserLevel)
+	ctx, cancel := context.WithCancel(ctx)
+	timer := signal.CancelAfterInactivity(ctx, cancel, plcy.Timeouts.ConnectionIdle)
+	inbound.Timer = timer
+
+	ctx = policy.ContextWithBufferPolicy(ctx, plcy.Buffer)
+	link, err := dispatcher.Dispatch(ctx, dest)
+	if err != nil {
+		return newError("failed to dispatch request").Base(err)
+	}
+
+	requestDone := func() error {
+		defer timer.SetTimeout(plcy.Timeouts.DownlinkOnly)
+
+		var reader buf.Reader
+		if network == net.Network_UDP {
+			reader = buf.NewPacketReader(conn)
+		} else {
+			reader = buf.NewReader(conn)
+		}
+		if err := buf.Copy(reader, link.Writer, buf.UpdateActivity(timer)); err != nil {
+			return newError("failed to transport request").Base(err)
+		}
+		return nil
+	}
+
+	responseDone := func() error {
+		defer timer.SetTimeout(plcy.Timeouts.UplinkOnly)
+
+		var writer buf.Writer
+		if network == net.Network_UDP {
+			writer = &buf.SequentialWriter{Writer: conn}
+		} else {
+			writer = buf.NewWriter(conn)
+		}
+		if err := buf.Copy(link.Reader, writer, buf.UpdateActivity(timer)); err != nil {
+			return newError("failed to transport response").Base(err)
+		}
+		return nil
+	}
+
+	if err := task.Run(ctx, task.OnSuccess(requestDone, task.Close(link.Writer)), responseDone); err != nil {
+		common.Interrupt(link.Reader)
+		common.Interrupt(link.Writer)
+		return newError("connection ends").Base(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,262 @@
+//go:build linux && !android
+
+package tun
+
+import (
+	"errors"
+	"net/netip"
+	"os"
+
+	"github.com/vishvananda/netlink"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/proxy/wireguard/gvisortun"
+	"golang.org/x/sys/unix"
+	wgtun "golang.zx2c4.com/wireguard/tun"
+	"gvisor.dev/gvisor/pkg/tcpip/stack"
+)
+
+const (
+	// packetOffset is the room left before each packet, as the kernel device needs it for the virtio header.
+	packetOffset = 16
+	// rulePriority is the priority of the first policy routing rule added for auto route.
+	rulePriority = 9000
+)
+
+type linuxDevice struct {
+	tun   wgtun.Device
+	stack wgtun.Device
+
+	handle *netlink.Handle
+	routes []*netlink.Route
+	rules  []*netlink.Rule
+}
+
+func prefixToIPNet(prefix netip.Prefix) *net.IPNet {
+	return &net.IPNet{
+		IP:   prefix.Addr().AsSlice(),
+		Mask: net.CIDRMask(prefix.Bits(), prefix.Addr().BitLen()),
+	}
+}
+
+func family(prefix netip.Prefix) int {
+	if prefix.Addr().Is4() {
+		return unix.AF_INET
+	}
+	return unix.AF_INET6
+}
+
+func (t *Tun) openDevice(handler gvisortun.Handler) (_ device, err error) {
+	mtu := t.mtu()
+	d := &linuxDevice{}
+	defer func() {
+		if err != nil {
+			d.Close()
+		}
+	}()
+
+	d.tun, err = wgtun.CreateTUN(t.name(), mtu)
+	if err != nil {
+		return nil, err
+	}
+	name, err := d.tun.Name()
+	if err != nil {
+		return nil, err
+	}
+
+	// The netstack takes the next address in each prefix, so that it acts as the peer of the system.
+	var stackAddresses []netip.Addr
+	for _, prefix := range t.prefixes {
+		addr := prefix.Addr().Next()
+		if !prefix.Contains(addr) {
+			addr = prefix.Addr()
+		}
+		stackAddresses = append(stackAddresses, addr)
+	}
+	var s *stack.Stack
+	d.stack, _, s, err = gvisortun.CreateNetTUN(stackAddresses, mtu, true)
+	if err != nil {
+		return nil, err
+	}
+	gvisortun.HandleConnections(s, handler)
+
+	d.handle, err = netlink.NewHandle()
+	if err != nil {
+		return nil, err
+	}
+	link, err := d.handle.LinkByName(name)
+	if err != nil {
+		return nil, err
+	}
+	for _, prefix := range t.prefixes {
+		if err = d.handle.AddrAdd(link, &netlink.Addr{IPNet: prefixToIPNet(prefix)}); err != nil {
+			return nil, newError("failed to add address ", prefix, " to ", name).Base(err)
+		}
+	}
+	if err = d.handle.LinkSetMTU(link, mtu); err != nil {
+		return nil, err
+	}
+	if err = d.handle.LinkSetUp(link); err != nil {
+		return nil, err
+	}
+	if t.config.AutoRoute {
+		if err = d.setupRoutes(t, link.Attrs().Index); err != nil {
+			return nil, err
+		}
+	}
+
+	go func() {
+		for range d.tun.Events() {
+		}
+	}()
+	go d.forward(d.tun, d.stack, mtu)
+	go d.forward(d.stack, d.tun, mtu)
+
+	newError("TUN device ", name, " is up").AtInfo().WriteToLog()
+	return d, nil
+}
+
+// setupRoutes routes the traffic of the system into the TUN device through a dedicated routing table, except
+// for the excluded destinations and marked packets.
+func (d *linuxDevice) setupRoutes(t *Tun, linkIndex int) error {
+	table := t.routeTable()
+
+	var routes []netip.Prefix
+	for _, r := range t.config.Route {
+		routes = append(routes, netip.MustParsePrefix(r))
+	}
+	if len(routes) == 0 {
+		for _, prefix := range t.prefixes {
+			if prefix.Addr().Is4() {
+				routes = append(routes, netip.MustParsePrefix("0.0.0.0/0"))
+			} else {
+				routes = append(routes, netip.MustParsePrefix("::/0"))
+			}
+		}
+	}
+
+	families := make(map[int]bool)
+	added := make(map[netip.Prefix]bool)
+	for _, r := range routes {
+		if added[r] {
+			continue
+		}
+		added[r] = true
+		families[family(r)] = true
+		route := &netlink.Route{
+			LinkIndex: linkIndex,
+			Dst:       prefixToIPNet(r),
+			Table:     table,
+		}
+		if err := d.handle.RouteAdd(route); err != nil {
+			return newError("failed to add route ", r).Base(err)
+		}
+		d.routes = append(d.routes, route)
+	}
+
+	if t.config.ExcludeMark == 0 {
+		newError("auto route is enabled without exclude mark, outbound traffic may be routed back to the TUN device").AtWarning().WriteToLog()
+	}
+
+	addRule := func(rule *netlink.Rule) error {
+		if err := d.handle.RuleAdd(rule); err != nil {
+			return newError("failed to add rule ", rule).Base(err)
+		}
+		d.rules = append(d.rules, rule)
+		return nil
+	}
+	for f := range families {
+		for _, e := range t.config.ExcludeAddress {
+			prefix := netip.MustParsePrefix(e)
+			if family(prefix) != f {
+				continue
+			}
+			rule := netlink.NewRule()
+			rule.Priority, rule.Family, rule.Table, rule.Dst = rulePriority, f, unix.RT_TABLE_MAIN, prefixToIPNet(prefix)
+			if err := addRule(rule); err != nil {
+				return err
+			}
+		}
+		if t.config.ExcludeMark != 0 {
+			rule := netlink.NewRule()
+			rule.Priority, rule.Family, rule.Table, rule.Mark = rulePriority, f, unix.RT_TABLE_MAIN, int(t.config.ExcludeMark)
+			if err := addRule(rule); err != nil {
+				return err
+			}
+		}
+		rule := netlink.NewRule()
+		rule.Priority, rule.Family, rule.Table = rulePriority+1, f, table
+		if err := addRule(rule); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// forward copies packets from one device to the other, until either of them is closed.
+func (d *linuxDevice) forward(from, to wgtun.Device, mtu int) {
+	batchSize := from.BatchSize()
+	bufs := make([][]byte, batchSize)
+	for i := range bufs {
+		bufs[i] = make([]byte, packetOffset+mtu)
+	}
+	sizes := make([]int, batchSize)
+	packets := make([][]byte, 0, batchSize)
+
+	for {
+		n, err := from.Read(bufs, sizes, packetOffset)
+		if err != nil {
+			if errors.Is(err, os.ErrClosed) {
+				return
+			}
+			if !errors.Is(err, wgtun.ErrTooManySegments) {
+				newError("failed to read packets from TUN device").Base(err).AtDebug().WriteToLog()
+				return
+			}
+		}
+		packets = packets[:0]
+		for i := 0; i < n; i++ {
+			packets = append(packets, bufs[i][:packetOffset+sizes[i]])
+		}
+		if len(packets) == 0 {
+			continue
+		}
+		if _, err := to.Write(packets, packetOffset); err != nil {
+			if errors.Is(err, os.ErrClosed) {
+				return
+			}
+			newError("failed to write packets to TUN device").Base(err).AtDebug().WriteToLog()
+		}
+	}
+}
+
+func (d *linuxDevice) Close() error {
+	var errs []error
+	for _, rule := range d.rules {
+		if err := d.handle.RuleDel(rule); err != nil {
+			errs = append(errs, newError("failed to delete rule ", rule).Base(err))
+		}
+	}
+	for _, route := range d.routes {
+		if err := d.handle.RouteDel(route); err != nil {
+			errs = append(errs, newError("failed to delete route ", route.Dst).Base(err))
+		}
+	}
+	d.rules, d.routes = nil, nil
+	if d.handle != nil {
+		d.handle.Close()
+		d.handle = nil
+	}
+	if d.tun != nil {
+		if err := d.tun.Close(); err != nil {
+			errs = append(errs, err)
+		}
+		d.tun = nil
+	}
+	if d.stack != nil {
+		if err := d.stack.Close(); err != nil {
+			errs = append(errs, err)
+		}
+		d.stack = nil
+	}
+	return errors.Join(errs...)
+}
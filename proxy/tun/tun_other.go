@@ -0,0 +1,11 @@
+//go:build !linux || android
+
+package tun
+
+import (
+	"github.com/xtls/xray-core/proxy/wireguard/gvisortun"
+)
+
+func (t *Tun) openDevice(handler gvisortun.Handler) (device, error) {
+	return nil, newError("TUN inbound is only supported on Linux")
+}
@@ -0,0 +1,73 @@
+package gvisortun
+
+import (
+	"net"
+	"time"
+
+	xnet "github.com/xtls/xray-core/common/net"
+	"gvisor.dev/gvisor/pkg/tcpip"
+	"gvisor.dev/gvisor/pkg/tcpip/adapters/gonet"
+	"gvisor.dev/gvisor/pkg/tcpip/stack"
+	"gvisor.dev/gvisor/pkg/tcpip/transport/tcp"
+	"gvisor.dev/gvisor/pkg/tcpip/transport/udp"
+	"gvisor.dev/gvisor/pkg/waiter"
+)
+
+// Handler handles a connection captured by a stack in promiscuous mode. As the stack terminates the connection
+// on behalf of the remote peer, dest is the original destination of the connection.
+type Handler func(dest xnet.Destination, conn net.Conn)
+
+// HandleConnections captures all TCP and UDP connections going through the stack, and passes them to handler
+// in new goroutines.
+func HandleConnections(s *stack.Stack, handler Handler) {
+	tcpForwarder := tcp.NewForwarder(s, 0, 65535, func(r *tcp.ForwarderRequest) {
+		go func(r *tcp.ForwarderRequest) {
+			var (
+				wq waiter.Queue
+				id = r.ID()
+			)
+
+			// Perform a TCP three-way handshake.
+			ep, err := r.CreateEndpoint(&wq)
+			if err != nil {
+				newError(err.String()).AtError().WriteToLog()
+				r.Complete(true)
+				return
+			}
+			r.Complete(false)
+			defer ep.Close()
+
+			// enable tcp keep-alive to prevent hanging connections
+			ep.SocketOptions().SetKeepAlive(true)
+
+			// local address is actually destination
+			handler(xnet.TCPDestination(xnet.IPAddress(id.LocalAddress.AsSlice()), xnet.Port(id.LocalPort)), gonet.NewTCPConn(&wq, ep))
+		}(r)
+	})
+	s.SetTransportProtocolHandler(tcp.ProtocolNumber, tcpForwarder.HandlePacket)
+
+	udpForwarder := udp.NewForwarder(s, func(r *udp.ForwarderRequest) {
+		go func(r *udp.ForwarderRequest) {
+			var (
+				wq waiter.Queue
+				id = r.ID()
+			)
+
+			ep, err := r.CreateEndpoint(&wq)
+			if err != nil {
+				newError(err.String()).AtError().WriteToLog()
+				return
+			}
+			defer ep.Close()
+
+			// prevents hanging connections and ensure timely release
+			ep.SocketOptions().SetLinger(tcpip.LingerOption{
+				Enabled: true,
+				Timeout: 15 * time.Second,
+			})
+
+			handler(xnet.UDPDestination(xnet.IPAddress(id.LocalAddress.AsSlice()), xnet.Port(id.LocalPort)), gonet.NewUDPConn(s, &wq, ep))
+		}(r)
+	})
+	s.SetTransportProtocolHandler(udp.ProtocolNumber, udpForwarder.HandlePacket)
+}
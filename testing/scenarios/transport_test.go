@@ -25,7 +25,6 @@ import (
 	"github.com/xtls/xray-core/transport/internet"
 	"github.com/xtls/xray-core/transport/internet/domainsocket"
 	"github.com/xtls/xray-core/transport/internet/headers/http"
-	"github.com/xtls/xray-core/transport/internet/headers/wechat"
 	"github.com/xtls/xray-core/transport/internet/quic"
 	tcptransport "github.com/xtls/xray-core/transport/internet/tcp"
 	"golang.org/x/sync/errgroup"
@@ -275,12 +274,7 @@ func TestVMessQuic(t *testing.T) {
 						TransportSettings: []*internet.TransportConfig{
 							{
 								ProtocolName: "quic",
-								Settings: serial.ToTypedMessage(&quic.Config{
-									Header: serial.ToTypedMessage(&wechat.VideoConfig{}),
-									Security: &protocol.SecurityConfig{
-										Type: protocol.SecurityType_NONE,
-									},
-								}),
+								Settings:     serial.ToTypedMessage(&quic.Config{}),
 							},
 						},
 					},
@@ -334,12 +328,7 @@ func TestVMessQuic(t *testing.T) {
 						TransportSettings: []*internet.TransportConfig{
 							{
 								ProtocolName: "quic",
-								Settings: serial.ToTypedMessage(&quic.Config{
-									Header: serial.ToTypedMessage(&wechat.VideoConfig{}),
-									Security: &protocol.SecurityConfig{
-										Type: protocol.SecurityType_NONE,
-									},
-								}),
+								Settings:     serial.ToTypedMessage(&quic.Config{}),
 							},
 						},
 					},
@@ -13,6 +13,7 @@ import (
 	"github.com/xtls/xray-core/transport"
 	"github.com/xtls/xray-core/transport/internet/stat"
 	"github.com/xtls/xray-core/transport/pipe"
+	"golang.org/x/net/dns/dnsmessage"
 )
 
 // Dialer is the interface for dialing outbound connections.
@@ -102,6 +103,15 @@ func lookupIP(domain string, strategy DomainStrategy, localAddr net.Address) ([]
 	return ips, err
 }
 
+// LookupRaw queries the records of the given type for the domain with the DNS of the instance.
+func LookupRaw(ctx context.Context, domain string, qType dnsmessage.Type) (*dnsmessage.Message, error) {
+	rawClient, ok := dnsClient.(dns.RawClient)
+	if !ok {
+		return nil, newError("DNS does not support ", qType, " queries")
+	}
+	return rawClient.LookupRaw(ctx, domain, qType)
+}
+
 func canLookupIP(ctx context.Context, dst net.Destination, sockopt *SocketConfig) bool {
 	if dst.Address.Family().IsIP() || dnsClient == nil {
 		return false
@@ -171,6 +171,7 @@ type ConnMetadata struct {
 	LocalAddr    net.Addr
 	RemoteAddr   net.Addr
 	Conversation uint16
+	Counters     *Counters
 }
 
 // Connection is a KCP connection over UDP.
@@ -196,6 +197,7 @@ type Connection struct {
 	sendingWorker   *SendingWorker
 
 	output SegmentWriter
+	fec    *FECDecoder
 
 	dataUpdater *Updater
 	pingUpdater *Updater
@@ -205,6 +207,23 @@ type Connection struct {
 func NewConnection(meta ConnMetadata, writer PacketWriter, closer io.Closer, config *Config) *Connection {
 	newError("#", meta.Conversation, " creating connection to ", meta.RemoteAddr).WriteToLog()
 
+	output := NewRetryableWriter(NewSegmentWriter(writer))
+	mss := config.GetMTUValue() - uint32(writer.Overhead()) - DataSegmentOverhead
+	if dataShards, parityShards := config.GetFECShards(); dataShards > 0 {
+		fecWriter, err := NewFECWriter(output, meta.Conversation, dataShards, parityShards)
+		if err != nil {
+			newError("#", meta.Conversation, " disabling FEC").Base(err).AtWarning().WriteToLog()
+		} else {
+			output = fecWriter
+			// Parity shards are larger than the largest data shard by the size prefix.
+			mss -= FECSegmentOverhead + 2
+		}
+	}
+	var fecDecoder *FECDecoder
+	if dataShards, parityShards := config.GetFECShards(); dataShards > 0 {
+		fecDecoder, _ = NewFECDecoder(dataShards, parityShards)
+	}
+
 	conn := &Connection{
 		meta:       meta,
 		closer:     closer,
@@ -212,8 +231,9 @@ func NewConnection(meta ConnMetadata, writer PacketWriter, closer io.Closer, con
 		dataInput:  signal.NewNotifier(),
 		dataOutput: signal.NewNotifier(),
 		Config:     config,
-		output:     NewRetryableWriter(NewSegmentWriter(writer)),
-		mss:        config.GetMTUValue() - uint32(writer.Overhead()) - DataSegmentOverhead,
+		output:     output,
+		fec:        fecDecoder,
+		mss:        mss,
 		roundTrip: &RoundTripInfo{
 			rto:    100,
 			minRtt: config.GetTTIValue(),
@@ -597,11 +617,42 @@ func (c *Connection) Input(segments []Segment) {
 			c.receivingWorker.ProcessSendingNext(seg.SendingNext)
 			c.roundTrip.UpdatePeerRTO(seg.PeerRTO, current)
 			seg.Release()
+		case *FECSegment:
+			c.inputFEC(seg)
+			seg.Release()
 		default:
 		}
 	}
 }
 
+func (c *Connection) inputFEC(seg *FECSegment) {
+	if c.fec == nil {
+		newError("#", c.meta.Conversation, " discarding FEC segment as FEC is disabled").AtDebug().WriteToLog()
+		return
+	}
+
+	data, recovered := c.fec.Decode(seg)
+	c.meta.Counters.addRecovered(recovered)
+	for _, b := range data {
+		var segments []Segment
+		for len(b) > 0 {
+			inner, x := ReadSegment(b)
+			if inner == nil {
+				break
+			}
+			if inner.Command() == CommandFEC {
+				inner.Release()
+				break
+			}
+			segments = append(segments, inner)
+			b = x
+		}
+		if len(segments) > 0 {
+			c.Input(segments)
+		}
+	}
+}
+
 func (c *Connection) flush() {
 	current := c.Elapsed()
 
@@ -78,6 +78,7 @@ func DialKCP(ctx context.Context, dest net.Destination, streamSettings *internet
 		LocalAddr:    rawConn.LocalAddr(),
 		RemoteAddr:   rawConn.RemoteAddr(),
 		Conversation: conv,
+		Counters:     newCounters(ctx),
 	}, writer, rawConn, kcpSettings)
 
 	go fetchInput(ctx, rawConn, reader, session)
@@ -0,0 +1,287 @@
+package kcp
+
+import (
+	"context"
+	"encoding/binary"
+	"sync"
+
+	"github.com/klauspost/reedsolomon"
+	"github.com/xtls/xray-core/core"
+	"github.com/xtls/xray-core/features/stats"
+)
+
+// fecGroupWindow is the number of recent FEC groups kept for recovery.
+const fecGroupWindow = 32
+
+// Counters are the stats counters of mKCP connections.
+type Counters struct {
+	// Recovered counts the segments recovered by FEC.
+	Recovered stats.Counter
+	// Retransmitted counts the retransmitted data segments.
+	Retransmitted stats.Counter
+}
+
+// newCounters returns the counters in the stats manager of the instance in the context, or nil if there is none.
+func newCounters(ctx context.Context) *Counters {
+	v := core.FromContext(ctx)
+	if v == nil {
+		return nil
+	}
+	m, ok := v.GetFeature(stats.ManagerType()).(stats.Manager)
+	if !ok {
+		return nil
+	}
+	c := new(Counters)
+	c.Recovered, _ = stats.GetOrRegisterCounter(m, "mkcp>>>segment>>>recovered")
+	c.Retransmitted, _ = stats.GetOrRegisterCounter(m, "mkcp>>>segment>>>retransmitted")
+	return c
+}
+
+func (c *Counters) addRecovered(n int) {
+	if c != nil && c.Recovered != nil {
+		c.Recovered.Add(int64(n))
+	}
+}
+
+func (c *Counters) addRetransmitted(n int) {
+	if c != nil && c.Retransmitted != nil {
+		c.Retransmitted.Add(int64(n))
+	}
+}
+
+func isValidFECShards(dataShards int, parityShards int) bool {
+	return dataShards > 0 && parityShards > 0 && dataShards+parityShards <= 256
+}
+
+// FECWriter sends each segment as a data shard, followed by the parity shards once a group of data shards is sent.
+type FECWriter struct {
+	sync.Mutex
+	writer       SegmentWriter
+	encoder      reedsolomon.Encoder
+	conv         uint16
+	dataShards   int
+	parityShards int
+
+	group  uint32
+	shards [][]byte
+	count  int
+	maxLen int
+}
+
+func NewFECWriter(writer SegmentWriter, conv uint16, dataShards int, parityShards int) (*FECWriter, error) {
+	if !isValidFECShards(dataShards, parityShards) {
+		return nil, newError("invalid FEC shards: ", dataShards, "+", parityShards)
+	}
+	encoder, err := reedsolomon.New(dataShards, parityShards)
+	if err != nil {
+		return nil, newError("failed to create FEC encoder").Base(err)
+	}
+	return &FECWriter{
+		writer:       writer,
+		encoder:      encoder,
+		conv:         conv,
+		dataShards:   dataShards,
+		parityShards: parityShards,
+		shards:       make([][]byte, dataShards+parityShards),
+	}, nil
+}
+
+func (w *FECWriter) newSegment(index int) *FECSegment {
+	return &FECSegment{
+		Conv:         w.conv,
+		Group:        w.group,
+		Index:        uint8(index),
+		DataShards:   uint8(w.dataShards),
+		ParityShards: uint8(w.parityShards),
+	}
+}
+
+// Write implements SegmentWriter.
+func (w *FECWriter) Write(seg Segment) error {
+	w.Lock()
+	defer w.Unlock()
+
+	// Shards are prefixed with the size of the segment, so that recovered shards can be stripped of padding.
+	shard := make([]byte, 2+seg.ByteSize())
+	binary.BigEndian.PutUint16(shard, uint16(seg.ByteSize()))
+	seg.Serialize(shard[2:])
+
+	fecSeg := w.newSegment(w.count)
+	fecSeg.Data().Write(shard[2:])
+	err := w.writer.Write(fecSeg)
+	fecSeg.Release()
+
+	w.shards[w.count] = shard
+	if len(shard) > w.maxLen {
+		w.maxLen = len(shard)
+	}
+	w.count++
+	if w.count == w.dataShards {
+		w.writeParity()
+	}
+	return err
+}
+
+func (w *FECWriter) writeParity() {
+	for i := 0; i < w.dataShards; i++ {
+		w.shards[i] = append(w.shards[i], make([]byte, w.maxLen-len(w.shards[i]))...)
+	}
+	for i := w.dataShards; i < len(w.shards); i++ {
+		w.shards[i] = make([]byte, w.maxLen)
+	}
+	if err := w.encoder.Encode(w.shards); err != nil {
+		newError("failed to encode FEC group").Base(err).WriteToLog()
+	} else {
+		for i := w.dataShards; i < len(w.shards); i++ {
+			fecSeg := w.newSegment(i)
+			fecSeg.Data().Write(w.shards[i])
+			w.writer.Write(fecSeg)
+			fecSeg.Release()
+		}
+	}
+
+	for i := range w.shards {
+		w.shards[i] = nil
+	}
+	w.group++
+	w.count = 0
+	w.maxLen = 0
+}
+
+type fecGroup struct {
+	shards   [][]byte
+	received int
+	done     bool
+}
+
+// FECDecoder recovers lost data shards of FEC groups from the parity shards.
+type FECDecoder struct {
+	encoder      reedsolomon.Encoder
+	dataShards   int
+	parityShards int
+	groups       map[uint32]*fecGroup
+	latest       uint32
+}
+
+func NewFECDecoder(dataShards int, parityShards int) (*FECDecoder, error) {
+	if !isValidFECShards(dataShards, parityShards) {
+		return nil, newError("invalid FEC shards: ", dataShards, "+", parityShards)
+	}
+	encoder, err := reedsolomon.New(dataShards, parityShards)
+	if err != nil {
+		return nil, newError("failed to create FEC decoder").Base(err)
+	}
+	return &FECDecoder{
+		encoder:      encoder,
+		dataShards:   dataShards,
+		parityShards: parityShards,
+		groups:       make(map[uint32]*fecGroup),
+	}, nil
+}
+
+// Decode returns the serialized segments carried by the shard, and the ones recovered with it.
+func (d *FECDecoder) Decode(seg *FECSegment) (data [][]byte, recovered int) {
+	index := int(seg.Index)
+	if int(seg.DataShards) != d.dataShards || int(seg.ParityShards) != d.parityShards || index >= d.dataShards+d.parityShards {
+		return nil, 0
+	}
+	payload := seg.Data().Bytes()
+	// Parity shards carry at least the size prefix of the data shards.
+	if seg.IsParity() && len(payload) < 2 {
+		return nil, 0
+	}
+
+	// Groups far behind the latest one are complete or lost for good.
+	if seg.Group-d.latest < 0x7FFFFFFF {
+		d.latest = seg.Group
+	} else if d.latest-seg.Group >= fecGroupWindow {
+		if seg.IsParity() {
+			return nil, 0
+		}
+		return [][]byte{payload}, 0
+	}
+	if len(d.groups) > fecGroupWindow {
+		for n := range d.groups {
+			if d.latest-n >= fecGroupWindow {
+				delete(d.groups, n)
+			}
+		}
+	}
+
+	group, found := d.groups[seg.Group]
+	if !found {
+		group = &fecGroup{
+			shards: make([][]byte, d.dataShards+d.parityShards),
+		}
+		d.groups[seg.Group] = group
+	}
+	if group.done || group.shards[index] != nil {
+		return nil, 0
+	}
+
+	if seg.IsParity() {
+		group.shards[index] = append([]byte(nil), payload...)
+	} else {
+		shard := make([]byte, 2+len(payload))
+		binary.BigEndian.PutUint16(shard, uint16(len(payload)))
+		copy(shard[2:], payload)
+		group.shards[index] = shard
+		data = append(data, payload)
+	}
+	group.received++
+
+	if group.received < d.dataShards {
+		return data, 0
+	}
+	group.done = true
+	defer func() {
+		group.shards = nil
+	}()
+
+	size := 0
+	missing := make([]int, 0, d.parityShards)
+	for i, shard := range group.shards {
+		if i >= d.dataShards {
+			if shard == nil {
+				continue
+			}
+			// Parity shards of a group have the same size.
+			if size != 0 && len(shard) != size {
+				return data, 0
+			}
+			size = len(shard)
+		} else if shard == nil {
+			missing = append(missing, i)
+		}
+	}
+	if len(missing) == 0 {
+		return data, 0
+	}
+	for i := 0; i < d.dataShards; i++ {
+		shard := group.shards[i]
+		if shard == nil {
+			continue
+		}
+		if len(shard) > size {
+			return data, 0
+		}
+		group.shards[i] = append(shard, make([]byte, size-len(shard))...)
+	}
+	if err := d.encoder.ReconstructData(group.shards); err != nil {
+		newError("failed to recover FEC group ", seg.Group).Base(err).AtDebug().WriteToLog()
+		return data, 0
+	}
+	for _, i := range missing {
+		shard := group.shards[i]
+		if len(shard) < 2 {
+			continue
+		}
+		n := int(binary.BigEndian.Uint16(shard))
+		if 2+n > len(shard) {
+			continue
+		}
+		data = append(data, shard[2:2+n])
+		recovered++
+	}
+	return data, recovered
+}
@@ -0,0 +1,99 @@
+package kcp_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/xtls/xray-core/common"
+	. "github.com/xtls/xray-core/transport/internet/kcp"
+)
+
+type segmentRecorder struct {
+	segments []*FECSegment
+}
+
+func (r *segmentRecorder) Write(seg Segment) error {
+	b := make([]byte, seg.ByteSize())
+	seg.Serialize(b)
+	parsed, _ := ReadSegment(b)
+	r.segments = append(r.segments, parsed.(*FECSegment))
+	return nil
+}
+
+func TestFECRecovery(t *testing.T) {
+	recorder := new(segmentRecorder)
+	writer, err := NewFECWriter(recorder, 1, 4, 2)
+	common.Must(err)
+
+	var sent [][]byte
+	for i := 0; i < 8; i++ {
+		seg := NewDataSegment()
+		seg.Conv = 1
+		seg.Number = uint32(i)
+		seg.Data().Write(make([]byte, 10*i+1))
+		b := make([]byte, seg.ByteSize())
+		seg.Serialize(b)
+		sent = append(sent, b)
+		common.Must(writer.Write(seg))
+		seg.Release()
+	}
+	if len(recorder.segments) != 12 {
+		t.Fatal("expected 12 shards, but got ", len(recorder.segments))
+	}
+
+	decoder, err := NewFECDecoder(4, 2)
+	common.Must(err)
+
+	lost := map[int]bool{
+		// Two data shards of the first group.
+		1: true,
+		3: true,
+		// A data shard and a parity shard of the second group.
+		6:  true,
+		10: true,
+	}
+	received := make([][]byte, 8)
+	totalRecovered := 0
+	for i, seg := range recorder.segments {
+		if lost[i] {
+			continue
+		}
+		data, recovered := decoder.Decode(seg)
+		totalRecovered += recovered
+		for _, b := range data {
+			parsed, _ := ReadSegment(b)
+			received[parsed.(*DataSegment).Number] = append([]byte(nil), b...)
+		}
+	}
+
+	if totalRecovered != 3 {
+		t.Error("expected 3 recovered segments, but got ", totalRecovered)
+	}
+	if r := cmp.Diff(received, sent); r != "" {
+		t.Error(r)
+	}
+}
+
+func TestFECMalformedParity(t *testing.T) {
+	decoder, err := NewFECDecoder(1, 1)
+	common.Must(err)
+
+	seg := NewFECSegment()
+	seg.Index = 1
+	seg.DataShards = 1
+	seg.ParityShards = 1
+	seg.Data().WriteByte(1)
+	if data, recovered := decoder.Decode(seg); len(data) != 0 || recovered != 0 {
+		t.Error("recovered from malformed parity shard: ", data)
+	}
+
+	// Shard counts of the segment must match the decoder.
+	seg = NewFECSegment()
+	seg.Index = 2
+	seg.DataShards = 2
+	seg.ParityShards = 1
+	seg.Data().Write([]byte{0, 0, 0})
+	if data, recovered := decoder.Decode(seg); len(data) != 0 || recovered != 0 {
+		t.Error("decoded shard of other shard counts: ", data)
+	}
+}
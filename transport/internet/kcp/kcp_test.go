@@ -83,3 +83,53 @@ func TestDialAndListen(t *testing.T) {
 		t.Error("active connections: ", v)
 	}
 }
+
+func TestDialAndListenWithFEC(t *testing.T) {
+	listerner, err := NewListener(context.Background(), net.LocalHostIP, net.Port(0), &internet.MemoryStreamConfig{
+		ProtocolName: "mkcp",
+		ProtocolSettings: &Config{
+			Fec: &FEC{
+				DataShards:   10,
+				ParityShards: 3,
+			},
+		},
+	}, func(conn stat.Connection) {
+		go func(c stat.Connection) {
+			defer c.Close()
+			io.Copy(c, c)
+		}(conn)
+	})
+	common.Must(err)
+	defer listerner.Close()
+
+	port := net.Port(listerner.Addr().(*net.UDPAddr).Port)
+
+	clientConn, err := DialKCP(context.Background(), net.UDPDestination(net.LocalHostIP, port), &internet.MemoryStreamConfig{
+		ProtocolName: "mkcp",
+		ProtocolSettings: &Config{
+			Fec: &FEC{
+				DataShards:   10,
+				ParityShards: 3,
+			},
+		},
+	})
+	common.Must(err)
+
+	clientSend := make([]byte, 256*1024)
+	rand.Read(clientSend)
+	go clientConn.Write(clientSend)
+
+	clientReceived := make([]byte, 256*1024)
+	common.Must2(io.ReadFull(clientConn, clientReceived))
+	if r := cmp.Diff(clientReceived, clientSend); r != "" {
+		t.Error(r)
+	}
+	clientConn.Close()
+
+	for i := 0; i < 60 && listerner.ActiveConnections() > 0; i++ {
+		time.Sleep(500 * time.Millisecond)
+	}
+	if v := listerner.ActiveConnections(); v != 0 {
+		t.Error("active connections: ", v)
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/xtls/xray-core/transport/internet/stat"
 	"github.com/xtls/xray-core/transport/internet/tls"
 	"github.com/xtls/xray-core/transport/internet/udp"
+	"google.golang.org/protobuf/proto"
 )
 
 type ConnectionID struct {
@@ -32,6 +33,7 @@ type Listener struct {
 	header    internet.PacketHeader
 	security  cipher.AEAD
 	addConn   internet.ConnHandler
+	counters  *Counters
 }
 
 func NewListener(ctx context.Context, address net.Address, port net.Port, streamSettings *internet.MemoryStreamConfig, addConn internet.ConnHandler) (*Listener, error) {
@@ -54,6 +56,7 @@ func NewListener(ctx context.Context, address net.Address, port net.Port, stream
 		sessions: make(map[ConnectionID]*Connection),
 		config:   kcpSettings,
 		addConn:  addConn,
+		counters: newCounters(ctx),
 	}
 
 	if config := tls.ConfigFromStreamSettings(streamSettings); config != nil {
@@ -119,15 +122,28 @@ func (l *Listener) OnReceive(payload *buf.Buffer, src net.Destination) {
 			Port: int(src.Port),
 		}
 		localAddr := l.hub.Addr()
+		config := l.config
+		if fecSeg, ok := segments[0].(*FECSegment); ok {
+			// FEC is only used with the shard counts of the listener.
+			dataShards, parityShards := config.GetFECShards()
+			if dataShards == 0 || int(fecSeg.DataShards) != dataShards || int(fecSeg.ParityShards) != parityShards {
+				newError("discarding FEC segment of ", fecSeg.DataShards, "+", fecSeg.ParityShards, " shards from ", src, ", as the FEC config of the listener differs").AtWarning().WriteToLog()
+				return
+			}
+		} else if config.Fec != nil {
+			config = proto.Clone(l.config).(*Config)
+			config.Fec = nil
+		}
 		conn = NewConnection(ConnMetadata{
 			LocalAddr:    localAddr,
 			RemoteAddr:   remoteAddr,
 			Conversation: conv,
+			Counters:     l.counters,
 		}, &KCPPacketWriter{
 			Header:   l.header,
 			Security: l.security,
 			Writer:   writer,
-		}, writer, l.config)
+		}, writer, config)
 		var netConn stat.Connection = conn
 		if l.tlsConfig != nil {
 			netConn = tls.Server(conn, l.tlsConfig)
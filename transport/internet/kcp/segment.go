@@ -18,6 +18,8 @@ const (
 	CommandTerminate Command = 2
 	// CommandPing indicates a ping.
 	CommandPing Command = 3
+	// CommandFEC indicates a FECSegment.
+	CommandFEC Command = 4
 )
 
 type SegmentOption byte
@@ -273,6 +275,96 @@ func (s *CmdOnlySegment) Serialize(b []byte) {
 
 func (*CmdOnlySegment) Release() {}
 
+const (
+	FECSegmentOverhead = 13
+)
+
+// FECSegment is a shard of a FEC group. Data shards carry a serialized segment, while parity shards carry the parity
+// of the data shards in the group.
+type FECSegment struct {
+	Conv         uint16
+	Option       SegmentOption
+	Group        uint32
+	Index        uint8
+	DataShards   uint8
+	ParityShards uint8
+
+	payload *buf.Buffer
+}
+
+func NewFECSegment() *FECSegment {
+	return new(FECSegment)
+}
+
+func (s *FECSegment) parse(conv uint16, cmd Command, opt SegmentOption, buf []byte) (bool, []byte) {
+	s.Conv = conv
+	s.Option = opt
+	if len(buf) < 9 {
+		return false, nil
+	}
+
+	s.Group = binary.BigEndian.Uint32(buf)
+	buf = buf[4:]
+
+	s.Index = buf[0]
+	s.DataShards = buf[1]
+	s.ParityShards = buf[2]
+	buf = buf[3:]
+
+	dataLen := int(binary.BigEndian.Uint16(buf))
+	buf = buf[2:]
+
+	if len(buf) < dataLen {
+		return false, nil
+	}
+	s.Data().Clear()
+	s.Data().Write(buf[:dataLen])
+	buf = buf[dataLen:]
+
+	return true, buf
+}
+
+func (s *FECSegment) Conversation() uint16 {
+	return s.Conv
+}
+
+func (*FECSegment) Command() Command {
+	return CommandFEC
+}
+
+// IsParity returns true if the segment is a parity shard.
+func (s *FECSegment) IsParity() bool {
+	return s.Index >= s.DataShards
+}
+
+func (s *FECSegment) Data() *buf.Buffer {
+	if s.payload == nil {
+		s.payload = buf.New()
+	}
+	return s.payload
+}
+
+func (s *FECSegment) Serialize(b []byte) {
+	binary.BigEndian.PutUint16(b, s.Conv)
+	b[2] = byte(CommandFEC)
+	b[3] = byte(s.Option)
+	binary.BigEndian.PutUint32(b[4:], s.Group)
+	b[8] = s.Index
+	b[9] = s.DataShards
+	b[10] = s.ParityShards
+	binary.BigEndian.PutUint16(b[11:], uint16(s.payload.Len()))
+	copy(b[13:], s.payload.Bytes())
+}
+
+func (s *FECSegment) ByteSize() int32 {
+	return 2 + 1 + 1 + 4 + 1 + 1 + 1 + 2 + s.payload.Len()
+}
+
+func (s *FECSegment) Release() {
+	s.payload.Release()
+	s.payload = nil
+}
+
 func ReadSegment(buf []byte) (Segment, []byte) {
 	if len(buf) < 4 {
 		return nil, nil
@@ -291,6 +383,8 @@ func ReadSegment(buf []byte) (Segment, []byte) {
 		seg = NewDataSegment()
 	case CommandACK:
 		seg = NewAckSegment()
+	case CommandFEC:
+		seg = NewFECSegment()
 	default:
 		seg = NewCmdOnlySegment()
 	}
@@ -283,6 +283,9 @@ func (w *SendingWorker) Write(seg Segment) error {
 	if w.conn.State() == StateReadyToClose {
 		dataSeg.Option = SegmentOptionClose
 	}
+	if dataSeg.transmit > 1 {
+		w.conn.meta.Counters.addRetransmitted(1)
+	}
 
 	return w.conn.output.Write(dataSeg)
 }
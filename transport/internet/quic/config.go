@@ -1,47 +1,34 @@
 package quic
 
 import (
-	"crypto/aes"
-	"crypto/cipher"
-	"crypto/sha256"
+	"context"
+	"time"
 
-	"github.com/xtls/xray-core/common"
-	"github.com/xtls/xray-core/common/protocol"
-	"github.com/xtls/xray-core/transport/internet"
-	"golang.org/x/crypto/chacha20poly1305"
+	"github.com/quic-go/quic-go"
+	"github.com/quic-go/quic-go/logging"
+	"github.com/quic-go/quic-go/qlog"
 )
 
-func getAuth(config *Config) (cipher.AEAD, error) {
-	security := config.Security.GetSecurityType()
-	if security == protocol.SecurityType_NONE {
-		return nil, nil
+func (c *Config) getMaxIdleTimeout() time.Duration {
+	if c.MaxIdleTimeout == 0 {
+		return time.Second * 300
 	}
-
-	salted := []byte(config.Key + "xray-quic-salt")
-	key := sha256.Sum256(salted)
-
-	if security == protocol.SecurityType_AES128_GCM {
-		block, err := aes.NewCipher(key[:16])
-		common.Must(err)
-		return cipher.NewGCM(block)
-	}
-
-	if security == protocol.SecurityType_CHACHA20_POLY1305 {
-		return chacha20poly1305.New(key[:])
-	}
-
-	return nil, newError("unsupported security type")
+	return time.Second * time.Duration(c.MaxIdleTimeout)
 }
 
-func getHeader(config *Config) (internet.PacketHeader, error) {
-	if config.Header == nil {
-		return nil, nil
-	}
+func (c *Config) getKeepAlivePeriod() time.Duration {
+	return time.Second * time.Duration(c.KeepAlivePeriod)
+}
 
-	msg, err := config.Header.GetInstance()
-	if err != nil {
-		return nil, err
+func (c *Config) getQUICConfig() *quic.Config {
+	return &quic.Config{
+		KeepAlivePeriod:      c.getKeepAlivePeriod(),
+		HandshakeIdleTimeout: time.Second * 8,
+		MaxIdleTimeout:       c.getMaxIdleTimeout(),
+		Allow0RTT:            c.ZeroRtt,
+		EnableDatagrams:      true,
+		Tracer: func(ctx context.Context, p logging.Perspective, ci quic.ConnectionID) *logging.ConnectionTracer {
+			return qlog.NewConnectionTracer(&QlogWriter{connID: ci}, p, ci)
+		},
 	}
-
-	return internet.CreatePacketHeader(msg)
 }
@@ -1,159 +1,63 @@
 package quic
 
 import (
-	"crypto/cipher"
-	"crypto/rand"
+	"context"
 	"errors"
-	"syscall"
+	"io"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/quic-go/quic-go"
-	"github.com/xtls/xray-core/common"
 	"github.com/xtls/xray-core/common/buf"
 	"github.com/xtls/xray-core/common/net"
-	"github.com/xtls/xray-core/transport/internet"
 )
 
+// The first byte of each stream tells how the connection over it is relayed.
+const (
+	// streamTypeStream relays the connection over the stream itself.
+	streamTypeStream byte = 0
+	// streamTypeDatagram relays the connection in datagrams, see datagramConn.
+	streamTypeDatagram byte = 1
+)
+
+// sysConn records when a packet is last received, so that the dialer can tell when the path stops working.
 type sysConn struct {
-	conn   *net.UDPConn
-	header internet.PacketHeader
-	auth   cipher.AEAD
+	net.PacketConn
+	lastRead atomic.Int64
 }
 
-func wrapSysConn(rawConn *net.UDPConn, config *Config) (*sysConn, error) {
-	header, err := getHeader(config)
-	if err != nil {
-		return nil, err
-	}
-	auth, err := getAuth(config)
-	if err != nil {
-		return nil, err
+func wrapSysConn(conn net.PacketConn) *sysConn {
+	c := &sysConn{
+		PacketConn: conn,
 	}
-	return &sysConn{
-		conn:   rawConn,
-		header: header,
-		auth:   auth,
-	}, nil
-}
-
-var errInvalidPacket = errors.New("invalid packet")
-
-func (c *sysConn) readFromInternal(p []byte) (int, net.Addr, error) {
-	buffer := getBuffer()
-	defer putBuffer(buffer)
-
-	nBytes, addr, err := c.conn.ReadFrom(buffer)
-	if err != nil {
-		return 0, nil, err
-	}
-
-	payload := buffer[:nBytes]
-	if c.header != nil {
-		if len(payload) <= int(c.header.Size()) {
-			return 0, nil, errInvalidPacket
-		}
-		payload = payload[c.header.Size():]
-	}
-
-	if c.auth == nil {
-		n := copy(p, payload)
-		return n, addr, nil
-	}
-
-	if len(payload) <= c.auth.NonceSize() {
-		return 0, nil, errInvalidPacket
-	}
-
-	nonce := payload[:c.auth.NonceSize()]
-	payload = payload[c.auth.NonceSize():]
-
-	p, err = c.auth.Open(p[:0], nonce, payload, nil)
-	if err != nil {
-		return 0, nil, errInvalidPacket
-	}
-
-	return len(p), addr, nil
+	c.lastRead.Store(time.Now().UnixNano())
+	return c
 }
 
 func (c *sysConn) ReadFrom(p []byte) (int, net.Addr, error) {
-	if c.header == nil && c.auth == nil {
-		return c.conn.ReadFrom(p)
-	}
-
-	for {
-		n, addr, err := c.readFromInternal(p)
-		if err != nil && err != errInvalidPacket {
-			return 0, nil, err
-		}
-		if err == nil {
-			return n, addr, nil
-		}
-	}
-}
-
-func (c *sysConn) WriteTo(p []byte, addr net.Addr) (int, error) {
-	if c.header == nil && c.auth == nil {
-		return c.conn.WriteTo(p, addr)
-	}
-
-	buffer := getBuffer()
-	defer putBuffer(buffer)
-
-	payload := buffer
-	n := 0
-	if c.header != nil {
-		c.header.Serialize(payload)
-		n = int(c.header.Size())
-	}
-
-	if c.auth == nil {
-		nBytes := copy(payload[n:], p)
-		n += nBytes
-	} else {
-		nounce := payload[n : n+c.auth.NonceSize()]
-		common.Must2(rand.Read(nounce))
-		n += c.auth.NonceSize()
-		pp := c.auth.Seal(payload[:n], nounce, p, nil)
-		n = len(pp)
+	n, addr, err := c.PacketConn.ReadFrom(p)
+	if err == nil {
+		c.lastRead.Store(time.Now().UnixNano())
 	}
-
-	return c.conn.WriteTo(payload[:n], addr)
-}
-
-func (c *sysConn) Close() error {
-	return c.conn.Close()
-}
-
-func (c *sysConn) LocalAddr() net.Addr {
-	return c.conn.LocalAddr()
-}
-
-func (c *sysConn) SetReadBuffer(bytes int) error {
-	return c.conn.SetReadBuffer(bytes)
-}
-
-func (c *sysConn) SetWriteBuffer(bytes int) error {
-	return c.conn.SetWriteBuffer(bytes)
-}
-
-func (c *sysConn) SetDeadline(t time.Time) error {
-	return c.conn.SetDeadline(t)
+	return n, addr, err
 }
 
-func (c *sysConn) SetReadDeadline(t time.Time) error {
-	return c.conn.SetReadDeadline(t)
+// idleTime returns the time since a packet is last received.
+func (c *sysConn) idleTime() time.Duration {
+	return time.Duration(time.Now().UnixNano() - c.lastRead.Load())
 }
 
-func (c *sysConn) SetWriteDeadline(t time.Time) error {
-	return c.conn.SetWriteDeadline(t)
-}
-
-func (c *sysConn) SyscallConn() (syscall.RawConn, error) {
-	return c.conn.SyscallConn()
+// stream is the part of quic.Stream used by interConn.
+type stream interface {
+	io.ReadWriteCloser
+	SetDeadline(t time.Time) error
+	SetReadDeadline(t time.Time) error
+	SetWriteDeadline(t time.Time) error
 }
 
 type interConn struct {
-	stream quic.Stream
+	stream stream
 	local  net.Addr
 	remote net.Addr
 }
@@ -196,3 +100,123 @@ func (c *interConn) SetReadDeadline(t time.Time) error {
 func (c *interConn) SetWriteDeadline(t time.Time) error {
 	return c.stream.SetWriteDeadline(t)
 }
+
+// earlyStream is a stream opened in 0-RTT. What is written to it is kept until the handshake completes, so that it is
+// sent again on a new stream if the server rejects 0-RTT.
+type earlyStream struct {
+	conn quic.EarlyConnection
+
+	access sync.Mutex
+	stream quic.Stream
+	// written is what has been written since the stream is opened, including the stream type, or nil once 0-RTT is
+	// accepted or rejected.
+	written       []byte
+	readDeadline  time.Time
+	writeDeadline time.Time
+}
+
+func newEarlyStream(conn quic.EarlyConnection, stream quic.Stream, streamType byte) (*earlyStream, error) {
+	s := &earlyStream{
+		conn:    conn,
+		stream:  stream,
+		written: []byte{streamType},
+	}
+	if _, err := stream.Write(s.written); err != nil && !errors.Is(err, quic.Err0RTTRejected) {
+		return nil, err
+	}
+	return s, nil
+}
+
+// current returns the stream in use, and records b as written if the handshake is not complete.
+func (s *earlyStream) current(b []byte) quic.Stream {
+	s.access.Lock()
+	defer s.access.Unlock()
+
+	if s.written != nil && isHandshakeComplete(s.conn) && s.conn.ConnectionState().Used0RTT {
+		s.written = nil
+	}
+	if s.written != nil {
+		s.written = append(s.written, b...)
+	}
+	return s.stream
+}
+
+// reopen opens a new stream in place of the given one rejected in 0-RTT, and sends what is written again.
+func (s *earlyStream) reopen(rejected quic.Stream) error {
+	s.access.Lock()
+	defer s.access.Unlock()
+
+	if s.stream != rejected {
+		// Reopened by the other direction.
+		return nil
+	}
+	newError("0-RTT rejected, opening stream again").AtDebug().WriteToLog()
+	conn, err := s.conn.NextConnection(context.Background())
+	if err != nil {
+		return err
+	}
+	stream, err := conn.OpenStream()
+	if err != nil {
+		return err
+	}
+	stream.SetReadDeadline(s.readDeadline)
+	stream.SetWriteDeadline(s.writeDeadline)
+	if _, err := stream.Write(s.written); err != nil {
+		stream.CancelRead(0)
+		stream.Close()
+		return err
+	}
+	s.stream = stream
+	s.written = nil
+	return nil
+}
+
+func (s *earlyStream) Read(b []byte) (int, error) {
+	stream := s.current(nil)
+	n, err := stream.Read(b)
+	if errors.Is(err, quic.Err0RTTRejected) {
+		if err := s.reopen(stream); err != nil {
+			return 0, err
+		}
+		return s.current(nil).Read(b)
+	}
+	return n, err
+}
+
+func (s *earlyStream) Write(b []byte) (int, error) {
+	stream := s.current(b)
+	n, err := stream.Write(b)
+	if errors.Is(err, quic.Err0RTTRejected) {
+		// b is sent again on the new stream.
+		if err := s.reopen(stream); err != nil {
+			return 0, err
+		}
+		return len(b), nil
+	}
+	return n, err
+}
+
+func (s *earlyStream) Close() error {
+	return s.current(nil).Close()
+}
+
+func (s *earlyStream) SetDeadline(t time.Time) error {
+	s.SetReadDeadline(t)
+	return s.SetWriteDeadline(t)
+}
+
+func (s *earlyStream) SetReadDeadline(t time.Time) error {
+	s.access.Lock()
+	defer s.access.Unlock()
+
+	s.readDeadline = t
+	return s.stream.SetReadDeadline(t)
+}
+
+func (s *earlyStream) SetWriteDeadline(t time.Time) error {
+	s.access.Lock()
+	defer s.access.Unlock()
+
+	s.writeDeadline = t
+	return s.stream.SetWriteDeadline(t)
+}
@@ -0,0 +1,247 @@
+package quic
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/quic-go/quic-go"
+	"github.com/quic-go/quic-go/quicvarint"
+	"github.com/xtls/xray-core/common/buf"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/signal/done"
+)
+
+// datagramQueueSize is the number of received datagrams queued for each connection before they are dropped.
+const datagramQueueSize = 64
+
+// datagramDispatcher delivers the datagrams received by a QUIC connection to the datagramConn of their stream.
+type datagramDispatcher struct {
+	conn quic.Connection
+
+	access sync.Mutex
+	conns  map[quic.StreamID]*datagramConn
+}
+
+func newDatagramDispatcher(conn quic.Connection) *datagramDispatcher {
+	d := &datagramDispatcher{
+		conn:  conn,
+		conns: make(map[quic.StreamID]*datagramConn),
+	}
+	go d.receive()
+	return d
+}
+
+func (d *datagramDispatcher) receive() {
+	for {
+		b, err := d.conn.ReceiveDatagram(context.Background())
+		if err != nil {
+			return
+		}
+		id, n, err := quicvarint.Parse(b)
+		if err != nil {
+			continue
+		}
+		d.access.Lock()
+		conn, found := d.conns[quic.StreamID(id)]
+		d.access.Unlock()
+		if found {
+			conn.deliver(b[n:])
+		}
+	}
+}
+
+func (d *datagramDispatcher) add(conn *datagramConn) {
+	d.access.Lock()
+	defer d.access.Unlock()
+
+	d.conns[conn.stream.StreamID()] = conn
+}
+
+func (d *datagramDispatcher) remove(conn *datagramConn) {
+	d.access.Lock()
+	defer d.access.Unlock()
+
+	delete(d.conns, conn.stream.StreamID())
+}
+
+// datagramConn relays a connection to a UDP destination over a stream and the datagrams tagged with the stream ID.
+// Each write is sent as a whole in one datagram if it fits, so proxy protocols that write each packet at once, such
+// as VLESS and Trojan, lose packets instead of the connection when datagrams are lost. Other protocols are refused
+// when the config is built. The first write in each
+// direction usually carries the header of the proxy protocol, so it is always sent over the stream, as well as the
+// writes too large for a datagram. On the stream, each write is framed by a 2-byte length.
+type datagramConn struct {
+	interConn
+	stream     quic.Stream
+	conn       quic.Connection
+	dispatcher *datagramDispatcher
+	prefix     []byte
+	done       *done.Instance
+
+	writeAccess sync.Mutex
+	wroteFirst  bool
+
+	frames    chan buf.MultiBuffer
+	datagrams chan []byte
+	readFirst bool
+	leftover  buf.MultiBuffer
+}
+
+func newDatagramConn(conn quic.Connection, dispatcher *datagramDispatcher, stream quic.Stream, remote net.Addr) *datagramConn {
+	c := &datagramConn{
+		interConn: interConn{
+			stream: stream,
+			local:  conn.LocalAddr(),
+			remote: remote,
+		},
+		stream:     stream,
+		conn:       conn,
+		dispatcher: dispatcher,
+		prefix:     quicvarint.Append(nil, uint64(stream.StreamID())),
+		done:       done.New(),
+		frames:     make(chan buf.MultiBuffer, 1),
+		datagrams:  make(chan []byte, datagramQueueSize),
+	}
+	dispatcher.add(c)
+	go c.readFrames()
+	return c
+}
+
+func (c *datagramConn) readFrames() {
+	defer close(c.frames)
+
+	var header [2]byte
+	for {
+		if _, err := io.ReadFull(c.stream, header[:]); err != nil {
+			return
+		}
+		size := int32(binary.BigEndian.Uint16(header[:]))
+		var mb buf.MultiBuffer
+		for size > 0 {
+			b := buf.New()
+			if _, err := b.ReadFullFrom(c.stream, min(size, buf.Size)); err != nil {
+				b.Release()
+				buf.ReleaseMulti(mb)
+				return
+			}
+			size -= b.Len()
+			mb = append(mb, b)
+		}
+		select {
+		case c.frames <- mb:
+		case <-c.done.Wait():
+			buf.ReleaseMulti(mb)
+			return
+		}
+	}
+}
+
+func (c *datagramConn) deliver(b []byte) {
+	select {
+	case c.datagrams <- b:
+	default:
+	}
+}
+
+// ReadMultiBuffer implements buf.Reader. It returns what is sent in one write by the peer.
+func (c *datagramConn) ReadMultiBuffer() (buf.MultiBuffer, error) {
+	if !c.leftover.IsEmpty() {
+		mb := c.leftover
+		c.leftover = nil
+		return mb, nil
+	}
+
+	// Datagrams are not read before the first frame, which carries the header of the proxy protocol.
+	datagrams := c.datagrams
+	if !c.readFirst {
+		datagrams = nil
+	}
+
+	select {
+	case mb, ok := <-c.frames:
+		if !ok {
+			return nil, io.EOF
+		}
+		c.readFirst = true
+		return mb, nil
+	case b := <-datagrams:
+		return buf.MergeBytes(nil, b), nil
+	case <-c.done.Wait():
+		return nil, io.ErrClosedPipe
+	}
+}
+
+func (c *datagramConn) Read(b []byte) (int, error) {
+	if c.leftover.IsEmpty() {
+		mb, err := c.ReadMultiBuffer()
+		if err != nil {
+			return 0, err
+		}
+		c.leftover = mb
+	}
+	var n int
+	c.leftover, n = buf.SplitBytes(c.leftover, b)
+	return n, nil
+}
+
+// WriteMultiBuffer implements buf.Writer. The MultiBuffer is sent as a whole.
+func (c *datagramConn) WriteMultiBuffer(mb buf.MultiBuffer) error {
+	defer buf.ReleaseMulti(mb)
+
+	b := make([]byte, len(c.prefix)+int(mb.Len()))
+	copy(b, c.prefix)
+	mb.Copy(b[len(c.prefix):])
+	return c.write(b)
+}
+
+func (c *datagramConn) Write(b []byte) (int, error) {
+	p := make([]byte, len(c.prefix)+len(b))
+	copy(p, c.prefix)
+	copy(p[len(c.prefix):], b)
+	if err := c.write(p); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// write sends the payload after the prefix in b.
+func (c *datagramConn) write(b []byte) error {
+	if c.done.Done() {
+		return io.ErrClosedPipe
+	}
+	if len(b) == len(c.prefix) {
+		return nil
+	}
+
+	c.writeAccess.Lock()
+	defer c.writeAccess.Unlock()
+
+	if c.wroteFirst {
+		err := c.conn.SendDatagram(b)
+		var tooLarge *quic.DatagramTooLargeError
+		if !errors.As(err, &tooLarge) {
+			return err
+		}
+	}
+	c.wroteFirst = true
+
+	payload := b[len(c.prefix):]
+	for len(payload) > 0 {
+		frame := payload[:min(len(payload), 0xFFFF)]
+		payload = payload[len(frame):]
+		if _, err := c.stream.Write(append(binary.BigEndian.AppendUint16(nil, uint16(len(frame))), frame...)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *datagramConn) Close() error {
+	c.done.Close()
+	c.dispatcher.remove(c)
+	c.stream.CancelRead(0)
+	return c.stream.Close()
+}
@@ -6,10 +6,9 @@ import (
 	"time"
 
 	"github.com/quic-go/quic-go"
-	"github.com/quic-go/quic-go/logging"
-	"github.com/quic-go/quic-go/qlog"
 	"github.com/xtls/xray-core/common"
 	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/session"
 	"github.com/xtls/xray-core/common/task"
 	"github.com/xtls/xray-core/transport/internet"
 	"github.com/xtls/xray-core/transport/internet/stat"
@@ -17,22 +16,70 @@ import (
 )
 
 type connectionContext struct {
-	rawConn *sysConn
-	conn    quic.Connection
+	conn      quic.Connection
+	datagrams *datagramDispatcher
+
+	pathAccess sync.Mutex
+	// path is the socket the connection currently sends on.
+	path *sysConn
+	// transports are all the transports the connection has used, which are closed with it.
+	transports []*quic.Transport
 }
 
 var errConnectionClosed = newError("connection closed")
 
-func (c *connectionContext) openStream(destAddr net.Addr) (*interConn, error) {
+func (c *connectionContext) openStream(ctx context.Context, destAddr net.Addr, datagram bool) (stat.Connection, error) {
 	if !isActive(c.conn) {
 		return nil, errConnectionClosed
 	}
 
 	stream, err := c.conn.OpenStream()
 	if err != nil {
-		return nil, err
+		// Streams cannot be opened before the handshake completes without a resumed session, nor on a connection
+		// whose 0-RTT data is rejected.
+		early, ok := c.conn.(quic.EarlyConnection)
+		if !ok {
+			return nil, err
+		}
+		conn, err := early.NextConnection(ctx)
+		if err != nil {
+			return nil, err
+		}
+		c.conn = conn
+		if stream, err = conn.OpenStream(); err != nil {
+			return nil, err
+		}
+	}
+
+	if early, ok := c.conn.(quic.EarlyConnection); ok && !isHandshakeComplete(early) {
+		// The stream is opened in 0-RTT. Datagrams are not used, as they are lost if 0-RTT is rejected.
+		s, err := newEarlyStream(early, stream, streamTypeStream)
+		if err != nil {
+			stream.Close()
+			return nil, err
+		}
+		return &interConn{
+			stream: s,
+			local:  c.conn.LocalAddr(),
+			remote: destAddr,
+		}, nil
+	}
+
+	if datagram && c.conn.ConnectionState().SupportsDatagrams {
+		if _, err := stream.Write([]byte{streamTypeDatagram}); err != nil {
+			stream.Close()
+			return nil, err
+		}
+		if c.datagrams == nil {
+			c.datagrams = newDatagramDispatcher(c.conn)
+		}
+		return newDatagramConn(c.conn, c.datagrams, stream, destAddr), nil
 	}
 
+	if _, err := stream.Write([]byte{streamTypeStream}); err != nil {
+		stream.Close()
+		return nil, err
+	}
 	conn := &interConn{
 		stream: stream,
 		local:  c.conn.LocalAddr(),
@@ -42,12 +89,103 @@ func (c *connectionContext) openStream(destAddr net.Addr) (*interConn, error) {
 	return conn, nil
 }
 
+// watchPath migrates the connection to a new socket once nothing is received for two keep-alive periods, and closes
+// the connection if the new socket does not work either.
+func (c *connectionContext) watchPath(dest net.Destination, sockopt *internet.SocketConfig, keepAlivePeriod time.Duration) {
+	conn := c.conn
+	ticker := time.NewTicker(keepAlivePeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-conn.Context().Done():
+			return
+		case <-ticker.C:
+		}
+
+		c.pathAccess.Lock()
+		idleTime := c.path.idleTime()
+		c.pathAccess.Unlock()
+		if idleTime < keepAlivePeriod*2 {
+			continue
+		}
+
+		newError("migrating quic connection to ", dest).AtInfo().WriteToLog()
+		if err := c.migrate(conn, dest, sockopt, keepAlivePeriod*2); err != nil {
+			newError("failed to migrate quic connection to ", dest).Base(err).AtWarning().WriteToLog()
+			if err := conn.CloseWithError(0, ""); err != nil {
+				newError("failed to close connection").Base(err).WriteToLog()
+			}
+			return
+		}
+	}
+}
+
+func (c *connectionContext) migrate(conn quic.Connection, dest net.Destination, sockopt *internet.SocketConfig, timeout time.Duration) error {
+	rawConn, err := dialPacketConn(context.Background(), dest, sockopt)
+	if err != nil {
+		return err
+	}
+	sysConn := wrapSysConn(rawConn)
+	tr := &quic.Transport{
+		ConnectionIDLength: 12,
+		Conn:               sysConn,
+	}
+	c.pathAccess.Lock()
+	c.transports = append(c.transports, tr)
+	c.pathAccess.Unlock()
+
+	path, err := conn.AddPath(tr)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := path.Probe(ctx); err != nil {
+		path.Close()
+		return newError("failed to probe new path").Base(err)
+	}
+	if err := path.Switch(); err != nil {
+		path.Close()
+		return newError("failed to switch to new path").Base(err)
+	}
+
+	c.pathAccess.Lock()
+	c.path = sysConn
+	c.pathAccess.Unlock()
+	return nil
+}
+
+func (c *connectionContext) close() {
+	if err := c.conn.CloseWithError(0, ""); err != nil {
+		newError("failed to close connection").Base(err).WriteToLog()
+	}
+
+	c.pathAccess.Lock()
+	defer c.pathAccess.Unlock()
+	for _, tr := range c.transports {
+		tr.Close()
+		if err := tr.Conn.Close(); err != nil {
+			newError("failed to close raw connection").Base(err).WriteToLog()
+		}
+	}
+}
+
 type clientConnections struct {
 	access  sync.Mutex
 	conns   map[net.Destination][]*connectionContext
 	cleanup *task.Periodic
 }
 
+func isHandshakeComplete(conn quic.EarlyConnection) bool {
+	select {
+	case <-conn.HandshakeComplete():
+		return true
+	default:
+		return false
+	}
+}
+
 func isActive(s quic.Connection) bool {
 	select {
 	case <-s.Context().Done():
@@ -66,12 +204,7 @@ func removeInactiveConnections(conns []*connectionContext) []*connectionContext
 		}
 
 		newError("closing quic connection at index: ", i).WriteToLog()
-		if err := s.conn.CloseWithError(0, ""); err != nil {
-			newError("failed to close connection").Base(err).WriteToLog()
-		}
-		if err := s.rawConn.Close(); err != nil {
-			newError("failed to close raw connection").Base(err).WriteToLog()
-		}
+		s.close()
 	}
 
 	if len(activeConnections) < len(conns) {
@@ -103,6 +236,24 @@ func (s *clientConnections) cleanConnections() error {
 	return nil
 }
 
+func dialPacketConn(ctx context.Context, dest net.Destination, sockopt *internet.SocketConfig) (net.PacketConn, error) {
+	rawConn, err := internet.DialSystem(ctx, dest, sockopt)
+	if err != nil {
+		return nil, newError("failed to dial to dest: ", err).AtWarning().Base(err)
+	}
+
+	switch conn := rawConn.(type) {
+	case *net.UDPConn:
+		return conn, nil
+	case *internet.PacketConnWrapper:
+		return conn.Conn, nil
+	default:
+		// TODO: Support sockopt for QUIC
+		rawConn.Close()
+		return nil, newError("QUIC with sockopt is unsupported").AtWarning()
+	}
+}
+
 func (s *clientConnections) openConnection(ctx context.Context, destAddr net.Addr, config *Config, tlsConfig *tls.Config, sockopt *internet.SocketConfig) (stat.Connection, error) {
 	s.access.Lock()
 	defer s.access.Unlock()
@@ -113,6 +264,12 @@ func (s *clientConnections) openConnection(ctx context.Context, destAddr net.Add
 
 	dest := net.DestinationFromAddr(destAddr)
 
+	// Connections to UDP destinations are relayed in datagrams if enabled.
+	datagram := false
+	if outbound := session.OutboundFromContext(ctx); outbound != nil && config.Datagram {
+		datagram = outbound.Target.Network == net.Network_UDP
+	}
+
 	var conns []*connectionContext
 	if s, found := s.conns[dest]; found {
 		conns = s
@@ -121,7 +278,7 @@ func (s *clientConnections) openConnection(ctx context.Context, destAddr net.Add
 	if len(conns) > 0 {
 		s := conns[len(conns)-1]
 		if isActive(s.conn) {
-			conn, err := s.openStream(destAddr)
+			conn, err := s.openStream(ctx, destAddr, datagram)
 			if err == nil {
 				return conn, nil
 			}
@@ -133,53 +290,41 @@ func (s *clientConnections) openConnection(ctx context.Context, destAddr net.Add
 
 	conns = removeInactiveConnections(conns)
 	newError("dialing quic to ", dest).WriteToLog()
-	rawConn, err := internet.DialSystem(ctx, dest, sockopt)
-	if err != nil {
-		return nil, newError("failed to dial to dest: ", err).AtWarning().Base(err)
-	}
-
-	quicConfig := &quic.Config{
-		KeepAlivePeriod:      0,
-		HandshakeIdleTimeout: time.Second * 8,
-		MaxIdleTimeout:       time.Second * 300,
-		Tracer: func(ctx context.Context, p logging.Perspective, ci quic.ConnectionID) *logging.ConnectionTracer {
-			return qlog.NewConnectionTracer(&QlogWriter{connID: ci}, p, ci)
-		},
-	}
-
-	var udpConn *net.UDPConn
-	switch conn := rawConn.(type) {
-	case *net.UDPConn:
-		udpConn = conn
-	case *internet.PacketConnWrapper:
-		udpConn = conn.Conn.(*net.UDPConn)
-	default:
-		// TODO: Support sockopt for QUIC
-		rawConn.Close()
-		return nil, newError("QUIC with sockopt is unsupported").AtWarning()
-	}
-
-	sysConn, err := wrapSysConn(udpConn, config)
+	rawConn, err := dialPacketConn(ctx, dest, sockopt)
 	if err != nil {
-		rawConn.Close()
 		return nil, err
 	}
-	tr := quic.Transport{
+
+	sysConn := wrapSysConn(rawConn)
+	tr := &quic.Transport{
 		ConnectionIDLength: 12,
 		Conn:               sysConn,
 	}
-	conn, err := tr.Dial(context.Background(), destAddr, tlsConfig.GetTLSConfig(tls.WithDestination(dest)), quicConfig)
+	quicConfig := config.getQUICConfig()
+	goTLSConfig := tlsConfig.GetTLSConfig(tls.WithDestination(dest))
+	var conn quic.Connection
+	if config.ZeroRtt {
+		goTLSConfig.SessionTicketsDisabled = false
+		conn, err = tr.DialEarly(context.Background(), destAddr, goTLSConfig, quicConfig)
+	} else {
+		conn, err = tr.Dial(context.Background(), destAddr, goTLSConfig, quicConfig)
+	}
 	if err != nil {
+		tr.Close()
 		sysConn.Close()
 		return nil, err
 	}
 
 	context := &connectionContext{
-		conn:    conn,
-		rawConn: sysConn,
+		conn:       conn,
+		path:       sysConn,
+		transports: []*quic.Transport{tr},
+	}
+	if config.Migration && config.KeepAlivePeriod > 0 {
+		go context.watchPath(dest, sockopt, config.getKeepAlivePeriod())
 	}
 	s.conns[dest] = append(conns, context)
-	return context.openStream(destAddr)
+	return context.openStream(ctx, destAddr, datagram)
 }
 
 var client clientConnections
@@ -208,7 +353,7 @@ func Dial(ctx context.Context, dest net.Destination, streamSettings *internet.Me
 			IP:   dest.Address.IP(),
 			Port: int(dest.Port),
 		}
-	}  else {
+	} else {
 		dialerIp := internet.DestIpAddress()
 		if dialerIp != nil {
 			destAddr = &net.UDPAddr{
@@ -0,0 +1,105 @@
+package quic
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"io"
+	"testing"
+
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/protocol/tls/cert"
+	"github.com/xtls/xray-core/testing/servers/udp"
+	"github.com/xtls/xray-core/transport/internet"
+	"github.com/xtls/xray-core/transport/internet/stat"
+	"github.com/xtls/xray-core/transport/internet/tls"
+)
+
+var zeroRTTCertificate = cert.MustGenerate(nil, cert.DNSNames("0rtt.example.com"))
+
+func listenEcho(port net.Port, zeroRTT bool) internet.Listener {
+	listener, err := Listen(context.Background(), net.LocalHostIP, port, &internet.MemoryStreamConfig{
+		ProtocolName:     "quic",
+		ProtocolSettings: &Config{ZeroRtt: zeroRTT},
+		SecurityType:     "tls",
+		SecuritySettings: &tls.Config{
+			Certificate: []*tls.Certificate{tls.ParseCertificate(zeroRTTCertificate)},
+		},
+	}, func(conn stat.Connection) {
+		go func() {
+			defer conn.Close()
+			io.Copy(conn, conn)
+		}()
+	})
+	common.Must(err)
+	return listener
+}
+
+// closeClientConnections closes the connections of the dialer, so that the next dial makes a new one.
+func closeClientConnections() {
+	client.access.Lock()
+	defer client.access.Unlock()
+
+	for _, conns := range client.conns {
+		for _, c := range conns {
+			c.close()
+		}
+	}
+	client.conns = make(map[net.Destination][]*connectionContext)
+}
+
+// echo dials the port, and checks what is written is echoed. It returns whether the connection used 0-RTT.
+func echo(t *testing.T, port net.Port) bool {
+	conn, err := Dial(context.Background(), net.UDPDestination(net.LocalHostIP, port), &internet.MemoryStreamConfig{
+		ProtocolName:     "quic",
+		ProtocolSettings: &Config{ZeroRtt: true},
+		SecurityType:     "tls",
+		SecuritySettings: &tls.Config{
+			ServerName:    "0rtt.example.com",
+			AllowInsecure: true,
+		},
+	})
+	common.Must(err)
+	defer conn.Close()
+
+	b := make([]byte, 1024)
+	common.Must2(rand.Read(b))
+	common.Must2(conn.Write(b))
+	echoed := make([]byte, len(b))
+	if _, err := io.ReadFull(conn, echoed); err != nil {
+		t.Fatal("failed to read echo: ", err)
+	}
+	if !bytes.Equal(b, echoed) {
+		t.Fatal("echo does not match")
+	}
+
+	client.access.Lock()
+	defer client.access.Unlock()
+	conns := client.conns[net.UDPDestination(net.LocalHostIP, port)]
+	return conns[len(conns)-1].conn.ConnectionState().Used0RTT
+}
+
+func TestQuic0RTT(t *testing.T) {
+	port := udp.PickPort()
+	listener := listenEcho(port, true)
+	defer closeClientConnections()
+
+	// The first connection gets the session ticket.
+	if echo(t, port) {
+		t.Error("first connection used 0-RTT")
+	}
+	closeClientConnections()
+	if !echo(t, port) {
+		t.Error("resumed connection did not use 0-RTT")
+	}
+	closeClientConnections()
+
+	// The new listener rejects 0-RTT, and what is sent in it is sent again.
+	listener.Close()
+	listener = listenEcho(port, false)
+	defer listener.Close()
+	if echo(t, port) {
+		t.Error("0-RTT accepted by listener without 0-RTT")
+	}
+}
@@ -2,11 +2,10 @@ package quic
 
 import (
 	"context"
+	"io"
 	"time"
 
 	"github.com/quic-go/quic-go"
-	"github.com/quic-go/quic-go/logging"
-	"github.com/quic-go/quic-go/qlog"
 	"github.com/xtls/xray-core/common"
 	"github.com/xtls/xray-core/common/net"
 	"github.com/xtls/xray-core/common/protocol/tls/cert"
@@ -17,13 +16,18 @@ import (
 
 // Listener is an internet.Listener that listens for TCP connections.
 type Listener struct {
-	rawConn  *sysConn
-	listener *quic.Listener
+	rawConn  net.PacketConn
+	listener *quic.EarlyListener
 	done     *done.Instance
 	addConn  internet.ConnHandler
 }
 
 func (l *Listener) acceptStreams(conn quic.Connection) {
+	var datagrams *datagramDispatcher
+	if conn.ConnectionState().SupportsDatagrams {
+		datagrams = newDatagramDispatcher(conn)
+	}
+
 	for {
 		stream, err := conn.AcceptStream(context.Background())
 		if err != nil {
@@ -42,13 +46,32 @@ func (l *Listener) acceptStreams(conn quic.Connection) {
 			}
 		}
 
-		conn := &interConn{
+		go l.handleStream(conn, datagrams, stream)
+	}
+}
+
+func (l *Listener) handleStream(conn quic.Connection, datagrams *datagramDispatcher, stream quic.Stream) {
+	var streamType [1]byte
+	if _, err := io.ReadFull(stream, streamType[:]); err != nil {
+		newError("failed to read stream type").Base(err).WriteToLog()
+		stream.CancelRead(0)
+		stream.Close()
+		return
+	}
+
+	switch {
+	case streamType[0] == streamTypeStream:
+		l.addConn(&interConn{
 			stream: stream,
 			local:  conn.LocalAddr(),
 			remote: conn.RemoteAddr(),
-		}
-
-		l.addConn(conn)
+		})
+	case streamType[0] == streamTypeDatagram && datagrams != nil:
+		l.addConn(newDatagramConn(conn, datagrams, stream, conn.RemoteAddr()))
+	default:
+		newError("unknown stream type: ", streamType[0]).AtWarning().WriteToLog()
+		stream.CancelRead(0)
+		stream.Close()
 	}
 }
 
@@ -102,35 +125,26 @@ func Listen(ctx context.Context, address net.Address, port net.Port, streamSetti
 		return nil, err
 	}
 
-	quicConfig := &quic.Config{
-		KeepAlivePeriod:       0,
-		HandshakeIdleTimeout:  time.Second * 8,
-		MaxIdleTimeout:        time.Second * 300,
-		MaxIncomingStreams:    32,
-		MaxIncomingUniStreams: -1,
-		Tracer: func(ctx context.Context, p logging.Perspective, ci quic.ConnectionID) *logging.ConnectionTracer {
-			return qlog.NewConnectionTracer(&QlogWriter{connID: ci}, p, ci)
-		},
-	}
+	quicConfig := config.getQUICConfig()
+	quicConfig.MaxIncomingStreams = 32
+	quicConfig.MaxIncomingUniStreams = -1
 
-	conn, err := wrapSysConn(rawConn.(*net.UDPConn), config)
-	if err != nil {
-		conn.Close()
-		return nil, err
-	}
+	goTLSConfig := tlsConfig.GetTLSConfig()
+	// quic-go fails the handshake if session tickets are disabled, and 0-RTT data is sent when resuming a session.
+	goTLSConfig.SessionTicketsDisabled = false
 	tr := quic.Transport{
 		ConnectionIDLength: 12,
-		Conn:               conn,
+		Conn:               rawConn,
 	}
-	qListener, err := tr.Listen(tlsConfig.GetTLSConfig(), quicConfig)
+	qListener, err := tr.ListenEarly(goTLSConfig, quicConfig)
 	if err != nil {
-		conn.Close()
+		rawConn.Close()
 		return nil, err
 	}
 
 	listener := &Listener{
 		done:     done.New(),
-		rawConn:  conn,
+		rawConn:  rawConn,
 		listener: qListener,
 		addConn:  handler,
 	}
@@ -7,12 +7,6 @@ import (
 
 //go:generate go run github.com/xtls/xray-core/common/errors/errorgen
 
-// Here is some modification needs to be done before update quic vendor.
-// * use bytespool in buffer_pool.go
-// * set MaxReceivePacketSize to 1452 - 32 (16 bytes auth, 16 bytes head)
-//
-//
-
 const (
 	protocolName   = "quic"
 	internalDomain = "quic.internal.example.com"
@@ -3,6 +3,7 @@ package quic_test
 import (
 	"context"
 	"crypto/rand"
+	"sync"
 	"testing"
 	"time"
 
@@ -10,12 +11,10 @@ import (
 	"github.com/xtls/xray-core/common"
 	"github.com/xtls/xray-core/common/buf"
 	"github.com/xtls/xray-core/common/net"
-	"github.com/xtls/xray-core/common/protocol"
 	"github.com/xtls/xray-core/common/protocol/tls/cert"
-	"github.com/xtls/xray-core/common/serial"
+	"github.com/xtls/xray-core/common/session"
 	"github.com/xtls/xray-core/testing/servers/udp"
 	"github.com/xtls/xray-core/transport/internet"
-	"github.com/xtls/xray-core/transport/internet/headers/wireguard"
 	"github.com/xtls/xray-core/transport/internet/quic"
 	"github.com/xtls/xray-core/transport/internet/stat"
 	"github.com/xtls/xray-core/transport/internet/tls"
@@ -152,49 +151,169 @@ func TestQuicConnectionWithoutTLS(t *testing.T) {
 	}
 }
 
-func TestQuicConnectionAuthHeader(t *testing.T) {
+func echo(conn stat.Connection) {
+	go func() {
+		defer conn.Close()
+
+		b := buf.New()
+		defer b.Release()
+
+		for {
+			b.Clear()
+			if _, err := b.ReadFrom(conn); err != nil {
+				return
+			}
+			if _, err := conn.Write(b.Bytes()); err != nil {
+				return
+			}
+		}
+	}()
+}
+
+func TestQuicDatagram(t *testing.T) {
 	port := udp.PickPort()
 
 	listener, err := quic.Listen(context.Background(), net.LocalHostIP, port, &internet.MemoryStreamConfig{
+		ProtocolName:     "quic",
+		ProtocolSettings: &quic.Config{},
+	}, echo)
+	common.Must(err)
+	defer listener.Close()
+
+	time.Sleep(time.Second)
+
+	dctx := session.ContextWithOutbound(context.Background(), &session.Outbound{
+		Target: net.UDPDestination(net.LocalHostIP, 53),
+	})
+	conn, err := quic.Dial(dctx, net.TCPDestination(net.LocalHostIP, port), &internet.MemoryStreamConfig{
 		ProtocolName: "quic",
 		ProtocolSettings: &quic.Config{
-			Header: serial.ToTypedMessage(&wireguard.WireguardConfig{}),
-			Key:    "abcd",
-			Security: &protocol.SecurityConfig{
-				Type: protocol.SecurityType_AES128_GCM,
-			},
+			Datagram: true,
 		},
-	}, func(conn stat.Connection) {
-		go func() {
-			defer conn.Close()
+	})
+	common.Must(err)
+	defer conn.Close()
 
-			b := buf.New()
-			defer b.Release()
+	// Each write is read back as a whole, whether it is sent in a datagram or over the stream.
+	b2 := buf.New()
+	defer b2.Release()
+	for _, size := range []int{100, 1000, 100, 4000, 1} {
+		b1 := make([]byte, size)
+		common.Must2(rand.Read(b1))
+		common.Must2(conn.Write(b1))
+
+		b2.Clear()
+		common.Must(conn.SetReadDeadline(time.Now().Add(time.Second * 5)))
+		common.Must2(b2.ReadFrom(conn))
+		if r := cmp.Diff(b2.Bytes(), b1); r != "" {
+			t.Error(r)
+		}
+	}
+}
 
-			for {
-				b.Clear()
-				if _, err := b.ReadFrom(conn); err != nil {
-					return
-				}
-				common.Must2(conn.Write(b.Bytes()))
-			}
-		}()
-	})
+// udpRelay forwards packets between the clients and the server, and drops the packets of blocked clients.
+type udpRelay struct {
+	conn   *net.UDPConn
+	server *net.UDPAddr
+
+	access   sync.Mutex
+	upstream map[string]*net.UDPConn
+	blocked  map[string]bool
+}
+
+func newUDPRelay(server *net.UDPAddr) *udpRelay {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.LocalHostIP.IP()})
 	common.Must(err)
+	r := &udpRelay{
+		conn:     conn,
+		server:   server,
+		upstream: make(map[string]*net.UDPConn),
+		blocked:  make(map[string]bool),
+	}
+	go r.run()
+	return r
+}
+
+func (r *udpRelay) run() {
+	b := make([]byte, 2048)
+	for {
+		n, client, err := r.conn.ReadFromUDP(b)
+		if err != nil {
+			return
+		}
+		r.access.Lock()
+		if r.blocked[client.String()] {
+			r.access.Unlock()
+			continue
+		}
+		upstream, found := r.upstream[client.String()]
+		if !found {
+			upstream, err = net.DialUDP("udp", nil, r.server)
+			common.Must(err)
+			r.upstream[client.String()] = upstream
+			go r.runUpstream(client, upstream)
+		}
+		r.access.Unlock()
+		upstream.Write(b[:n])
+	}
+}
 
+func (r *udpRelay) runUpstream(client *net.UDPAddr, upstream *net.UDPConn) {
+	b := make([]byte, 2048)
+	for {
+		n, err := upstream.Read(b)
+		if err != nil {
+			return
+		}
+		r.access.Lock()
+		blocked := r.blocked[client.String()]
+		r.access.Unlock()
+		if !blocked {
+			r.conn.WriteToUDP(b[:n], client)
+		}
+	}
+}
+
+// blockAll blocks all the clients seen so far.
+func (r *udpRelay) blockAll() {
+	r.access.Lock()
+	defer r.access.Unlock()
+
+	for client := range r.upstream {
+		r.blocked[client] = true
+	}
+}
+
+func (r *udpRelay) Close() {
+	r.access.Lock()
+	defer r.access.Unlock()
+
+	r.conn.Close()
+	for _, upstream := range r.upstream {
+		upstream.Close()
+	}
+}
+
+func TestQuicMigration(t *testing.T) {
+	port := udp.PickPort()
+
+	listener, err := quic.Listen(context.Background(), net.LocalHostIP, port, &internet.MemoryStreamConfig{
+		ProtocolName:     "quic",
+		ProtocolSettings: &quic.Config{},
+	}, echo)
+	common.Must(err)
 	defer listener.Close()
 
+	relay := newUDPRelay(&net.UDPAddr{IP: net.LocalHostIP.IP(), Port: int(port)})
+	defer relay.Close()
+
 	time.Sleep(time.Second)
 
-	dctx := context.Background()
-	conn, err := quic.Dial(dctx, net.TCPDestination(net.LocalHostIP, port), &internet.MemoryStreamConfig{
+	conn, err := quic.Dial(context.Background(), net.DestinationFromAddr(relay.conn.LocalAddr()), &internet.MemoryStreamConfig{
 		ProtocolName: "quic",
 		ProtocolSettings: &quic.Config{
-			Header: serial.ToTypedMessage(&wireguard.WireguardConfig{}),
-			Key:    "abcd",
-			Security: &protocol.SecurityConfig{
-				Type: protocol.SecurityType_AES128_GCM,
-			},
+			KeepAlivePeriod: 1,
+			Migration:       true,
 		},
 	})
 	common.Must(err)
@@ -204,20 +323,19 @@ func TestQuicConnectionAuthHeader(t *testing.T) {
 	b1 := make([]byte, N)
 	common.Must2(rand.Read(b1))
 	b2 := buf.New()
+	defer b2.Release()
 
-	common.Must2(conn.Write(b1))
+	for i := 0; i < 2; i++ {
+		common.Must2(conn.Write(b1))
 
-	b2.Clear()
-	common.Must2(b2.ReadFullFrom(conn, N))
-	if r := cmp.Diff(b2.Bytes(), b1); r != "" {
-		t.Error(r)
-	}
-
-	common.Must2(conn.Write(b1))
+		b2.Clear()
+		common.Must(conn.SetReadDeadline(time.Now().Add(time.Second * 10)))
+		common.Must2(b2.ReadFullFrom(conn, N))
+		if r := cmp.Diff(b2.Bytes(), b1); r != "" {
+			t.Error(r)
+		}
 
-	b2.Clear()
-	common.Must2(b2.ReadFullFrom(conn, N))
-	if r := cmp.Diff(b2.Bytes(), b1); r != "" {
-		t.Error(r)
+		// The connection only survives this by moving to a new socket.
+		relay.blockAll()
 	}
 }
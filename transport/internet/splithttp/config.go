@@ -0,0 +1,65 @@
+package splithttp
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/transport/internet"
+)
+
+const (
+	defaultMaxUploadSize        = 1000000
+	defaultMaxConcurrentUploads = 10
+)
+
+// GetNormalizedPath returns the path with leading and trailing slashes, so session IDs can be appended to it.
+func (c *Config) GetNormalizedPath() string {
+	path := c.Path
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	if !strings.HasSuffix(path, "/") {
+		path += "/"
+	}
+	return path
+}
+
+func (c *Config) GetRequestHeader() http.Header {
+	header := http.Header{}
+	for k, v := range c.Header {
+		header.Add(k, v)
+	}
+	return header
+}
+
+func (c *Config) GetNormalizedMaxUploadSize() int32 {
+	if c.MaxUploadSize <= 0 {
+		return defaultMaxUploadSize
+	}
+	return c.MaxUploadSize
+}
+
+func (c *Config) GetNormalizedMaxConcurrentUploads() int32 {
+	if c.MaxConcurrentUploads <= 0 {
+		return defaultMaxConcurrentUploads
+	}
+	return c.MaxConcurrentUploads
+}
+
+func (c *Config) isValidHost(host string) bool {
+	if len(c.Host) == 0 {
+		return true
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.EqualFold(host, c.Host)
+}
+
+func init() {
+	common.Must(internet.RegisterProtocolConfigCreator(protocolName, func() interface{} {
+		return new(Config)
+	}))
+}
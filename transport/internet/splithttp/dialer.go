@@ -0,0 +1,312 @@
+package splithttp
+
+import (
+	"bytes"
+	"context"
+	gotls "crypto/tls"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/quic-go/quic-go"
+	"github.com/quic-go/quic-go/http3"
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/buf"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/net/cnc"
+	"github.com/xtls/xray-core/common/session"
+	"github.com/xtls/xray-core/common/signal/semaphore"
+	"github.com/xtls/xray-core/common/uuid"
+	"github.com/xtls/xray-core/transport/internet"
+	"github.com/xtls/xray-core/transport/internet/reality"
+	"github.com/xtls/xray-core/transport/internet/stat"
+	"github.com/xtls/xray-core/transport/internet/tls"
+	"github.com/xtls/xray-core/transport/pipe"
+	"golang.org/x/net/http2"
+)
+
+type dialerConf struct {
+	net.Destination
+	*internet.MemoryStreamConfig
+}
+
+var (
+	globalDialerMap    map[dialerConf]*http.Client
+	globalDialerAccess sync.Mutex
+)
+
+// getHTTPClient returns the HTTP client shared by all connections to the destination. It speaks HTTP/3 if the TLS
+// settings only allow h3, HTTP/2 over REALITY or if the TLS settings only allow h2, and HTTP/1.1 otherwise.
+func getHTTPClient(ctx context.Context, dest net.Destination, streamSettings *internet.MemoryStreamConfig) *http.Client {
+	globalDialerAccess.Lock()
+	defer globalDialerAccess.Unlock()
+
+	if globalDialerMap == nil {
+		globalDialerMap = make(map[dialerConf]*http.Client)
+	}
+	if client, found := globalDialerMap[dialerConf{dest, streamSettings}]; found {
+		return client
+	}
+
+	tlsConfig := tls.ConfigFromStreamSettings(streamSettings)
+	realityConfig := reality.ConfigFromStreamSettings(streamSettings)
+	sockopt := streamSettings.SocketSettings
+
+	var transport http.RoundTripper
+	switch {
+	case isH3(tlsConfig):
+		transport = &http3.RoundTripper{
+			TLSClientConfig: tlsConfig.GetTLSConfig(tls.WithDestination(dest)),
+			Dial: func(hctx context.Context, addr string, tlsCfg *gotls.Config, cfg *quic.Config) (quic.EarlyConnection, error) {
+				rawConn, err := internet.DialSystem(hctx, net.UDPDestination(dest.Address, dest.Port), sockopt)
+				if err != nil {
+					return nil, err
+				}
+				packetConn, ok := rawConn.(*internet.PacketConnWrapper)
+				if !ok {
+					rawConn.Close()
+					return nil, newError("splithttp over HTTP/3 with sockopt is unsupported").AtWarning()
+				}
+				conn, err := quic.DialEarly(hctx, packetConn.Conn, packetConn.Dest, tlsCfg, cfg)
+				if err != nil {
+					rawConn.Close()
+					return nil, err
+				}
+				go func() {
+					<-conn.Context().Done()
+					rawConn.Close()
+				}()
+				return conn, nil
+			},
+		}
+	case realityConfig != nil || (tlsConfig != nil && len(tlsConfig.NextProtocol) == 1 && tlsConfig.NextProtocol[0] == "h2"):
+		transport = &http2.Transport{
+			DialTLSContext: func(hctx context.Context, network string, addr string, cfg *gotls.Config) (net.Conn, error) {
+				return dialTLS(hctx, ctx, dest, streamSettings, "h2")
+			},
+			IdleConnTimeout: 90 * time.Second,
+		}
+	default:
+		dialContext := func(hctx context.Context, network string, addr string) (net.Conn, error) {
+			return dialTLS(hctx, ctx, dest, streamSettings, "http/1.1")
+		}
+		transport = &http.Transport{
+			DialContext:     dialContext,
+			DialTLSContext:  dialContext,
+			IdleConnTimeout: 90 * time.Second,
+			// Each connection holds one download request, and uploads need connections of their own.
+			MaxIdleConnsPerHost: 100,
+		}
+	}
+
+	client := &http.Client{
+		Transport: transport,
+	}
+	globalDialerMap[dialerConf{dest, streamSettings}] = client
+	return client
+}
+
+// dialTLS dials a TCP connection to the destination, secured by TLS or REALITY if configured.
+func dialTLS(hctx context.Context, ctx context.Context, dest net.Destination, streamSettings *internet.MemoryStreamConfig, nextProto string) (net.Conn, error) {
+	hctx = session.ContextWithID(hctx, session.IDFromContext(ctx))
+	hctx = session.ContextWithOutbound(hctx, session.OutboundFromContext(ctx))
+	hctx = session.ContextWithTimeoutOnly(hctx, true)
+
+	conn, err := internet.DialSystem(hctx, dest, streamSettings.SocketSettings)
+	if err != nil {
+		return nil, err
+	}
+
+	if realityConfig := reality.ConfigFromStreamSettings(streamSettings); realityConfig != nil {
+		return reality.UClient(conn, realityConfig, hctx, dest)
+	}
+
+	if tlsConfig := tls.ConfigFromStreamSettings(streamSettings); tlsConfig != nil {
+		config := tlsConfig.GetTLSConfig(tls.WithDestination(dest), tls.WithNextProto(nextProto))
+		var cn tls.Interface
+		var err error
+		if fingerprint := tls.GetFingerprint(tlsConfig.Fingerprint); fingerprint != nil {
+			uConn := tls.UClient(conn, config, fingerprint).(*tls.UConn)
+			cn = uConn
+			if nextProto == "http/1.1" {
+				// Fingerprints advertise h2, which the HTTP/1.1 client cannot speak.
+				err = uConn.WebsocketHandshakeContext(hctx)
+			} else {
+				err = uConn.HandshakeContext(hctx)
+			}
+		} else {
+			cn = tls.Client(conn, config).(*tls.Conn)
+			err = cn.HandshakeContext(hctx)
+		}
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+		if !config.InsecureSkipVerify {
+			if err := cn.VerifyHostname(config.ServerName); err != nil {
+				conn.Close()
+				return nil, err
+			}
+		}
+		return cn, nil
+	}
+
+	return conn, nil
+}
+
+// Dial dials a new connection to the given destination. The downlink is the body of a GET request, while the uplink
+// is split into POST requests numbered in order.
+func Dial(ctx context.Context, dest net.Destination, streamSettings *internet.MemoryStreamConfig) (stat.Connection, error) {
+	newError("dialing splithttp to ", dest).WriteToLog(session.ExportIDToError(ctx))
+
+	transportConfiguration := streamSettings.ProtocolSettings.(*Config)
+	client := getHTTPClient(ctx, dest, streamSettings)
+
+	scheme := "http"
+	if tls.ConfigFromStreamSettings(streamSettings) != nil || reality.ConfigFromStreamSettings(streamSettings) != nil {
+		scheme = "https"
+	}
+	sessionID := uuid.New()
+	requestURL := url.URL{
+		Scheme: scheme,
+		Host:   dest.NetAddr(),
+		Path:   transportConfiguration.GetNormalizedPath() + sessionID.String(),
+	}
+	newRequest := func(ctx context.Context, method string, url string, body io.Reader) (*http.Request, error) {
+		request, err := http.NewRequestWithContext(ctx, method, url, body)
+		if err != nil {
+			return nil, err
+		}
+		request.Header = transportConfiguration.GetRequestHeader()
+		request.Host = transportConfiguration.Host
+		return request, nil
+	}
+
+	downloadCtx, cancelDownload := context.WithCancel(context.Background())
+	downloadRequest, err := newRequest(downloadCtx, http.MethodGet, requestURL.String(), nil)
+	if err != nil {
+		cancelDownload()
+		return nil, newError("failed to create download request").Base(err)
+	}
+	downloadReader := &waitReadCloser{wait: make(chan struct{})}
+	go func() {
+		response, err := client.Do(downloadRequest)
+		if err != nil {
+			newError("failed to send download request to ", dest).Base(err).AtWarning().WriteToLog(session.ExportIDToError(ctx))
+			downloadReader.Close()
+			return
+		}
+		if response.StatusCode != http.StatusOK {
+			newError("unexpected status of download request: ", response.StatusCode).AtWarning().WriteToLog(session.ExportIDToError(ctx))
+			response.Body.Close()
+			downloadReader.Close()
+			return
+		}
+		downloadReader.Set(response.Body)
+	}()
+
+	maxUploadSize := transportConfiguration.GetNormalizedMaxUploadSize()
+	uploadPipeReader, uploadPipeWriter := pipe.New(pipe.WithSizeLimit(maxUploadSize))
+	go func() {
+		requestsLimiter := semaphore.New(int(transportConfiguration.GetNormalizedMaxConcurrentUploads()))
+		var seq uint64
+		for {
+			mb, err := uploadPipeReader.ReadMultiBuffer()
+			if err != nil {
+				return
+			}
+			for !mb.IsEmpty() {
+				var chunk buf.MultiBuffer
+				mb, chunk = buf.SplitSize(mb, maxUploadSize)
+				payload := make([]byte, chunk.Len())
+				chunk.Copy(payload)
+				buf.ReleaseMulti(chunk)
+
+				<-requestsLimiter.Wait()
+				go func(seq uint64) {
+					defer requestsLimiter.Signal()
+					if err := upload(client, newRequest, requestURL.String()+"/"+strconv.FormatUint(seq, 10), payload); err != nil {
+						newError("failed to send upload request to ", dest).Base(err).AtWarning().WriteToLog(session.ExportIDToError(ctx))
+						uploadPipeReader.Interrupt()
+					}
+				}(seq)
+				seq++
+			}
+		}
+	}()
+
+	return cnc.NewConnection(
+		cnc.ConnectionOutput(downloadReader),
+		cnc.ConnectionInputMulti(uploadPipeWriter),
+		cnc.ConnectionOnClose(common.ChainedClosable{uploadPipeWriter, downloadReader, closerFunc(cancelDownload)}),
+	), nil
+}
+
+func upload(client *http.Client, newRequest func(context.Context, string, string, io.Reader) (*http.Request, error), url string, payload []byte) error {
+	request, err := newRequest(context.Background(), http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+	io.Copy(io.Discard, response.Body)
+	if response.StatusCode != http.StatusOK {
+		return newError("unexpected status ", response.StatusCode)
+	}
+	return nil
+}
+
+type closerFunc func()
+
+func (f closerFunc) Close() error {
+	f()
+	return nil
+}
+
+// waitReadCloser reads from the body of the download response once it arrives.
+type waitReadCloser struct {
+	wait chan struct{}
+	once sync.Once
+	rc   io.ReadCloser
+}
+
+func (w *waitReadCloser) Set(rc io.ReadCloser) {
+	set := false
+	w.once.Do(func() {
+		w.rc = rc
+		set = true
+		close(w.wait)
+	})
+	if !set {
+		// Closed before the response arrived.
+		rc.Close()
+	}
+}
+
+func (w *waitReadCloser) Read(b []byte) (int, error) {
+	<-w.wait
+	if w.rc == nil {
+		return 0, io.ErrClosedPipe
+	}
+	return w.rc.Read(b)
+}
+
+func (w *waitReadCloser) Close() error {
+	w.once.Do(func() {
+		close(w.wait)
+	})
+	if w.rc != nil {
+		return w.rc.Close()
+	}
+	return nil
+}
+
+func init() {
+	common.Must(internet.RegisterTransportDialer(protocolName, Dial))
+}
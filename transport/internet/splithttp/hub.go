@@ -0,0 +1,336 @@
+package splithttp
+
+import (
+	"context"
+	gotls "crypto/tls"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/quic-go/quic-go"
+	"github.com/quic-go/quic-go/http3"
+	goreality "github.com/xtls/reality"
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/net/cnc"
+	http_proto "github.com/xtls/xray-core/common/protocol/http"
+	"github.com/xtls/xray-core/common/session"
+	"github.com/xtls/xray-core/common/signal/done"
+	"github.com/xtls/xray-core/transport/internet"
+	"github.com/xtls/xray-core/transport/internet/reality"
+	"github.com/xtls/xray-core/transport/internet/tls"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+// sessionTimeout is how long a session waits for its download request after the first upload request.
+const sessionTimeout = 30 * time.Second
+
+type httpSession struct {
+	uploadQueue *uploadQueue
+	// isFullyConnected is closed when the download request of the session arrives.
+	isFullyConnected *done.Instance
+}
+
+type requestHandler struct {
+	config    *Config
+	path      string
+	localAddr net.Addr
+	addConn   internet.ConnHandler
+
+	sessionAccess sync.Mutex
+	sessions      map[string]*httpSession
+}
+
+func (h *requestHandler) upsertSession(sessionID string) *httpSession {
+	h.sessionAccess.Lock()
+	defer h.sessionAccess.Unlock()
+
+	if s, found := h.sessions[sessionID]; found {
+		return s
+	}
+	s := &httpSession{
+		uploadQueue:      newUploadQueue(int(h.config.GetNormalizedMaxConcurrentUploads())),
+		isFullyConnected: done.New(),
+	}
+	h.sessions[sessionID] = s
+
+	// Drop the session if its download request never arrives.
+	go func() {
+		select {
+		case <-time.After(sessionTimeout):
+			h.deleteSession(sessionID, s)
+			s.uploadQueue.Close()
+		case <-s.isFullyConnected.Wait():
+		}
+	}()
+	return s
+}
+
+func (h *requestHandler) deleteSession(sessionID string, s *httpSession) {
+	h.sessionAccess.Lock()
+	defer h.sessionAccess.Unlock()
+
+	if h.sessions[sessionID] == s {
+		delete(h.sessions, sessionID)
+	}
+}
+
+func (h *requestHandler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	if !h.config.isValidHost(request.Host) {
+		writer.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if !strings.HasPrefix(request.URL.Path, h.path) {
+		writer.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	sessionID, seqStr, hasSeq := strings.Cut(strings.TrimPrefix(request.URL.Path, h.path), "/")
+	if len(sessionID) == 0 {
+		writer.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	for k, v := range h.config.Header {
+		writer.Header().Set(k, v)
+	}
+
+	switch {
+	case request.Method == http.MethodPost && hasSeq:
+		h.handleUpload(writer, request, sessionID, seqStr)
+	case request.Method == http.MethodGet && !hasSeq:
+		h.handleDownload(writer, request, sessionID)
+	default:
+		writer.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *requestHandler) handleUpload(writer http.ResponseWriter, request *http.Request, sessionID string, seqStr string) {
+	seq, err := strconv.ParseUint(seqStr, 10, 64)
+	if err != nil {
+		writer.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	maxUploadSize := int64(h.config.GetNormalizedMaxUploadSize())
+	payload, err := io.ReadAll(io.LimitReader(request.Body, maxUploadSize+1))
+	if err != nil {
+		newError("failed to read upload request of session ", sessionID).Base(err).WriteToLog()
+		writer.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if int64(len(payload)) > maxUploadSize {
+		writer.WriteHeader(http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	s := h.upsertSession(sessionID)
+	if err := s.uploadQueue.Push(Packet{Seq: seq, Payload: payload}); err != nil {
+		newError("failed to upload to session ", sessionID).Base(err).WriteToLog()
+		writer.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	writer.WriteHeader(http.StatusOK)
+}
+
+func (h *requestHandler) handleDownload(writer http.ResponseWriter, request *http.Request, sessionID string) {
+	s := h.upsertSession(sessionID)
+	if s.isFullyConnected.Done() {
+		// Only one download request is allowed for each session.
+		writer.WriteHeader(http.StatusConflict)
+		return
+	}
+	s.isFullyConnected.Close()
+	defer h.deleteSession(sessionID, s)
+
+	// Ask proxies and CDNs not to buffer or cache the response.
+	writer.Header().Set("Cache-Control", "no-store")
+	writer.Header().Set("X-Accel-Buffering", "no")
+	writer.Header().Set("Content-Type", "text/event-stream")
+	writer.WriteHeader(http.StatusOK)
+	if f, ok := writer.(http.Flusher); ok {
+		f.Flush()
+	}
+
+	remoteAddr := h.localAddr
+	if dest, err := net.ParseDestination("tcp:" + request.RemoteAddr); err == nil && dest.Address.Family().IsIP() {
+		remoteAddr = &net.TCPAddr{
+			IP:   dest.Address.IP(),
+			Port: int(dest.Port),
+		}
+	}
+	forwardedAddress := http_proto.ParseXForwardedFor(request.Header)
+	if len(forwardedAddress) > 0 && forwardedAddress[0].Family().IsIP() {
+		remoteAddr = &net.TCPAddr{
+			IP:   forwardedAddress[0].IP(),
+			Port: 0,
+		}
+	}
+
+	done := done.New()
+	conn := cnc.NewConnection(
+		cnc.ConnectionOutput(s.uploadQueue),
+		cnc.ConnectionInput(flushWriter{w: writer, d: done}),
+		cnc.ConnectionOnClose(common.ChainedClosable{done, s.uploadQueue}),
+		cnc.ConnectionLocalAddr(h.localAddr),
+		cnc.ConnectionRemoteAddr(remoteAddr),
+	)
+	h.addConn(conn)
+
+	select {
+	case <-done.Wait():
+	case <-request.Context().Done():
+		conn.Close()
+	}
+}
+
+type flushWriter struct {
+	w io.Writer
+	d *done.Instance
+}
+
+func (fw flushWriter) Write(p []byte) (n int, err error) {
+	if fw.d.Done() {
+		return 0, io.ErrClosedPipe
+	}
+
+	defer func() {
+		if recover() != nil {
+			fw.d.Close()
+			err = io.ErrClosedPipe
+		}
+	}()
+
+	n, err = fw.w.Write(p)
+	if f, ok := fw.w.(http.Flusher); ok && err == nil {
+		f.Flush()
+	}
+	return
+}
+
+type Listener struct {
+	server     *http.Server
+	h3server   *http3.Server
+	listener   net.Listener
+	h3listener *quic.EarlyListener
+}
+
+func (l *Listener) Addr() net.Addr {
+	if l.h3listener != nil {
+		return l.h3listener.Addr()
+	}
+	return l.listener.Addr()
+}
+
+func (l *Listener) Close() error {
+	if l.h3server != nil {
+		l.h3server.Close()
+		return l.h3listener.Close()
+	}
+	return l.server.Close()
+}
+
+// isH3 returns true if the TLS settings only allow HTTP/3.
+func isH3(config *tls.Config) bool {
+	return config != nil && len(config.NextProtocol) == 1 && config.NextProtocol[0] == "h3"
+}
+
+func ListenSH(ctx context.Context, address net.Address, port net.Port, streamSettings *internet.MemoryStreamConfig, addConn internet.ConnHandler) (internet.Listener, error) {
+	transportConfiguration := streamSettings.ProtocolSettings.(*Config)
+	handler := &requestHandler{
+		config:   transportConfiguration,
+		path:     transportConfiguration.GetNormalizedPath(),
+		addConn:  addConn,
+		sessions: make(map[string]*httpSession),
+	}
+	listener := new(Listener)
+
+	tlsConfig := tls.ConfigFromStreamSettings(streamSettings)
+	if isH3(tlsConfig) {
+		if port == net.Port(0) {
+			return nil, newError("splithttp over HTTP/3 cannot listen on unix domain socket")
+		}
+		rawConn, err := internet.ListenSystemPacket(ctx, &net.UDPAddr{
+			IP:   address.IP(),
+			Port: int(port),
+		}, streamSettings.SocketSettings)
+		if err != nil {
+			return nil, newError("failed to listen UDP(for SplitHTTP/3) on ", address, ":", port).Base(err)
+		}
+		h3TLSConfig := tlsConfig.GetTLSConfig()
+		// quic-go fails the handshake if session tickets are disabled.
+		h3TLSConfig.SessionTicketsDisabled = false
+		h3listener, err := quic.ListenEarly(rawConn, h3TLSConfig, nil)
+		if err != nil {
+			rawConn.Close()
+			return nil, newError("failed to listen QUIC(for SplitHTTP/3) on ", address, ":", port).Base(err)
+		}
+		newError("listening QUIC(for SplitHTTP/3) on ", address, ":", port).WriteToLog(session.ExportIDToError(ctx))
+
+		handler.localAddr = h3listener.Addr()
+		listener.h3listener = h3listener
+		listener.h3server = &http3.Server{
+			Handler: handler,
+		}
+		go func() {
+			if err := listener.h3server.ServeListener(h3listener); err != nil {
+				newError("stopping serving SplitHTTP/3").Base(err).WriteToLog(session.ExportIDToError(ctx))
+			}
+		}()
+		return listener, nil
+	}
+
+	var err error
+	if port == net.Port(0) { // unix
+		listener.listener, err = internet.ListenSystem(ctx, &net.UnixAddr{
+			Name: address.Domain(),
+			Net:  "unix",
+		}, streamSettings.SocketSettings)
+		if err != nil {
+			return nil, newError("failed to listen unix domain socket(for SplitHTTP) on ", address).Base(err)
+		}
+		newError("listening unix domain socket(for SplitHTTP) on ", address).WriteToLog(session.ExportIDToError(ctx))
+	} else { // tcp
+		listener.listener, err = internet.ListenSystem(ctx, &net.TCPAddr{
+			IP:   address.IP(),
+			Port: int(port),
+		}, streamSettings.SocketSettings)
+		if err != nil {
+			return nil, newError("failed to listen TCP(for SplitHTTP) on ", address, ":", port).Base(err)
+		}
+		newError("listening TCP(for SplitHTTP) on ", address, ":", port).WriteToLog(session.ExportIDToError(ctx))
+	}
+
+	if streamSettings.SocketSettings != nil && streamSettings.SocketSettings.AcceptProxyProtocol {
+		newError("accepting PROXY protocol").AtWarning().WriteToLog(session.ExportIDToError(ctx))
+	}
+
+	handler.localAddr = listener.listener.Addr()
+	if tlsConfig != nil {
+		listener.listener = gotls.NewListener(listener.listener, tlsConfig.GetTLSConfig(tls.WithNextProto("h2", "http/1.1")))
+	} else if realityConfig := reality.ConfigFromStreamSettings(streamSettings); realityConfig != nil {
+		listener.listener = goreality.NewListener(listener.listener, realityConfig.GetREALITYConfig())
+	}
+
+	// HTTP/2 over REALITY is served as h2c, as REALITY connections are not recognized by the HTTP server.
+	listener.server = &http.Server{
+		Handler:           h2c.NewHandler(handler, &http2.Server{}),
+		ReadHeaderTimeout: time.Second * 4,
+		MaxHeaderBytes:    8192,
+	}
+	go func() {
+		if err := listener.server.Serve(listener.listener); err != nil && err != http.ErrServerClosed {
+			newError("stopping serving SplitHTTP").Base(err).WriteToLog(session.ExportIDToError(ctx))
+		}
+	}()
+
+	return listener, nil
+}
+
+func init() {
+	common.Must(internet.RegisterTransportListener(protocolName, ListenSH))
+}
@@ -0,0 +1,17 @@
+package splithttp
+
+import (
+	"context"
+
+	"github.com/xtls/xray-core/common"
+)
+
+//go:generate go run github.com/xtls/xray-core/common/errors/errorgen
+
+const protocolName = "splithttp"
+
+func init() {
+	common.Must(common.RegisterConfig((*Config)(nil), func(ctx context.Context, config interface{}) (interface{}, error) {
+		return nil, newError("splithttp is a transport protocol.")
+	}))
+}
@@ -0,0 +1,165 @@
+package splithttp_test
+
+import (
+	"context"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/xtls/xray-core/common"
+	"github.com/xtls/xray-core/common/buf"
+	"github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/protocol/tls/cert"
+	"github.com/xtls/xray-core/testing/servers/tcp"
+	"github.com/xtls/xray-core/testing/servers/udp"
+	"github.com/xtls/xray-core/transport/internet"
+	. "github.com/xtls/xray-core/transport/internet/splithttp"
+	"github.com/xtls/xray-core/transport/internet/stat"
+	"github.com/xtls/xray-core/transport/internet/tls"
+)
+
+func echo(conn stat.Connection) {
+	go func() {
+		defer conn.Close()
+
+		b := buf.New()
+		defer b.Release()
+
+		for {
+			b.Clear()
+			if _, err := b.ReadFrom(conn); err != nil {
+				return
+			}
+			if _, err := conn.Write(b.Bytes()); err != nil {
+				return
+			}
+		}
+	}()
+}
+
+func testEcho(t *testing.T, port net.Port, serverSettings, clientSettings *internet.MemoryStreamConfig) {
+	listener, err := ListenSH(context.Background(), net.LocalHostIP, port, serverSettings, echo)
+	common.Must(err)
+	defer listener.Close()
+
+	time.Sleep(100 * time.Millisecond)
+
+	conn, err := Dial(context.Background(), net.TCPDestination(net.LocalHostIP, port), clientSettings)
+	common.Must(err)
+	defer conn.Close()
+
+	// Larger than the upload size, so the uplink is split into several requests.
+	const N = 64 * 1024
+	b1 := make([]byte, N)
+	common.Must2(rand.Read(b1))
+	b2 := buf.New()
+	defer b2.Release()
+
+	for i := 0; i < 2; i++ {
+		nBytes, err := conn.Write(b1)
+		common.Must(err)
+		if nBytes != N {
+			t.Error("write: ", nBytes)
+		}
+
+		received := make([]byte, 0, N)
+		for len(received) < N {
+			b2.Clear()
+			common.Must2(b2.ReadFrom(conn))
+			received = append(received, b2.Bytes()...)
+		}
+		if r := cmp.Diff(received, b1); r != "" {
+			t.Error(r)
+		}
+	}
+}
+
+func TestHTTPConnection(t *testing.T) {
+	config := &Config{
+		Path:          "/sh",
+		MaxUploadSize: 1000,
+	}
+	testEcho(t, tcp.PickPort(), &internet.MemoryStreamConfig{
+		ProtocolName:     "splithttp",
+		ProtocolSettings: config,
+	}, &internet.MemoryStreamConfig{
+		ProtocolName:     "splithttp",
+		ProtocolSettings: config,
+	})
+}
+
+func TestH2Connection(t *testing.T) {
+	config := &Config{
+		Host:          "www.example.com",
+		Path:          "sh",
+		MaxUploadSize: 4096,
+	}
+	testEcho(t, tcp.PickPort(), &internet.MemoryStreamConfig{
+		ProtocolName:     "splithttp",
+		ProtocolSettings: config,
+		SecurityType:     "tls",
+		SecuritySettings: &tls.Config{
+			Certificate: []*tls.Certificate{tls.ParseCertificate(cert.MustGenerate(nil, cert.CommonName("www.example.com")))},
+		},
+	}, &internet.MemoryStreamConfig{
+		ProtocolName:     "splithttp",
+		ProtocolSettings: config,
+		SecurityType:     "tls",
+		SecuritySettings: &tls.Config{
+			ServerName:    "www.example.com",
+			AllowInsecure: true,
+			NextProtocol:  []string{"h2"},
+		},
+	})
+}
+
+func TestHTTPSConnectionWithFingerprint(t *testing.T) {
+	config := &Config{
+		Host:          "www.example.com",
+		Path:          "/sh",
+		MaxUploadSize: 4096,
+	}
+	testEcho(t, tcp.PickPort(), &internet.MemoryStreamConfig{
+		ProtocolName:     "splithttp",
+		ProtocolSettings: config,
+		SecurityType:     "tls",
+		SecuritySettings: &tls.Config{
+			Certificate: []*tls.Certificate{tls.ParseCertificate(cert.MustGenerate(nil, cert.CommonName("www.example.com")))},
+		},
+	}, &internet.MemoryStreamConfig{
+		ProtocolName:     "splithttp",
+		ProtocolSettings: config,
+		SecurityType:     "tls",
+		SecuritySettings: &tls.Config{
+			ServerName:    "www.example.com",
+			AllowInsecure: true,
+			Fingerprint:   "chrome",
+		},
+	})
+}
+
+func TestH3Connection(t *testing.T) {
+	config := &Config{
+		Path:          "/sh/",
+		MaxUploadSize: 4096,
+	}
+	testEcho(t, udp.PickPort(), &internet.MemoryStreamConfig{
+		ProtocolName:     "splithttp",
+		ProtocolSettings: config,
+		SecurityType:     "tls",
+		SecuritySettings: &tls.Config{
+			Certificate:  []*tls.Certificate{tls.ParseCertificate(cert.MustGenerate(nil, cert.CommonName("www.example.com")))},
+			NextProtocol: []string{"h3"},
+		},
+	}, &internet.MemoryStreamConfig{
+		ProtocolName:     "splithttp",
+		ProtocolSettings: config,
+		SecurityType:     "tls",
+		SecuritySettings: &tls.Config{
+			ServerName:    "www.example.com",
+			AllowInsecure: true,
+			NextProtocol:  []string{"h3"},
+		},
+	})
+}
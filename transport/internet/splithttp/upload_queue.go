@@ -0,0 +1,106 @@
+package splithttp
+
+import (
+	"container/heap"
+	"io"
+	"sync"
+)
+
+// Packet is the payload of an upload request, numbered by its position in the uplink.
+type Packet struct {
+	Seq     uint64
+	Payload []byte
+}
+
+type packetHeap []Packet
+
+func (h packetHeap) Len() int           { return len(h) }
+func (h packetHeap) Less(i, j int) bool { return h[i].Seq < h[j].Seq }
+func (h packetHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *packetHeap) Push(x interface{}) {
+	*h = append(*h, x.(Packet))
+}
+
+func (h *packetHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
+
+// uploadQueue reassembles the uplink from upload requests that may arrive out of order.
+type uploadQueue struct {
+	access     sync.Mutex
+	cond       *sync.Cond
+	packets    packetHeap
+	current    []byte
+	nextSeq    uint64
+	closed     bool
+	maxPackets int
+}
+
+func newUploadQueue(maxPackets int) *uploadQueue {
+	q := &uploadQueue{
+		maxPackets: maxPackets,
+	}
+	q.cond = sync.NewCond(&q.access)
+	return q
+}
+
+// Push adds a packet to the queue. It blocks while the queue is full, unless the packet is the next one to be read.
+func (q *uploadQueue) Push(p Packet) error {
+	q.access.Lock()
+	defer q.access.Unlock()
+
+	for !q.closed && p.Seq != q.nextSeq && len(q.packets) >= q.maxPackets {
+		q.cond.Wait()
+	}
+	if q.closed {
+		return io.ErrClosedPipe
+	}
+	if p.Seq < q.nextSeq {
+		// Duplicate of a packet already read.
+		return nil
+	}
+	heap.Push(&q.packets, p)
+	q.cond.Broadcast()
+	return nil
+}
+
+// Read implements io.Reader.
+func (q *uploadQueue) Read(b []byte) (int, error) {
+	q.access.Lock()
+	defer q.access.Unlock()
+
+	for len(q.current) == 0 {
+		if len(q.packets) > 0 && q.packets[0].Seq <= q.nextSeq {
+			p := heap.Pop(&q.packets).(Packet)
+			if p.Seq == q.nextSeq {
+				q.current = p.Payload
+				q.nextSeq++
+				q.cond.Broadcast()
+			}
+			continue
+		}
+		if q.closed {
+			return 0, io.EOF
+		}
+		q.cond.Wait()
+	}
+
+	n := copy(b, q.current)
+	q.current = q.current[n:]
+	return n, nil
+}
+
+// Close implements io.Closer.
+func (q *uploadQueue) Close() error {
+	q.access.Lock()
+	defer q.access.Unlock()
+
+	q.closed = true
+	q.cond.Broadcast()
+	return nil
+}
@@ -41,6 +41,17 @@ func (c *Config) loadSelfCertPool() (*x509.CertPool, error) {
 	return root, nil
 }
 
+// loadClientCAs returns the pool of the certificates of AUTHORITY_VERIFY usage, which verify client certificates.
+func (c *Config) loadClientCAs() *x509.CertPool {
+	pool := x509.NewCertPool()
+	for _, cert := range c.Certificate {
+		if cert.Usage == Certificate_AUTHORITY_VERIFY && !pool.AppendCertsFromPEM(cert.Certificate) {
+			newError("failed to append client CA").AtWarning().WriteToLog()
+		}
+	}
+	return pool
+}
+
 // BuildCertificates builds a list of TLS certificates from proto definition.
 func (c *Config) BuildCertificates() []*tls.Certificate {
 	certs := make([]*tls.Certificate, 0, len(c.Certificate))
@@ -253,6 +264,18 @@ func getNewGetCertificateFunc(certs []*tls.Certificate, rejectUnknownSNI bool) f
 	}
 }
 
+func getClientCertificateFunc(certs []*tls.Certificate) func(info *tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	return func(info *tls.CertificateRequestInfo) (*tls.Certificate, error) {
+		for _, keyPair := range certs {
+			if info.SupportsCertificate(keyPair) == nil {
+				return keyPair, nil
+			}
+		}
+		// Send no certificate, and let the server decide whether to continue.
+		return new(tls.Certificate), nil
+	}
+}
+
 func (c *Config) parseServerName() string {
 	return c.ServerName
 }
@@ -318,7 +341,21 @@ func (c *Config) GetTLSConfig(opts ...Option) *tls.Config {
 	if len(caCerts) > 0 {
 		config.GetCertificate = getGetCertificateFunc(config, caCerts)
 	} else {
-		config.GetCertificate = getNewGetCertificateFunc(c.BuildCertificates(), c.RejectUnknownSni)
+		certs := c.BuildCertificates()
+		config.GetCertificate = getNewGetCertificateFunc(certs, c.RejectUnknownSni)
+		config.GetClientCertificate = getClientCertificateFunc(certs)
+	}
+
+	switch c.ClientAuth {
+	case ClientAuth_REQUEST:
+		config.ClientAuth = tls.VerifyClientCertIfGiven
+	case ClientAuth_REQUIRE:
+		config.ClientAuth = tls.RequireAnyClientCert
+	case ClientAuth_VERIFY:
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	if config.ClientAuth >= tls.VerifyClientCertIfGiven {
+		config.ClientCAs = c.loadClientCAs()
 	}
 
 	if sn := c.parseServerName(); len(sn) > 0 {
@@ -363,6 +400,8 @@ func (c *Config) GetTLSConfig(opts ...Option) *tls.Config {
 		}
 	}
 
+	c.applyECH(config)
+
 	if len(c.MasterKeyLog) > 0 && c.MasterKeyLog != "none" {
 		writer, err := os.OpenFile(c.MasterKeyLog, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0644)
 		if err != nil {
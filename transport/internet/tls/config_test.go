@@ -3,6 +3,8 @@ package tls_test
 import (
 	gotls "crypto/tls"
 	"crypto/x509"
+	"io"
+	"net"
 	"testing"
 	"time"
 
@@ -72,6 +74,59 @@ func TestInsecureCertificates(t *testing.T) {
 	}
 }
 
+func TestClientAuth(t *testing.T) {
+	caCert := cert.MustGenerate(nil, cert.Authority(true), cert.KeyUsage(x509.KeyUsageCertSign), cert.ExtKeyUsage(x509.ExtKeyUsageClientAuth))
+	clientCA := ParseCertificate(caCert)
+	clientCA.Usage = Certificate_AUTHORITY_VERIFY
+	clientCert := ParseCertificate(cert.MustGenerate(caCert, cert.CommonName("alice"), cert.ExtKeyUsage(x509.ExtKeyUsageClientAuth)))
+
+	handshake := func(clientAuth ClientAuth, clientCerts []*Certificate) (string, error) {
+		serverConn, clientConn := net.Pipe()
+		defer serverConn.Close()
+		defer clientConn.Close()
+
+		client := Client(clientConn, (&Config{AllowInsecure: true, Certificate: clientCerts}).GetTLSConfig())
+		go io.Copy(io.Discard, client)
+
+		server := Server(serverConn, (&Config{
+			Certificate: []*Certificate{ParseCertificate(cert.MustGenerate(nil)), clientCA},
+			ClientAuth:  clientAuth,
+		}).GetTLSConfig()).(*Conn)
+		err := server.Handshake()
+		return server.VerifiedPeerSubject(), err
+	}
+
+	subject, err := handshake(ClientAuth_VERIFY, []*Certificate{clientCert})
+	common.Must(err)
+	if subject != "CN=alice" {
+		t.Error("subject: ", subject)
+	}
+
+	if _, err := handshake(ClientAuth_VERIFY, nil); err == nil {
+		t.Error("client without certificate is accepted")
+	}
+
+	subject, err = handshake(ClientAuth_REQUEST, nil)
+	common.Must(err)
+	if subject != "" {
+		t.Error("subject: ", subject)
+	}
+
+	unknownCert := ParseCertificate(cert.MustGenerate(nil, cert.CommonName("alice"), cert.ExtKeyUsage(x509.ExtKeyUsageClientAuth)))
+	// The client sends no certificate, as it is not issued by the authorities the server accepts.
+	subject, err = handshake(ClientAuth_REQUEST, []*Certificate{unknownCert})
+	common.Must(err)
+	if subject != "" {
+		t.Error("subject of unknown authority: ", subject)
+	}
+
+	subject, err = handshake(ClientAuth_REQUIRE, []*Certificate{unknownCert})
+	common.Must(err)
+	if subject != "" {
+		t.Error("subject of unverified certificate: ", subject)
+	}
+}
+
 func BenchmarkCertificateIssuing(b *testing.B) {
 	certificate := ParseCertificate(cert.MustGenerate(nil, cert.Authority(true), cert.KeyUsage(x509.KeyUsageCertSign)))
 	certificate.Usage = Certificate_AUTHORITY_ISSUE
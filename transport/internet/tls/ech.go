@@ -0,0 +1,316 @@
+package tls
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"sync"
+	"time"
+
+	"github.com/OmarTariq612/goech"
+	"github.com/xtls/xray-core/transport/internet"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+const (
+	// typeHTTPS is the type of HTTPS records, which dnsmessage does not define.
+	typeHTTPS dnsmessage.Type = 65
+	// svcParamECH is the key of the SvcParam holding the ECHConfigList in HTTPS records.
+	svcParamECH = 5
+
+	echQueryTimeout = time.Second * 5
+	// echRecordMinTTL is the minimum time an ECHConfigList from an HTTPS record is used before the record is queried
+	// again, and echQueryFailureTTL the time before a failed query is retried.
+	echRecordMinTTL    = time.Minute
+	echQueryFailureTTL = time.Minute
+	// echRetryTTL is how long the retry configs sent by a server that rejects ECH are used.
+	echRetryTTL = time.Hour
+)
+
+// emptyECHConfigList is a valid ECHConfigList without any config, which makes the handshake fail before the
+// ClientHello is sent.
+var emptyECHConfigList = []byte{0, 0}
+
+type echCacheEntry struct {
+	configList []byte
+	expire     time.Time
+}
+
+// echCache holds ECHConfigLists until they expire. An empty ECHConfigList means that ECH is not available.
+type echCache struct {
+	access  sync.Mutex
+	entries map[string]echCacheEntry
+}
+
+func newECHCache() *echCache {
+	return &echCache{
+		entries: make(map[string]echCacheEntry),
+	}
+}
+
+func (c *echCache) get(key string) ([]byte, bool) {
+	c.access.Lock()
+	defer c.access.Unlock()
+
+	entry, found := c.entries[key]
+	if !found {
+		return nil, false
+	}
+	if time.Now().After(entry.expire) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return entry.configList, true
+}
+
+// getStale returns the ECHConfigList of the key even if it has expired, and whether it has.
+func (c *echCache) getStale(key string) (configList []byte, expired bool, found bool) {
+	c.access.Lock()
+	defer c.access.Unlock()
+
+	entry, found := c.entries[key]
+	return entry.configList, found && time.Now().After(entry.expire), found
+}
+
+func (c *echCache) set(key string, configList []byte, ttl time.Duration) {
+	c.access.Lock()
+	defer c.access.Unlock()
+
+	c.entries[key] = echCacheEntry{
+		configList: configList,
+		expire:     time.Now().Add(ttl),
+	}
+}
+
+// echQuerier queries HTTPS records in the background, so that dialing never waits for DNS.
+type echQuerier struct {
+	access  sync.Mutex
+	pending map[string]chan struct{}
+}
+
+// query starts querying the HTTPS record of the domain unless it is being queried, and returns a channel closed once
+// the query is done and its result is in echRecords.
+func (q *echQuerier) query(domain string) <-chan struct{} {
+	q.access.Lock()
+	defer q.access.Unlock()
+
+	if done, found := q.pending[domain]; found {
+		return done
+	}
+	done := make(chan struct{})
+	q.pending[domain] = done
+	go func() {
+		configList, ttl, err := queryECHConfigList(domain)
+		if err != nil {
+			newError("failed to query ECH config of ", domain).Base(err).AtWarning().WriteToLog()
+			// The last ECHConfigList is used until a query succeeds.
+			configList, _, _ = echRecords.getStale(domain)
+			ttl = echQueryFailureTTL
+		}
+		echRecords.set(domain, configList, ttl)
+
+		q.access.Lock()
+		delete(q.pending, domain)
+		q.access.Unlock()
+		close(done)
+	}()
+	return done
+}
+
+var (
+	// echRecords holds the ECHConfigLists from HTTPS records by domain. Expired ones are used while the records are
+	// queried again.
+	echRecords = newECHCache()
+	echQueries = &echQuerier{
+		pending: make(map[string]chan struct{}),
+	}
+	// echRetryConfigs holds the retry configs sent by the servers that reject ECH by server name.
+	echRetryConfigs = newECHCache()
+)
+
+// getECHConfigList returns the ECHConfigList to connect to the server, which is empty if ECH is not available.
+// The retry configs of the server take precedence over the configured ones.
+func (c *Config) getECHConfigList(serverName string) []byte {
+	if configList, found := echRetryConfigs.get(serverName); found {
+		return configList
+	}
+	if len(c.EchConfigList) > 0 {
+		return c.EchConfigList
+	}
+
+	configList, expired, found := echRecords.getStale(c.EchQueryDomain)
+	if found {
+		if expired {
+			echQueries.query(c.EchQueryDomain)
+		}
+		return configList
+	}
+	done := echQueries.query(c.EchQueryDomain)
+	if !c.EchForce {
+		newError("querying ECH config of ", c.EchQueryDomain, " in the background").AtInfo().WriteToLog()
+		return nil
+	}
+	// The connection cannot be made without ECH, so it waits for the first query.
+	<-done
+	configList, _, _ = echRecords.getStale(c.EchQueryDomain)
+	return configList
+}
+
+// applyECH sets up ECH in the tls.Config, after its server name is set.
+func (c *Config) applyECH(config *tls.Config) {
+	if len(c.EchServerKeys) > 0 {
+		keys, err := parseECHServerKeys(c.EchServerKeys)
+		if err != nil {
+			newError("failed to parse ECH server keys").Base(err).AtError().WriteToLog()
+		} else {
+			config.EncryptedClientHelloKeys = keys
+			config.MinVersion = tls.VersionTLS13
+			config.VerifyConnection = func(state tls.ConnectionState) error {
+				if state.ECHAccepted {
+					newError("ECH accepted for ", state.ServerName).AtDebug().WriteToLog()
+				} else {
+					newError("ECH not used for ", state.ServerName).AtDebug().WriteToLog()
+				}
+				return nil
+			}
+		}
+	}
+
+	if len(c.EchConfigList) == 0 && c.EchQueryDomain == "" {
+		return
+	}
+
+	serverName := config.ServerName
+	configList := c.getECHConfigList(serverName)
+	if len(configList) == 0 {
+		if !c.EchForce {
+			newError("ECH is not available for ", serverName, ", connecting without ECH").AtInfo().WriteToLog()
+			return
+		}
+		newError("ECH is not available for ", serverName, ", refusing to connect without ECH").AtWarning().WriteToLog()
+		configList = emptyECHConfigList
+	}
+	config.EncryptedClientHelloConfigList = configList
+	config.MinVersion = tls.VersionTLS13
+	config.VerifyConnection = func(state tls.ConnectionState) error {
+		// A rejected handshake fails with tls.ECHRejectionError before this is called.
+		if state.ECHAccepted {
+			newError("ECH accepted by ", serverName).AtInfo().WriteToLog()
+		}
+		return nil
+	}
+}
+
+// handleECHRejection remembers the retry configs sent by the server that rejects ECH, so that the next connections
+// use them. If there are none, the server does not support ECH.
+func handleECHRejection(serverName string, err *tls.ECHRejectionError) {
+	if len(err.RetryConfigList) == 0 {
+		newError("ECH rejected by ", serverName, ", which does not support ECH").AtWarning().WriteToLog()
+	} else {
+		newError("ECH rejected by ", serverName, ", using the retry configs from the server for new connections").AtWarning().WriteToLog()
+	}
+	echRetryConfigs.set(serverName, err.RetryConfigList, echRetryTTL)
+}
+
+// queryECHConfigList returns the ECHConfigList in the HTTPS record of the domain, and how long it can be cached.
+func queryECHConfigList(domain string) ([]byte, time.Duration, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), echQueryTimeout)
+	defer cancel()
+
+	msg, err := internet.LookupRaw(ctx, domain, typeHTTPS)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, answer := range msg.Answers {
+		record, ok := answer.Body.(*dnsmessage.UnknownResource)
+		if !ok || answer.Header.Type != typeHTTPS {
+			continue
+		}
+		configList, err := parseECHParam(record.Data)
+		if err != nil {
+			return nil, 0, err
+		}
+		if len(configList) > 0 {
+			return configList, max(time.Duration(answer.Header.TTL)*time.Second, echRecordMinTTL), nil
+		}
+	}
+	return nil, 0, newError("no ECH config in HTTPS records")
+}
+
+// parseECHParam returns the ECHConfigList in the RDATA of an HTTPS record, or nil if there is none.
+func parseECHParam(data []byte) ([]byte, error) {
+	errMalformed := newError("malformed HTTPS record")
+
+	if len(data) < 2 {
+		return nil, errMalformed
+	}
+	priority := binary.BigEndian.Uint16(data)
+	data = data[2:]
+
+	// TargetName is never compressed.
+	for {
+		if len(data) == 0 {
+			return nil, errMalformed
+		}
+		length := int(data[0])
+		data = data[1:]
+		if length == 0 {
+			break
+		}
+		if length > 63 || len(data) < length {
+			return nil, errMalformed
+		}
+		data = data[length:]
+	}
+
+	// Records in AliasMode have no SvcParams.
+	if priority == 0 {
+		return nil, nil
+	}
+
+	for len(data) > 0 {
+		if len(data) < 4 {
+			return nil, errMalformed
+		}
+		key := binary.BigEndian.Uint16(data)
+		length := int(binary.BigEndian.Uint16(data[2:]))
+		data = data[4:]
+		if len(data) < length {
+			return nil, errMalformed
+		}
+		if key == svcParamECH {
+			return data[:length], nil
+		}
+		data = data[length:]
+	}
+	return nil, nil
+}
+
+// parseECHServerKeys converts the ECH key sets generated by `xray tls ech` into keys of crypto/tls.
+func parseECHServerKeys(data []byte) ([]tls.EncryptedClientHelloKey, error) {
+	keySets, err := goech.UnmarshalECHKeySetList(data)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]tls.EncryptedClientHelloKey, 0, len(keySets))
+	for _, keySet := range keySets {
+		config, err := keySet.ECHConfig.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		privateKey, err := keySet.PrivateKey.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, tls.EncryptedClientHelloKey{
+			// The ECHConfig is marshalled with a length prefix, as a list of one config.
+			Config:      config[2:],
+			PrivateKey:  privateKey,
+			SendAsRetry: true,
+		})
+	}
+	if len(keys) == 0 {
+		return nil, newError("no ECH key")
+	}
+	return keys, nil
+}
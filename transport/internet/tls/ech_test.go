@@ -0,0 +1,191 @@
+package tls_test
+
+import (
+	"context"
+	gotls "crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/OmarTariq612/goech"
+	"github.com/cloudflare/circl/hpke"
+	"github.com/xtls/xray-core/common"
+	xnet "github.com/xtls/xray-core/common/net"
+	"github.com/xtls/xray-core/common/protocol/tls/cert"
+	"github.com/xtls/xray-core/features/dns"
+	"github.com/xtls/xray-core/transport/internet"
+	. "github.com/xtls/xray-core/transport/internet/tls"
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+func generateECHKeySet() (configList []byte, keys []byte) {
+	keySet, err := goech.GenerateECHKeySet(0, "public.example.com", hpke.KEM_X25519_HKDF_SHA256)
+	common.Must(err)
+	configList, err = keySet.ECHConfig.MarshalBinary()
+	common.Must(err)
+	keys, err = keySet.MarshalBinary()
+	common.Must(err)
+	return
+}
+
+func handshakeECH(serverConfig *Config, clientConfig *Config) (gotls.ConnectionState, error) {
+	serverConn, clientConn := net.Pipe()
+	defer serverConn.Close()
+	defer clientConn.Close()
+
+	server := Server(serverConn, serverConfig.GetTLSConfig()).(*Conn)
+	go io.Copy(io.Discard, server)
+
+	client := Client(clientConn, clientConfig.GetTLSConfig()).(*Conn)
+	err := client.Handshake()
+	return client.ConnectionState(), err
+}
+
+var echCA = cert.MustGenerate(nil, cert.Authority(true), cert.KeyUsage(x509.KeyUsageCertSign))
+
+func newECHServerConfig(keys []byte) *Config {
+	return &Config{
+		Certificate:   []*Certificate{ParseCertificate(cert.MustGenerate(echCA, cert.DNSNames("public.example.com", "ech.example.com", "retry.example.com", "force.example.com")))},
+		EchServerKeys: keys,
+	}
+}
+
+// newECHClientConfig returns a client config which verifies the server, as the certificate for the public name
+// must be valid for the client to accept the retry configs.
+func newECHClientConfig(serverName string) *Config {
+	ca := ParseCertificate(echCA)
+	ca.Usage = Certificate_AUTHORITY_VERIFY
+	return &Config{
+		Certificate:       []*Certificate{ca},
+		DisableSystemRoot: true,
+		ServerName:        serverName,
+	}
+}
+
+func TestECH(t *testing.T) {
+	configList, keys := generateECHKeySet()
+
+	clientConfig := newECHClientConfig("ech.example.com")
+	clientConfig.EchConfigList = configList
+
+	state, err := handshakeECH(newECHServerConfig(keys), clientConfig)
+	common.Must(err)
+	if !state.ECHAccepted {
+		t.Error("ECH not accepted")
+	}
+	if state.ServerName != "ech.example.com" {
+		t.Error("server name: ", state.ServerName)
+	}
+}
+
+func TestECHRetryConfigs(t *testing.T) {
+	_, keys := generateECHKeySet()
+	staleConfigList, _ := generateECHKeySet()
+
+	serverConfig := newECHServerConfig(keys)
+	clientConfig := newECHClientConfig("retry.example.com")
+	clientConfig.EchConfigList = staleConfigList
+
+	_, err := handshakeECH(serverConfig, clientConfig)
+	if _, ok := err.(*gotls.ECHRejectionError); !ok {
+		t.Fatal("expected ECH rejection, but got ", err)
+	}
+
+	state, err := handshakeECH(serverConfig, clientConfig)
+	common.Must(err)
+	if !state.ECHAccepted {
+		t.Error("ECH not accepted with retry configs")
+	}
+}
+
+func TestECHUnavailable(t *testing.T) {
+	_, keys := generateECHKeySet()
+
+	serverConfig := newECHServerConfig(keys)
+	clientConfig := newECHClientConfig("force.example.com")
+	clientConfig.EchQueryDomain = "force.example.com"
+
+	state, err := handshakeECH(serverConfig, clientConfig)
+	common.Must(err)
+	if state.ECHAccepted {
+		t.Error("ECH accepted without config")
+	}
+
+	clientConfig.EchForce = true
+	if _, err := handshakeECH(serverConfig, clientConfig); err == nil {
+		t.Error("connected without ECH while forced")
+	}
+}
+
+// httpsDNS answers HTTPS queries with a record holding the ECHConfigList, once it is released.
+type httpsDNS struct {
+	configList []byte
+	release    chan struct{}
+}
+
+func (*httpsDNS) Type() interface{} {
+	return dns.ClientType()
+}
+
+func (*httpsDNS) Start() error {
+	return nil
+}
+
+func (*httpsDNS) Close() error {
+	return nil
+}
+
+func (*httpsDNS) LookupIP(domain string, option dns.IPOption) ([]xnet.IP, error) {
+	return nil, dns.ErrEmptyResponse
+}
+
+func (d *httpsDNS) LookupRaw(ctx context.Context, domain string, qType dnsmessage.Type) (*dnsmessage.Message, error) {
+	<-d.release
+	// SvcPriority 1, the root TargetName, and the ech SvcParam.
+	data := []byte{0, 1, 0, 0, 5}
+	data = binary.BigEndian.AppendUint16(data, uint16(len(d.configList)))
+	data = append(data, d.configList...)
+	return &dnsmessage.Message{
+		Answers: []dnsmessage.Resource{{
+			Header: dnsmessage.ResourceHeader{Type: qType, Class: dnsmessage.ClassINET, TTL: 300},
+			Body:   &dnsmessage.UnknownResource{Type: qType, Data: data},
+		}},
+	}, nil
+}
+
+func TestECHQuery(t *testing.T) {
+	configList, keys := generateECHKeySet()
+	d := &httpsDNS{
+		configList: configList,
+		release:    make(chan struct{}),
+	}
+	internet.InitSystemDialer(d, nil)
+	defer internet.InitSystemDialer(nil, nil)
+
+	serverConfig := newECHServerConfig(keys)
+	clientConfig := newECHClientConfig("ech.example.com")
+	clientConfig.EchQueryDomain = "query.example.com"
+
+	start := time.Now()
+	state, err := handshakeECH(serverConfig, clientConfig)
+	common.Must(err)
+	if state.ECHAccepted {
+		t.Error("ECH accepted before the HTTPS record is available")
+	}
+	if time.Since(start) > time.Second {
+		t.Error("handshake waited for the HTTPS record")
+	}
+
+	close(d.release)
+	for i := 0; i < 50 && !state.ECHAccepted; i++ {
+		time.Sleep(100 * time.Millisecond)
+		state, err = handshakeECH(serverConfig, clientConfig)
+		common.Must(err)
+	}
+	if !state.ECHAccepted {
+		t.Error("ECH not accepted with the config from the HTTPS record")
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"crypto/rand"
 	"crypto/tls"
+	"errors"
 	"math/big"
+	"sync"
 	"time"
 
 	utls "github.com/refraction-networking/utls"
@@ -26,6 +28,10 @@ var _ Interface = (*Conn)(nil)
 
 type Conn struct {
 	*tls.Conn
+
+	// echServerName is the server name to remember the retry configs for if ECH is rejected.
+	echServerName string
+	echRejected   sync.Once
 }
 
 const tlsCloseTimeout = 250 * time.Millisecond
@@ -38,6 +44,35 @@ func (c *Conn) Close() error {
 	return c.Conn.Close()
 }
 
+func (c *Conn) HandshakeContext(ctx context.Context) error {
+	return c.checkECH(c.Conn.HandshakeContext(ctx))
+}
+
+func (c *Conn) Handshake() error {
+	return c.HandshakeContext(context.Background())
+}
+
+func (c *Conn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	return n, c.checkECH(err)
+}
+
+func (c *Conn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	return n, c.checkECH(err)
+}
+
+// checkECH handles the handshake error caused by the rejection of ECH, which Read and Write return as well.
+func (c *Conn) checkECH(err error) error {
+	var rejection *tls.ECHRejectionError
+	if c.echServerName != "" && errors.As(err, &rejection) {
+		c.echRejected.Do(func() {
+			handleECHRejection(c.echServerName, rejection)
+		})
+	}
+	return err
+}
+
 func (c *Conn) WriteMultiBuffer(mb buf.MultiBuffer) error {
 	mb = buf.Compact(mb)
 	mb, err := buf.WriteMultiBuffer(c, mb)
@@ -61,10 +96,24 @@ func (c *Conn) NegotiatedProtocol() string {
 	return state.NegotiatedProtocol
 }
 
+// VerifiedPeerSubject returns the subject of the verified certificate of the peer after the handshake. It is empty if
+// the peer has sent no certificate, or the certificate is not verified.
+func (c *Conn) VerifiedPeerSubject() string {
+	chains := c.ConnectionState().VerifiedChains
+	if len(chains) == 0 || len(chains[0]) == 0 {
+		return ""
+	}
+	return chains[0][0].Subject.String()
+}
+
 // Client initiates a TLS client handshake on the given connection.
 func Client(c net.Conn, config *tls.Config) net.Conn {
 	tlsConn := tls.Client(c, config)
-	return &Conn{Conn: tlsConn}
+	conn := &Conn{Conn: tlsConn}
+	if config.EncryptedClientHelloConfigList != nil {
+		conn.echServerName = config.ServerName
+	}
+	return conn
 }
 
 // Server initiates a TLS server handshake on the given connection.
@@ -136,13 +185,35 @@ func UClient(c net.Conn, config *tls.Config, fingerprint *utls.ClientHelloID) ne
 }
 
 func copyConfig(c *tls.Config) *utls.Config {
-	return &utls.Config{
+	config := &utls.Config{
 		RootCAs:               c.RootCAs,
 		ServerName:            c.ServerName,
 		InsecureSkipVerify:    c.InsecureSkipVerify,
 		VerifyPeerCertificate: c.VerifyPeerCertificate,
 		KeyLogWriter:          c.KeyLogWriter,
 	}
+	if c.GetClientCertificate != nil {
+		config.GetClientCertificate = func(info *utls.CertificateRequestInfo) (*utls.Certificate, error) {
+			schemes := make([]tls.SignatureScheme, len(info.SignatureSchemes))
+			for i, scheme := range info.SignatureSchemes {
+				schemes[i] = tls.SignatureScheme(scheme)
+			}
+			cert, err := c.GetClientCertificate(&tls.CertificateRequestInfo{
+				AcceptableCAs:    info.AcceptableCAs,
+				SignatureSchemes: schemes,
+				Version:          info.Version,
+			})
+			if err != nil {
+				return nil, err
+			}
+			return &utls.Certificate{
+				Certificate: cert.Certificate,
+				PrivateKey:  cert.PrivateKey,
+				Leaf:        cert.Leaf,
+			}, nil
+		}
+	}
+	return config
 }
 
 func init() {
@@ -93,28 +93,38 @@ func dialWebSocket(ctx context.Context, dest net.Destination, streamSettings *in
 		protocol = "wss"
 		tlsConfig := config.GetTLSConfig(tls.WithDestination(dest), tls.WithNextProto("http/1.1"))
 		dialer.TLSClientConfig = tlsConfig
-		if fingerprint := tls.GetFingerprint(config.Fingerprint); fingerprint != nil {
-			dialer.NetDialTLSContext = func(_ context.Context, _, addr string) (gonet.Conn, error) {
-				// Like the NetDial in the dialer
-				pconn, err := internet.DialSystem(ctx, dest, streamSettings.SocketSettings)
-				if err != nil {
-					newError("failed to dial to " + addr).Base(err).AtError().WriteToLog()
-					return nil, err
-				}
-				// TLS and apply the handshake
-				cn := tls.UClient(pconn, tlsConfig, fingerprint).(*tls.UConn)
-				if err := cn.WebsocketHandshakeContext(ctx); err != nil {
+		fingerprint := tls.GetFingerprint(config.Fingerprint)
+		// The handshake is done here rather than by the dialer, so that the rejection of ECH is handled.
+		dialer.NetDialTLSContext = func(_ context.Context, _, addr string) (gonet.Conn, error) {
+			// Like the NetDial in the dialer
+			pconn, err := internet.DialSystem(ctx, dest, streamSettings.SocketSettings)
+			if err != nil {
+				newError("failed to dial to " + addr).Base(err).AtError().WriteToLog()
+				return nil, err
+			}
+			// TLS and apply the handshake
+			var cn tls.Interface
+			if fingerprint != nil {
+				uConn := tls.UClient(pconn, tlsConfig, fingerprint).(*tls.UConn)
+				cn = uConn
+				err = uConn.WebsocketHandshakeContext(ctx)
+			} else {
+				cn = tls.Client(pconn, tlsConfig).(*tls.Conn)
+				err = cn.HandshakeContext(ctx)
+			}
+			if err != nil {
+				newError("failed to dial to " + addr).Base(err).AtError().WriteToLog()
+				pconn.Close()
+				return nil, err
+			}
+			if !tlsConfig.InsecureSkipVerify {
+				if err := cn.VerifyHostname(tlsConfig.ServerName); err != nil {
 					newError("failed to dial to " + addr).Base(err).AtError().WriteToLog()
+					pconn.Close()
 					return nil, err
 				}
-				if !tlsConfig.InsecureSkipVerify {
-					if err := cn.VerifyHostname(tlsConfig.ServerName); err != nil {
-						newError("failed to dial to " + addr).Base(err).AtError().WriteToLog()
-						return nil, err
-					}
-				}
-				return cn, nil
 			}
+			return cn, nil
 		}
 	}
 